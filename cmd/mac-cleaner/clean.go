@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/history"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+	"github.com/rahulvramesh/cleanWithCli/pkg/cleaner"
+)
+
+// runClean scans and cleans without the TUI, as scheduled runs do. Only
+// low-risk categories are cleaned, plus the medium-risk ones named with
+// -include. Output is timestamped for the schedule log.
+func runClean(args []string) int {
+	fs := flag.NewFlagSet("clean", flag.ExitOnError)
+	mode := fs.String("mode", string(cleaner.ModeQuick), "scan to clean: quick, full or dev")
+	dryRun := fs.Bool("dry-run", false, "report what would be cleaned without deleting anything")
+	skip := fs.String("skip", "", "comma-separated scanners to skip (see the scanners subcommand)")
+	include := fs.String("include", "", "comma-separated medium-risk scanners to clean too, e.g. node-modules")
+	deleteRate := fs.String("delete-rate", "", deleteRateUsage)
+	fs.Parse(args)
+
+	switch cleaner.Mode(*mode) {
+	case cleaner.ModeQuick, cleaner.ModeFull, cleaner.ModeDev:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown mode %q\n", *mode)
+		return 2
+	}
+	disabled, err := parseScanners(*skip)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	included, err := parseScanners(*include)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	remove, err := parseDeleteRate(*deleteRate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	freed, failures, err := headlessClean(cleaner.Mode(*mode), disabled, included, remove, *dryRun)
+	if err != nil {
+		logf("Error: %v", err)
+		return 1
+	}
+	verb := "Freed"
+	if *dryRun {
+		verb = "Would free"
+	}
+	logf("%s %s", verb, humanize.Bytes(uint64(freed)))
+
+	if failures > 0 {
+		return 1
+	}
+	return 0
+}
+
+// logf prints a timestamped line for the schedule and watch logs
+func logf(format string, args ...any) {
+	fmt.Printf("%s "+format+"\n", append([]any{time.Now().Format(time.DateTime)}, args...)...)
+}
+
+// headlessClean scans mode and cleans the low-risk categories, and the
+// medium-risk ones of the included scanners, logging each one. Nobody
+// reviews what an unattended clean deletes, so report-only and high-risk
+// categories are always skipped. Deleted items go through remove, so its
+// rate limits hold. It returns the bytes freed and the number of paths that
+// couldn't be removed.
+func headlessClean(mode cleaner.Mode, disabled, included []string, remove func(string) error, dryRun bool) (freed int64, failures int, err error) {
+	logf("Starting %s clean", mode)
+	report, err := cleaner.Scan(context.Background(), cleaner.Options{Mode: mode, Disabled: disabled, Remove: remove})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	categories := make([]string, 0, len(report.Results))
+	for category := range report.Results {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	strategy := func(result *cleaner.ScanResult) cleaner.Strategy { return cleaner.ResultStrategy(result) }
+	if dryRun {
+		strategy = func(*cleaner.ScanResult) cleaner.Strategy { return cleaner.DryRun }
+	}
+
+	scanners := make(map[string]string)
+	for _, info := range cleaner.Scanners() {
+		scanners[info.Name] = info.Category
+	}
+	optedIn := make(map[string]bool)
+	for _, name := range included {
+		optedIn[scanners[name]] = true
+	}
+
+	now := time.Now()
+	var records []audit.Record
+	for _, category := range categories {
+		result := report.Results[category]
+		risk := cleaner.CategoryRisk(category)
+		if result.Advisory || risk >= types.RiskHigh || (risk > types.RiskLow && !optedIn[category]) {
+			logf("Skipping %s (%s)", category, humanize.Bytes(uint64(result.Total)))
+			continue
+		}
+		cr := cleaner.Clean(result.Items, strategy(result))
+		freed += cr.Freed
+		failures += len(cr.Errors)
+		logf("Cleaned %s: %d items, %s", category, len(cr.Removed), humanize.Bytes(uint64(cr.Freed)))
+		for _, err := range cr.Errors {
+			logf("  %v", err)
+		}
+		records = append(records, audit.CleanRecords(now, category, result.Items, cr.Removed, cr.Errors)...)
+	}
+
+	if !dryRun {
+		if err := audit.Append(audit.DefaultPath(), records...); err != nil {
+			logf("Error writing deletion log: %v", err)
+		}
+		saveCleanHistory(string(mode), report)
+	}
+	return freed, failures, nil
+}
+
+// saveCleanHistory records the scan and the free space after the clean, as
+// the TUI does
+func saveCleanHistory(mode string, report *cleaner.Report) {
+	path := history.DefaultPath()
+	if path == "" {
+		return
+	}
+	home, _ := os.UserHomeDir()
+
+	entry := history.NewEntry(mode, report.Results, report.TotalSize)
+	sample := history.Entry{Time: time.Now(), Kind: history.KindClean}
+	sample.FreeBytes, sample.DiskBytes, _ = utils.DiskSpace(home)
+	history.Append(path, entry)
+	history.Append(path, sample)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/daemon"
+	"github.com/rahulvramesh/cleanWithCli/internal/plugin"
+	"github.com/rahulvramesh/cleanWithCli/internal/sizecache"
+	"github.com/rahulvramesh/cleanWithCli/pkg/cleaner"
+)
+
+// runDaemon serves the automation API on a Unix socket until interrupted
+func runDaemon(args []string) int {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	socket := fs.String("socket", daemon.DefaultSocket(), "Unix socket to listen on")
+	skip := fs.String("skip", "", "comma-separated scanners to skip (see the scanners subcommand)")
+	warm := fs.Duration("warm", 10*time.Minute, "idle time before refreshing cached directory sizes in the background (0 disables)")
+	deleteRate := fs.String("delete-rate", "", deleteRateUsage)
+	fs.Parse(args)
+
+	disabled, err := parseScanners(*skip)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	remove, err := parseDeleteRate(*deleteRate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	srv := &daemon.Server{
+		Scan:      cleaner.Options{Disabled: disabled, Sizes: cleaner.OpenSizeCache(sizecache.DefaultPath()), Remove: remove},
+		AuditFile: audit.DefaultPath(),
+		WarmAfter: *warm,
+	}
+	plugins, err := plugin.Discover(plugin.DefaultDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: loading plugins: %v\n", err)
+	}
+	for _, p := range plugins {
+		srv.Scan.Extra = append(srv.Scan.Extra, p.Scan)
+	}
+
+	l, err := daemon.Listen(*socket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer os.Remove(*socket)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", *socket)
+	if err := srv.Serve(ctx, l); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/changelog"
+	"github.com/rahulvramesh/cleanWithCli/internal/grouping"
+	"github.com/rahulvramesh/cleanWithCli/internal/history"
+	"github.com/rahulvramesh/cleanWithCli/internal/plugin"
+	"github.com/rahulvramesh/cleanWithCli/internal/schedule"
+	"github.com/rahulvramesh/cleanWithCli/internal/session"
+	"github.com/rahulvramesh/cleanWithCli/internal/sizecache"
+	"github.com/rahulvramesh/cleanWithCli/internal/status"
+	"github.com/rahulvramesh/cleanWithCli/internal/ui"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+	"github.com/rahulvramesh/cleanWithCli/pkg/cleaner"
+)
+
+// Set at build time via -ldflags
+var (
+	version   = "dev"
+	buildTime = "unknown"
+	gitCommit = "unknown"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "status":
+			os.Exit(runStatus(os.Args[2:]))
+		case "daemon":
+			os.Exit(runDaemon(os.Args[2:]))
+		case "plugin":
+			os.Exit(runPlugin(os.Args[2:]))
+		case "clean":
+			os.Exit(runClean(os.Args[2:]))
+		case "schedule":
+			os.Exit(runSchedule(os.Args[2:]))
+		case "watch":
+			os.Exit(runWatch(os.Args[2:]))
+		case "scanners":
+			listScanners()
+			return
+		case "version":
+			fmt.Printf("mac-cleaner %s (commit %s, built %s)\n", version, gitCommit, buildTime)
+			return
+		}
+	}
+
+	writeStatus := flag.Bool("status", false, "publish scan state for the status subcommand (e.g. tmux status-right)")
+	statusFile := flag.String("status-file", status.DefaultPath(), "status file written when -status is set")
+	plain := flag.Bool("plain", false, "print a plain-text scan report instead of starting the TUI")
+	record := flag.String("record", "", "record the session (events and timings) to this file")
+	replay := flag.String("replay", "", "replay a recorded session file without scanning or deleting anything")
+	deleteRate := flag.String("delete-rate", "", deleteRateUsage)
+	skip := flag.String("skip", "", "comma-separated scanners to skip, e.g. downloads,docker (see the scanners subcommand)")
+	roots := flag.String("roots", "", "comma-separated folders to clean of .DS_Store and AppleDouble files, e.g. /Volumes/USB (default: home folder and mounted volumes)")
+	installerAge := flag.Int("installer-age", cleaner.DefaultInstallerAge, "days before disk images and packages in Downloads and on the Desktop are listed as Installers")
+	screenshotAge := flag.Int("screenshot-age", cleaner.DefaultScreenshotAge, "days before screenshots on the Desktop are listed as Screenshots")
+	screenshotArchive := flag.String("screenshot-archive", "", "folder to move old screenshots into, e.g. ~/Pictures/Screenshots (default: the Trash)")
+	demo := flag.Bool("demo", false, "")
+	flag.Usage = usage
+	flag.Parse()
+
+	deleteRates, err := utils.ParseDeleteRates(*deleteRate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	disabled, err := parseScanners(*skip)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	hygieneRoots, err := parseRoots(*roots)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	// The archive folder is created when screenshots are first moved there
+	if *screenshotArchive != "" {
+		if *screenshotArchive, err = filepath.Abs(*screenshotArchive); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	plugins, err := plugin.Discover(plugin.DefaultDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: loading plugins: %v\n", err)
+	}
+
+	groups, err := grouping.Load(grouping.DefaultPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring category rules: %v\n", err)
+	}
+
+	scheduled, err := schedule.Load(schedule.DefaultPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: reading schedule: %v\n", err)
+	}
+
+	if ui.ColorDisabled() {
+		ui.DisableColor()
+	}
+
+	// Piped or captured output gets a readable report rather than a TUI
+	if *plain || !ui.StdoutIsTerminal() {
+		if err := ui.RunPlain(os.Stdout, ui.Options{Plugins: plugins, Disabled: disabled, Roots: hygieneRoots, InstallerAge: *installerAge, ScreenshotAge: *screenshotAge, ScreenshotArchive: *screenshotArchive, Grouping: groups}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	opts := ui.Options{
+		Plain:             ui.ColorDisabled(),
+		Demo:              *demo,
+		HistoryFile:       history.DefaultPath(),
+		AuditFile:         audit.DefaultPath(),
+		DeleteRates:       deleteRates,
+		Plugins:           plugins,
+		Disabled:          disabled,
+		Roots:             hygieneRoots,
+		InstallerAge:      *installerAge,
+		ScreenshotAge:     *screenshotAge,
+		ScreenshotArchive: *screenshotArchive,
+		Grouping:          groups,
+		Schedule:          scheduled,
+		Sizes:             sizecache.Open(sizecache.DefaultPath()),
+	}
+	if *writeStatus {
+		opts.StatusFile = *statusFile
+	}
+	if !*demo && *replay == "" {
+		opts.WhatsNew = whatsNew()
+	}
+
+	var model tea.Model = ui.InitialModel(opts)
+	switch {
+	case *replay != "":
+		f, err := os.Open(*replay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		player, err := session.Load(model, f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		model = player
+	case *record != "":
+		f, err := os.Create(*record)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		model = session.NewRecorder(model, f)
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithReportFocus())
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// whatsNew returns the changes since the last version the user ran, once.
+// First runs and development builds show nothing.
+func whatsNew() []changelog.Section {
+	path := changelog.SeenPath()
+	if path == "" || version == "dev" {
+		return nil
+	}
+	last := changelog.LastSeen(path)
+	if last == version {
+		return nil
+	}
+	changelog.MarkSeen(path, version)
+	if last == "" {
+		return nil
+	}
+	return changelog.WhatsNew(last, version)
+}
+
+// deleteRateUsage is the help of the -delete-rate flag every command that
+// deletes takes
+const deleteRateUsage = "per mount type deletion limits in ops/sec, e.g. nfs=100,smbfs=5 (0 disables)"
+
+// parseDeleteRate returns the remover for a -delete-rate value, limited by
+// the default rates for network mounts unless the value overrides them
+func parseDeleteRate(value string) (func(string) error, error) {
+	rates, err := utils.ParseDeleteRates(value)
+	if err != nil {
+		return nil, err
+	}
+	return utils.RateLimitedRemover(rates), nil
+}
+
+// parseScanners splits a list of scanner names, as -skip and -include take,
+// rejecting unknown ones
+func parseScanners(value string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, info := range cleaner.Scanners() {
+		known[info.Name] = true
+	}
+
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("unknown scanner %q; run %s scanners for the list", name, filepath.Base(os.Args[0]))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// parseRoots splits the -roots flag into absolute paths of existing folders
+func parseRoots(value string) ([]string, error) {
+	var roots []string
+	for _, root := range strings.Split(value, ",") {
+		root = strings.TrimSpace(root)
+		if root == "" {
+			continue
+		}
+		root, err := filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("root %s is not a folder", root)
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// listScanners prints the built-in scanners
+func listScanners() {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCATEGORY\tSCANS\tRISK")
+	for _, info := range cleaner.Scanners() {
+		modes := make([]string, len(info.Modes))
+		for i, mode := range info.Modes {
+			modes[i] = string(mode)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, info.Category, strings.Join(modes, ","), info.Risk)
+	}
+	tw.Flush()
+}
+
+// hiddenFlags are development flags left out of the usage message
+var hiddenFlags = map[string]bool{"demo": true}
+
+// usage prints the flag usage, skipping hidden flags
+func usage() {
+	out := flag.CommandLine.Output()
+	name := filepath.Base(os.Args[0])
+	fmt.Fprintf(out, "Usage: %s [flags]\n       %s status [-json] [-file path]\n       %s scanners\n       %s plugin list | install | remove | enable | disable\n       %s daemon [-socket path] [-skip names] [-delete-rate rates]\n       %s clean [-mode quick|full|dev] [-dry-run] [-skip names] [-include names] [-delete-rate rates]\n       %s schedule install | uninstall | status\n       %s watch [-below 20GB] [-action notify|clean] [-interval 5m] [-delete-rate rates]\n\nFlags:\n", name, name, name, name, name, name, name, name)
+	flag.VisitAll(func(f *flag.Flag) {
+		if hiddenFlags[f.Name] {
+			return
+		}
+		arg, usage := flag.UnquoteUsage(f)
+		if arg != "" {
+			arg = " " + arg
+		}
+		fmt.Fprintf(out, "  -%s%s\n    \t%s\n", f.Name, arg, usage)
+	})
+}
+
+// runStatus prints the last status written by a running instance
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	file := fs.String("file", status.DefaultPath(), "status file to read")
+	asJSON := fs.Bool("json", false, "print the raw status as JSON")
+	fs.Parse(args)
+
+	st, err := status.Read(*file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Print nothing so tmux status lines stay empty
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		json.NewEncoder(os.Stdout).Encode(st)
+		return 0
+	}
+	fmt.Println(status.FormatTmux(st))
+	return 0
+}
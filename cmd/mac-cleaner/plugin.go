@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/plugin"
+)
+
+// runPlugin manages external scanner plugins:
+//
+//	plugin list
+//	plugin install <name>
+//	plugin remove <name>
+//	plugin enable <name>
+//	plugin disable <name>
+func runPlugin(args []string) int {
+	dir := plugin.DefaultDir()
+	name := filepath.Base(os.Args[0])
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s plugin list | install <name> | remove <name> | enable <name> | disable <name>\n", name)
+		return 2
+	}
+
+	var err error
+	switch cmd := args[0]; {
+	case cmd == "list" && len(args) == 1:
+		err = listPlugins(dir)
+	case len(args) != 2:
+		fmt.Fprintf(os.Stderr, "Usage: %s plugin %s <name>\n", name, cmd)
+		return 2
+	case cmd == "install":
+		err = installPlugin(dir, args[1])
+	case cmd == "remove":
+		err = plugin.Uninstall(dir, args[1])
+	case cmd == "enable", cmd == "disable":
+		err = plugin.SetEnabled(dir, args[1], cmd == "enable")
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown plugin command %q\n", cmd)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// listPlugins prints the plugins in the index and those installed locally.
+// An unreachable index still lists the installed plugins.
+func listPlugins(dir string) error {
+	installed, err := plugin.ListInstalled(dir)
+	if err != nil {
+		return err
+	}
+	state := make(map[string]string, len(installed))
+	for _, p := range installed {
+		state[p.Name] = "disabled"
+		if p.Enabled {
+			state[p.Name] = "enabled"
+		}
+	}
+
+	idx, err := plugin.FetchIndex(plugin.IndexLocation())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: reading plugin index: %v\n", err)
+		idx = &plugin.Index{}
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVERSION\tSTATUS\tDESCRIPTION")
+	for _, l := range idx.Plugins {
+		status := state[l.Name]
+		if status == "" {
+			status = "available"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", l.Name, l.Version, status, l.Description)
+	}
+	for _, p := range installed {
+		if _, ok := idx.Find(p.Name); !ok {
+			fmt.Fprintf(tw, "%s\t-\t%s\t(not in the index)\n", p.Name, state[p.Name])
+		}
+	}
+	return tw.Flush()
+}
+
+// installPlugin downloads a plugin listed in the index
+func installPlugin(dir, name string) error {
+	idx, err := plugin.FetchIndex(plugin.IndexLocation())
+	if err != nil {
+		return err
+	}
+	l, ok := idx.Find(name)
+	if !ok {
+		return fmt.Errorf("plugin %s is not in the index", name)
+	}
+	if err := plugin.Install(dir, l); err != nil {
+		return err
+	}
+	fmt.Printf("Installed %s %s into %s\n", l.Name, l.Version, dir)
+	return nil
+}
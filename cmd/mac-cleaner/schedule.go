@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/schedule"
+)
+
+// runSchedule installs, removes or shows the launchd agent running
+// scheduled cleans
+func runSchedule(args []string) int {
+	if len(args) == 0 {
+		args = []string{"status"}
+	}
+	switch args[0] {
+	case "install":
+		return installSchedule(args[1:])
+	case "uninstall":
+		if err := schedule.Uninstall(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println("Removed the scheduled clean")
+		return 0
+	case "status":
+		return scheduleStatus()
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s schedule install [-mode quick] [-include scanners] [-every weekly] [-weekday monday] [-at 10:00] | uninstall | status\n", filepath.Base(os.Args[0]))
+		return 2
+	}
+}
+
+// installSchedule writes and loads the agent for the given flags
+func installSchedule(args []string) int {
+	fs := flag.NewFlagSet("schedule install", flag.ExitOnError)
+	mode := fs.String("mode", "quick", "scan to clean: quick, full or dev")
+	every := fs.String("every", schedule.Weekly, "how often to clean: daily or weekly")
+	weekday := fs.String("weekday", "monday", "day of weekly cleans")
+	at := fs.String("at", "10:00", "time of day to clean, HH:MM")
+	include := fs.String("include", "", "comma-separated medium-risk scanners to clean too, e.g. node-modules")
+	fs.Parse(args)
+
+	c := schedule.Config{Mode: *mode, Interval: *every}
+	included, err := parseScanners(*include)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	c.Include = included
+	day, ok := parseWeekday(*weekday)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown weekday %q\n", *weekday)
+		return 2
+	}
+	c.Weekday = day
+	if _, err := fmt.Sscanf(*at, "%d:%d", &c.Hour, &c.Minute); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid time %q, want HH:MM\n", *at)
+		return 2
+	}
+	if err := c.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := schedule.Install(c, exe); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Scheduled %s\nNext run: %s\nLog: %s\n", c, c.Next(time.Now()).Format("Mon 2 Jan 15:04"), schedule.LogPath())
+	return 0
+}
+
+// scheduleStatus prints the installed schedule and its next run
+func scheduleStatus() int {
+	c, err := schedule.Load(schedule.DefaultPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if c == nil {
+		fmt.Println("No scheduled clean")
+		return 0
+	}
+	fmt.Printf("Scheduled %s\nNext run: %s\nLog: %s\n", c, c.Next(time.Now()).Format("Mon 2 Jan 15:04"), schedule.LogPath())
+	return 0
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(name, d.String()) || strings.EqualFold(name, d.String()[:3]) {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/notify"
+	"github.com/rahulvramesh/cleanWithCli/internal/watch"
+	"github.com/rahulvramesh/cleanWithCli/pkg/cleaner"
+)
+
+// runWatch monitors free space on the home volume until interrupted,
+// notifying or running a safe clean when it drops below a threshold
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	below := fs.String("below", "20GB", "free space that triggers the action, e.g. 20GB")
+	action := fs.String("action", "notify", "what to do when space is low: notify, or clean the low-risk categories")
+	mode := fs.String("mode", string(cleaner.ModeQuick), "scan cleaned by -action clean: quick, full or dev")
+	interval := fs.Duration("interval", 5*time.Minute, "how often to check free space")
+	cooldown := fs.Duration("cooldown", 6*time.Hour, "how long to wait before acting again while space stays low")
+	skip := fs.String("skip", "", "comma-separated scanners to skip (see the scanners subcommand)")
+	deleteRate := fs.String("delete-rate", "", deleteRateUsage)
+	fs.Parse(args)
+
+	threshold, err := humanize.ParseBytes(*below)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -below %q: %v\n", *below, err)
+		return 2
+	}
+	if *action != "notify" && *action != "clean" {
+		fmt.Fprintf(os.Stderr, "Error: -action must be notify or clean, not %q\n", *action)
+		return 2
+	}
+	switch cleaner.Mode(*mode) {
+	case cleaner.ModeQuick, cleaner.ModeFull, cleaner.ModeDev:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown mode %q\n", *mode)
+		return 2
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -interval must be positive\n")
+		return 2
+	}
+	disabled, err := parseScanners(*skip)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	remove, err := parseDeleteRate(*deleteRate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	w := &watch.Watcher{
+		Path:      home,
+		Threshold: int64(threshold),
+		Interval:  *interval,
+		Cooldown:  *cooldown,
+		OnLow: func(free int64) {
+			logf("Free space is %s, below %s", humanize.Bytes(uint64(free)), humanize.Bytes(threshold))
+			if *action == "notify" {
+				sendNotification(fmt.Sprintf("Only %s free. Run mac-cleaner to reclaim space.", humanize.Bytes(uint64(free))))
+				return
+			}
+			// Nobody chose what a trigger deletes, so only low-risk categories go
+			freed, _, err := headlessClean(cleaner.Mode(*mode), disabled, nil, remove, false)
+			if err != nil {
+				logf("Error: %v", err)
+				return
+			}
+			logf("Freed %s", humanize.Bytes(uint64(freed)))
+			sendNotification(fmt.Sprintf("Disk space was low (%s free); a %s clean freed %s.", humanize.Bytes(uint64(free)), *mode, humanize.Bytes(uint64(freed))))
+		},
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logf("Watching free space on %s, acting below %s (%s)", home, humanize.Bytes(threshold), *action)
+	w.Run(ctx)
+	return 0
+}
+
+// sendNotification shows a notification, logging rather than failing when it can't
+func sendNotification(message string) {
+	if err := notify.Send("mac-cleaner", message); err != nil {
+		logf("Notification failed: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// Outcomes of a deletion
+const (
+	OutcomeDeleted = "deleted"
+	OutcomeMissing = "missing" // Already gone when the clean ran
+	OutcomeFailed  = "failed"
+)
+
+// Record describes a single attempted deletion
+type Record struct {
+	Time     time.Time `json:"time"`
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Category string    `json:"category,omitempty"`
+	Outcome  string    `json:"outcome"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// NewRecord builds the record for one attempted deletion from its error
+func NewRecord(now time.Time, path string, size int64, category string, err error) Record {
+	r := Record{Time: now, Path: path, Size: size, Category: category, Outcome: OutcomeDeleted}
+	switch {
+	case errors.Is(err, types.ErrNotFound):
+		r.Outcome = OutcomeMissing
+	case err != nil:
+		r.Outcome = OutcomeFailed
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// CleanRecords builds the records for a clean of items from one category,
+// given the paths the clean removed and its per-path errors. Items skipped
+// because a parent went first get no record of their own.
+func CleanRecords(now time.Time, category string, items []types.FileItem, removed []string, errs []error) []Record {
+	failed := make(map[string]error)
+	for _, err := range errs {
+		var pathErr *types.PathError
+		if errors.As(err, &pathErr) {
+			failed[pathErr.Path] = err
+		}
+	}
+	gone := make(map[string]bool, len(removed))
+	for _, path := range removed {
+		gone[path] = true
+	}
+
+	var records []Record
+	for _, item := range items {
+		if err, ok := failed[item.Path]; ok || gone[item.Path] {
+			records = append(records, NewRecord(now, item.Path, item.Size, category, err))
+		}
+	}
+	return records
+}
+
+// DefaultPath returns the default location of the audit log
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "cleanwithcli", "deletions.jsonl")
+}
+
+// Append adds records to the end of the audit log at path. The file is only
+// ever opened for appending, so earlier records are never rewritten.
+func Append(path string, records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(f)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Load reads all records from the audit log at path, oldest first. A missing
+// file is an empty log; unreadable lines are skipped.
+func Load(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,108 @@
+// Package changelog parses the embedded changelog for the "What's new"
+// screen shown after an update.
+package changelog
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed CHANGELOG.md
+var raw string
+
+// Group is a titled list of changes within a release, e.g. "Safety"
+type Group struct {
+	Title string
+	Items []string
+}
+
+// Section holds the changes of one release
+type Section struct {
+	Version string
+	Groups  []Group
+}
+
+// Parse reads "## <version>" sections, "### <title>" groups and "- " items
+// from a changelog, newest release first
+func Parse(text string) []Section {
+	var sections []Section
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "## "):
+			sections = append(sections, Section{Version: strings.TrimSpace(line[3:])})
+		case len(sections) == 0:
+			continue
+		case strings.HasPrefix(line, "### "):
+			s := &sections[len(sections)-1]
+			s.Groups = append(s.Groups, Group{Title: strings.TrimSpace(line[4:])})
+		case strings.HasPrefix(line, "- "):
+			s := &sections[len(sections)-1]
+			if len(s.Groups) == 0 {
+				s.Groups = append(s.Groups, Group{})
+			}
+			g := &s.Groups[len(s.Groups)-1]
+			g.Items = append(g.Items, strings.TrimSpace(line[2:]))
+		}
+	}
+	return sections
+}
+
+// Since returns the sections released after last, up to and including
+// current. Nothing is returned when current isn't in the changelog, such as
+// for development builds, or when last is current.
+func Since(sections []Section, last, current string) []Section {
+	start := -1
+	for i, s := range sections {
+		if s.Version == current {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	var newer []Section
+	for _, s := range sections[start:] {
+		if s.Version == last {
+			break
+		}
+		newer = append(newer, s)
+	}
+	return newer
+}
+
+// WhatsNew returns the embedded changelog sections released after last, up
+// to and including current
+func WhatsNew(last, current string) []Section {
+	return Since(Parse(raw), last, current)
+}
+
+// SeenPath returns the file recording the last version the user has run
+func SeenPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "cleanwithcli", "last-version")
+}
+
+// LastSeen returns the version recorded at path, or "" if there is none
+func LastSeen(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// MarkSeen records version at path
+func MarkSeen(path, version string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(version+"\n"), 0o644)
+}
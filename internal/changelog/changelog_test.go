@@ -0,0 +1,58 @@
+package changelog
+
+import "testing"
+
+const sample = `# Changelog
+
+## v0.3.0
+### Safety
+- Safer
+
+## v0.2.0
+### New categories
+- One
+- Two
+### Safety
+- Logged
+
+## v0.1.0
+- Initial
+`
+
+func TestSince(t *testing.T) {
+	sections := Parse(sample)
+	if len(sections) != 3 || len(sections[1].Groups) != 2 || len(sections[1].Groups[0].Items) != 2 {
+		t.Fatalf("Parse() = %+v", sections)
+	}
+	if sections[2].Groups[0].Title != "" || sections[2].Groups[0].Items[0] != "Initial" {
+		t.Errorf("ungrouped items = %+v", sections[2].Groups)
+	}
+
+	tests := []struct {
+		last, current string
+		want          []string
+	}{
+		{"v0.1.0", "v0.3.0", []string{"v0.3.0", "v0.2.0"}},
+		{"v0.2.0", "v0.2.0", nil},
+		{"", "v0.2.0", []string{"v0.2.0", "v0.1.0"}},
+		{"v0.1.0", "dev", nil},
+	}
+	for _, tt := range tests {
+		got := Since(sections, tt.last, tt.current)
+		if len(got) != len(tt.want) {
+			t.Errorf("Since(%q, %q) = %d sections, want %v", tt.last, tt.current, len(got), tt.want)
+			continue
+		}
+		for i, s := range got {
+			if s.Version != tt.want[i] {
+				t.Errorf("Since(%q, %q)[%d] = %s, want %s", tt.last, tt.current, i, s.Version, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestEmbeddedChangelogParses(t *testing.T) {
+	if sections := Parse(raw); len(sections) == 0 || len(sections[0].Groups) == 0 {
+		t.Errorf("embedded changelog has no sections: %+v", sections)
+	}
+}
@@ -0,0 +1,538 @@
+// Package daemon serves a JSON-RPC 2.0 API on a Unix socket so other local
+// tools can scan and clean without the terminal UI. Each line sent on a
+// connection is one request and is answered by one response line:
+//
+//	{"jsonrpc": "2.0", "id": 1, "method": "scan", "params": {"mode": "dev"}}
+//	{"jsonrpc": "2.0", "id": 1, "result": {"state": "scanning"}}
+//
+// Methods:
+//
+//	scan     {"mode": "full"|"dev"}  start a scan in the background
+//	status                           current state and last scan summary
+//	results  {"category": "…"}       last scan results, optionally one category
+//	clean    {"paths": ["…"]}        prepare a clean and return a token
+//	confirm  {"token": "…"}          run the clean the token was issued for
+//
+// While idle, the daemon can keep the directory size cache warm so scans,
+// including the TUI's, reuse recent sizes instead of walking every
+// directory.
+//
+// Cleaning takes two calls so nothing is deleted by a single stray request:
+// clean only accepts paths found by the last scan and returns a short-lived,
+// single-use token that confirm must send back.
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/scanner"
+	"github.com/rahulvramesh/cleanWithCli/pkg/cleaner"
+)
+
+// States reported by the status method
+const (
+	StateIdle     = "idle"
+	StateScanning = "scanning"
+	StateCleaning = "cleaning"
+	StateWarming  = "warming" // Refreshing cached sizes; any request stops it
+)
+
+// tokenTTL is how long a clean token can be confirmed
+const tokenTTL = 2 * time.Minute
+
+// JSON-RPC error codes
+const (
+	codeParse          = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternal       = -32603
+	codeBusy           = -32000 // A scan or clean is already running
+	codeToken          = -32001 // Unknown, used or expired token
+)
+
+// DefaultSocket returns the default socket path
+func DefaultSocket() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "cleanwithcli", "daemon.sock")
+}
+
+// Server holds the daemon state shared by all connections
+type Server struct {
+	Scan      cleaner.Options // Base scan options; the mode comes from each request
+	AuditFile string          // Deletion log, empty to disable
+	// Idle time after which directory sizes in Scan.Sizes are refreshed in
+	// the background, 0 to disable
+	WarmAfter time.Duration
+
+	mu         sync.Mutex
+	lastActive time.Time
+	warmed     bool      // Whether a first warm pass found the hotspots
+	warmedAt   time.Time // When the last warm pass finished
+	warmCancel context.CancelFunc
+	warmDone   chan struct{} // Closed when the warm pass in progress has stopped
+	state      string
+	scanErr    error
+	report     *cleaner.Report
+	scannedAt  time.Time
+	pending    map[string]pendingClean
+}
+
+// pendingClean is a clean waiting for confirmation
+type pendingClean struct {
+	items   map[string][]cleaner.FileItem // By category
+	expires time.Time
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// Result types
+type (
+	StatusResult struct {
+		State     string    `json:"state"`
+		ScannedAt time.Time `json:"scanned_at,omitempty"`
+		TotalSize int64     `json:"total_size"`
+		Error     string    `json:"error,omitempty"` // Why the last scan failed
+	}
+
+	Item struct {
+		Path string `json:"path"`
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	}
+
+	Category struct {
+		Category string `json:"category"`
+		Total    int64  `json:"total"`
+		Risk     string `json:"risk"`
+		Advisory bool   `json:"advisory,omitempty"` // Report only, never cleaned
+		Method   string `json:"method,omitempty"`   // How items are cleaned, when not deleted
+		Items    []Item `json:"items"`
+	}
+
+	ResultsResult struct {
+		ScannedAt  time.Time  `json:"scanned_at"`
+		TotalSize  int64      `json:"total_size"`
+		Categories []Category `json:"categories"`
+	}
+
+	CleanResult struct {
+		Token     string    `json:"token"`
+		Items     int       `json:"items"`
+		TotalSize int64     `json:"total_size"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	ConfirmResult struct {
+		Freed   int64    `json:"freed"`
+		Removed []string `json:"removed"`
+		Errors  []string `json:"errors,omitempty"`
+	}
+)
+
+// Listen creates the socket at path, replacing a stale one, and makes it
+// accessible to the current user only
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	os.Remove(path)
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Serve answers connections on l until ctx is done
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	go s.warmLoop(ctx)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.handle(ctx, conn)
+	}
+}
+
+// handle answers requests on one connection, one per line
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	in := bufio.NewScanner(conn)
+	in.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	enc := json.NewEncoder(conn)
+	for in.Scan() {
+		s.touch()
+		resp := response{JSONRPC: "2.0", ID: json.RawMessage("null")}
+
+		var req request
+		if err := json.Unmarshal(in.Bytes(), &req); err != nil {
+			resp.Error = &rpcError{Code: codeParse, Message: err.Error()}
+		} else {
+			if req.ID != nil {
+				resp.ID = req.ID
+			}
+			result, err := s.call(ctx, req)
+			var rerr *rpcError
+			switch {
+			case errors.As(err, &rerr):
+				resp.Error = rerr
+			case err != nil:
+				resp.Error = &rpcError{Code: codeInternal, Message: err.Error()}
+			default:
+				resp.Result = result
+			}
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// call dispatches a request to its method
+func (s *Server) call(ctx context.Context, req request) (any, error) {
+	if req.JSONRPC != "2.0" {
+		return nil, &rpcError{Code: codeInvalidRequest, Message: `jsonrpc must be "2.0"`}
+	}
+
+	switch req.Method {
+	case "scan":
+		var p struct {
+			Mode cleaner.Mode `json:"mode"`
+		}
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.startScan(ctx, p.Mode)
+	case "status":
+		return s.status(), nil
+	case "results":
+		var p struct {
+			Category string `json:"category"`
+		}
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.results(p.Category)
+	case "clean":
+		var p struct {
+			Paths []string `json:"paths"`
+		}
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.prepareClean(p.Paths, time.Now())
+	case "confirm":
+		var p struct {
+			Token string `json:"token"`
+		}
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.confirm(p.Token, time.Now())
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+}
+
+func decodeParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// startScan runs a scan in the background
+func (s *Server) startScan(ctx context.Context, mode cleaner.Mode) (*StatusResult, error) {
+	if mode == "" {
+		mode = cleaner.ModeFull
+	}
+	if mode != cleaner.ModeFull && mode != cleaner.ModeDev {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown mode %q", mode)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != "" && s.state != StateIdle {
+		return nil, &rpcError{Code: codeBusy, Message: "busy " + s.state}
+	}
+	s.state = StateScanning
+
+	opts := s.Scan
+	opts.Mode = mode
+	go func() {
+		report, err := cleaner.Scan(ctx, opts)
+		opts.Sizes.Save()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.state = StateIdle
+		s.scanErr = err
+		if err == nil {
+			s.report = report
+			s.scannedAt = time.Now()
+			// Tokens refer to the previous results
+			s.pending = nil
+		}
+	}()
+
+	return &StatusResult{State: StateScanning, ScannedAt: s.scannedAt, TotalSize: s.totalSize()}, nil
+}
+
+func (s *Server) totalSize() int64 {
+	if s.report == nil {
+		return 0
+	}
+	return s.report.TotalSize
+}
+
+func (s *Server) status() *StatusResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := &StatusResult{State: s.state, ScannedAt: s.scannedAt, TotalSize: s.totalSize()}
+	if st.State == "" {
+		st.State = StateIdle
+	}
+	if s.scanErr != nil {
+		st.Error = s.scanErr.Error()
+	}
+	return st
+}
+
+func (s *Server) results(category string) (*ResultsResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.report == nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "no scan has completed yet"}
+	}
+
+	res := &ResultsResult{ScannedAt: s.scannedAt, TotalSize: s.report.TotalSize, Categories: []Category{}}
+	for name, result := range s.report.Results {
+		if category != "" && name != category {
+			continue
+		}
+		c := Category{
+			Category: name,
+			Total:    result.Total,
+			Risk:     scanner.CategoryRisk(name).String(),
+			Advisory: result.Advisory,
+			Method:   result.Method,
+			Items:    make([]Item, 0, len(result.Items)),
+		}
+		for _, item := range result.Items {
+			c.Items = append(c.Items, Item{Path: item.Path, Name: item.Name, Size: item.Size})
+		}
+		res.Categories = append(res.Categories, c)
+	}
+	if category != "" && len(res.Categories) == 0 {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("no results for category %q", category)}
+	}
+	sort.Slice(res.Categories, func(i, j int) bool { return res.Categories[i].Total > res.Categories[j].Total })
+	return res, nil
+}
+
+// prepareClean checks that every path was found by the last scan and
+// issues a token for cleaning them
+func (s *Server) prepareClean(paths []string, now time.Time) (*CleanResult, error) {
+	if len(paths) == 0 {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "no paths to clean"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.report == nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "no scan has completed yet"}
+	}
+
+	found := make(map[string]string) // Path to category
+	sizes := make(map[string]cleaner.FileItem)
+	for name, result := range s.report.Results {
+		if result.Advisory {
+			continue
+		}
+		for _, item := range result.Items {
+			found[item.Path] = name
+			sizes[item.Path] = item
+		}
+	}
+
+	pc := pendingClean{items: make(map[string][]cleaner.FileItem), expires: now.Add(tokenTTL)}
+	res := &CleanResult{ExpiresAt: pc.expires}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		category, ok := found[path]
+		if !ok {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("%s was not found by the last scan", path)}
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		pc.items[category] = append(pc.items[category], sizes[path])
+		res.Items++
+		res.TotalSize += sizes[path].Size
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	if s.pending == nil {
+		s.pending = make(map[string]pendingClean)
+	}
+	for t, p := range s.pending {
+		if now.After(p.expires) {
+			delete(s.pending, t)
+		}
+	}
+	s.pending[token] = pc
+	res.Token = token
+	return res, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// confirm runs the clean a token was issued for. Tokens work once.
+func (s *Server) confirm(token string, now time.Time) (*ConfirmResult, error) {
+	s.mu.Lock()
+	pc, ok := s.pending[token]
+	delete(s.pending, token)
+	switch {
+	case !ok || now.After(pc.expires):
+		s.mu.Unlock()
+		return nil, &rpcError{Code: codeToken, Message: "unknown or expired token; call clean again"}
+	case s.state != "" && s.state != StateIdle:
+		s.mu.Unlock()
+		return nil, &rpcError{Code: codeBusy, Message: "busy " + s.state}
+	}
+	s.state = StateCleaning
+	report := s.report
+	s.mu.Unlock()
+
+	res := &ConfirmResult{Removed: []string{}}
+	var records []audit.Record
+	for category, items := range pc.items {
+		strategy := cleaner.RemoveAll
+		if s.Scan.Remove != nil {
+			strategy = cleaner.StrategyFunc(s.Scan.Remove)
+		}
+		if result, ok := report.Results[category]; ok {
+			strategy = cleaner.ResultStrategy(result)
+		}
+		cr := cleaner.Clean(items, strategy)
+		for _, item := range items {
+			s.Scan.Sizes.Forget(item.Path)
+		}
+		res.Freed += cr.Freed
+		res.Removed = append(res.Removed, cr.Removed...)
+		for _, err := range cr.Errors {
+			res.Errors = append(res.Errors, err.Error())
+		}
+		records = append(records, audit.CleanRecords(now, category, items, cr.Removed, cr.Errors)...)
+	}
+
+	s.Scan.Sizes.Save()
+
+	s.mu.Lock()
+	s.state = StateIdle
+	if s.report == report {
+		dropRemoved(report, res.Removed)
+	}
+	s.mu.Unlock()
+
+	if s.AuditFile != "" {
+		if err := audit.Append(s.AuditFile, records...); err != nil {
+			res.Errors = append(res.Errors, "writing deletion log: "+err.Error())
+		}
+	}
+	return res, nil
+}
+
+// dropRemoved takes removed items out of the stored results
+func dropRemoved(report *cleaner.Report, removed []string) {
+	gone := make(map[string]bool, len(removed))
+	for _, path := range removed {
+		gone[path] = true
+	}
+	for name, result := range report.Results {
+		kept := result.Items[:0]
+		for _, item := range result.Items {
+			if gone[item.Path] {
+				result.Total -= item.Size
+				if !result.Advisory {
+					report.TotalSize -= item.Size
+				}
+				continue
+			}
+			kept = append(kept, item)
+		}
+		result.Items = kept
+		if len(kept) == 0 {
+			delete(report.Results, name)
+		}
+	}
+}
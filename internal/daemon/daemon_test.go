@@ -0,0 +1,242 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/pkg/cleaner"
+)
+
+// client sends requests over one connection
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	in   *bufio.Scanner
+	id   int
+}
+
+func (c *client) call(method string, params any, result any) *rpcError {
+	c.t.Helper()
+	c.id++
+	req := map[string]any{"jsonrpc": "2.0", "id": c.id, "method": method, "params": params}
+	if err := json.NewEncoder(c.conn).Encode(req); err != nil {
+		c.t.Fatal(err)
+	}
+	if !c.in.Scan() {
+		c.t.Fatalf("%s: no response: %v", method, c.in.Err())
+	}
+
+	var resp struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(c.in.Bytes(), &resp); err != nil {
+		c.t.Fatal(err)
+	}
+	if resp.ID != c.id {
+		c.t.Fatalf("%s: response id = %d, want %d", method, resp.ID, c.id)
+	}
+	if resp.Error == nil && result != nil {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	return resp.Error
+}
+
+func TestScanAndConfirmedClean(t *testing.T) {
+	base := t.TempDir()
+	home := filepath.Join(base, "home")
+	cache := filepath.Join(home, "Library", "Caches", "com.example.app")
+	if err := os.MkdirAll(cache, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cache, "cache.db"), make([]byte, 4000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Socket paths are limited to about 100 bytes, so keep it short
+	dir, err := os.MkdirTemp("", "cwc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "d.sock")
+
+	l, err := Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(socket); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	auditFile := filepath.Join(base, "deletions.jsonl")
+	srv := &Server{
+		Scan:      cleaner.Options{HomeDir: home, RootDir: filepath.Join(base, "root")},
+		AuditFile: auditFile,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Serve(ctx, l)
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := &client{t: t, conn: conn, in: bufio.NewScanner(conn)}
+
+	if err := c.call("results", nil, nil); err == nil {
+		t.Error("results before any scan succeeded")
+	}
+	if err := c.call("scan", map[string]string{"mode": "full"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	var st StatusResult
+	for deadline := time.Now().Add(10 * time.Second); ; {
+		if err := c.call("status", nil, &st); err != nil {
+			t.Fatal(err)
+		}
+		if st.State == StateIdle {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scan did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var res ResultsResult
+	if err := c.call("results", map[string]string{"category": "Cache Files"}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Categories) != 1 || len(res.Categories[0].Items) != 1 || res.Categories[0].Items[0].Path != cache {
+		t.Fatalf("results = %+v, want the cache directory", res)
+	}
+
+	// Only paths found by the scan can be cleaned
+	if err := c.call("clean", map[string][]string{"paths": {home}}, nil); err == nil || err.Code != codeInvalidParams {
+		t.Errorf("clean of an unscanned path = %v, want invalid params", err)
+	}
+
+	var prep CleanResult
+	if err := c.call("clean", map[string][]string{"paths": {cache}}, &prep); err != nil {
+		t.Fatal(err)
+	}
+	if prep.Token == "" || prep.Items != 1 || prep.TotalSize < 4000 {
+		t.Errorf("clean = %+v", prep)
+	}
+	if _, err := os.Stat(cache); err != nil {
+		t.Fatal("clean deleted before confirmation")
+	}
+
+	var done ConfirmResult
+	if err := c.call("confirm", map[string]string{"token": prep.Token}, &done); err != nil {
+		t.Fatal(err)
+	}
+	if len(done.Removed) != 1 || len(done.Errors) != 0 {
+		t.Errorf("confirm = %+v", done)
+	}
+	if _, err := os.Stat(cache); !os.IsNotExist(err) {
+		t.Errorf("%s still exists after confirm", cache)
+	}
+	if err := c.call("confirm", map[string]string{"token": prep.Token}, nil); err == nil || err.Code != codeToken {
+		t.Errorf("reused token = %v, want a token error", err)
+	}
+
+	records, err := audit.Load(auditFile)
+	if err != nil || len(records) != 1 || records[0].Outcome != audit.OutcomeDeleted {
+		t.Errorf("audit records = %+v, %v", records, err)
+	}
+
+	if err := c.call("nope", nil, nil); err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("unknown method = %v", err)
+	}
+}
+
+func TestTokenExpires(t *testing.T) {
+	srv := &Server{report: &cleaner.Report{Results: map[string]*cleaner.ScanResult{
+		"Cache Files": {Category: "Cache Files", Items: []cleaner.FileItem{{Path: "/tmp/x", Size: 1}}, Total: 1},
+	}}}
+
+	now := time.Now()
+	prep, err := srv.prepareClean([]string{"/tmp/x"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.confirm(prep.Token, now.Add(tokenTTL+time.Second)); err == nil {
+		t.Error("expired token was accepted")
+	}
+}
+
+func TestWarmCachesSizes(t *testing.T) {
+	base := t.TempDir()
+	home := filepath.Join(base, "home")
+	cache := filepath.Join(home, "Library", "Caches", "com.example.app")
+	if err := os.MkdirAll(cache, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(cache, "cache.db"), make([]byte, 4000), 0o644)
+
+	sizesFile := filepath.Join(base, "sizes.json")
+	srv := &Server{
+		Scan: cleaner.Options{
+			HomeDir:  home,
+			RootDir:  filepath.Join(base, "root"),
+			Disabled: []string{"docker", "time-machine"},
+			Sizes:    cleaner.OpenSizeCache(sizesFile),
+		},
+		WarmAfter: time.Minute,
+	}
+
+	// The first pass finds and sizes the hotspots
+	srv.warm(context.Background())
+	if !srv.warmed {
+		t.Fatal("first warm pass didn't finish")
+	}
+	data, err := os.ReadFile(sizesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), cache) {
+		t.Errorf("size cache doesn't include %s:\n%s", cache, data)
+	}
+
+	if srv.status().State != StateIdle {
+		t.Errorf("state after warming = %q, want idle", srv.status().State)
+	}
+
+	// An idle daemon doesn't warm again until something asked for a scan
+	if srv.warmDue() {
+		t.Error("warm pass due again without any request")
+	}
+
+	// A request arriving mid-pass cancels it and waits for it to stop
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	srv.warmCancel, srv.warmDone = cancel, done
+	go func() {
+		<-ctx.Done()
+		close(done)
+	}()
+	srv.touch()
+	if ctx.Err() == nil {
+		t.Error("request didn't cancel the warm pass")
+	}
+	if srv.idleSince(time.Now().Add(-time.Second)) {
+		t.Error("idle right after a request")
+	}
+	if !srv.warmDue() {
+		t.Error("no warm pass due after a request")
+	}
+}
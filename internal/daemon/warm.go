@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/sizecache"
+	"github.com/rahulvramesh/cleanWithCli/pkg/cleaner"
+)
+
+// touch records a request, postponing background sizing. A warm pass in
+// progress is stopped, and touch waits for it to wind down so the request
+// doesn't scan alongside it.
+func (s *Server) touch() {
+	s.mu.Lock()
+	s.lastActive = time.Now()
+	cancel, done := s.warmCancel, s.warmDone
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// idleSince reports whether nothing has asked the daemon for anything since
+// t, and no scan or clean is running
+func (s *Server) idleSince(t time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return (s.state == "" || s.state == StateIdle) && !s.lastActive.After(t)
+}
+
+// warmDue reports whether a warm pass should run: the first one as soon as
+// the daemon is idle, later ones only once a request has come in since the
+// last pass, so an idle daemon doesn't keep walking the same trees
+func (s *Server) warmDue() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.warmed || s.lastActive.After(s.warmedAt)
+}
+
+// warmLoop refreshes cached directory sizes whenever the daemon has been
+// idle for WarmAfter
+func (s *Server) warmLoop(ctx context.Context) {
+	if s.WarmAfter <= 0 || s.Scan.Sizes == nil {
+		return
+	}
+	ticker := time.NewTicker(s.WarmAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if s.idleSince(now.Add(-s.WarmAfter)) && s.warmDue() {
+				s.warm(ctx)
+			}
+		}
+	}
+}
+
+// warm sizes hotspots so the next scan, in the daemon or the TUI, finds
+// their sizes cached. The first pass runs full and dev scans to find them,
+// such as DerivedData, caches and node_modules roots; later passes measure
+// again the cached directories whose sizes are about to expire. The daemon
+// is busy warming meanwhile, and a request arriving cancels the pass.
+func (s *Server) warm(ctx context.Context) {
+	s.mu.Lock()
+	if s.state != "" && s.state != StateIdle {
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.state, s.warmCancel, s.warmDone = StateWarming, cancel, done
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.state, s.warmCancel, s.warmDone = StateIdle, nil, nil
+		if ctx.Err() == nil {
+			s.warmedAt = time.Now()
+		}
+		s.mu.Unlock()
+		cancel()
+		close(done)
+	}()
+
+	if !s.warmed {
+		for _, mode := range []cleaner.Mode{cleaner.ModeFull, cleaner.ModeDev} {
+			opts := s.Scan
+			opts.Mode = mode
+			if _, err := cleaner.Scan(ctx, opts); err != nil {
+				return
+			}
+		}
+		s.mu.Lock()
+		s.warmed = true
+		s.mu.Unlock()
+	} else {
+		// Sizes still trusted for a while are used as they are, so only
+		// those expiring before the next pass are worth measuring now
+		for _, path := range s.Scan.Sizes.Stale(max(0, sizecache.MaxAge-s.WarmAfter)) {
+			if ctx.Err() != nil {
+				break
+			}
+			s.Scan.Sizes.Refresh(path)
+		}
+	}
+	s.Scan.Sizes.Save()
+}
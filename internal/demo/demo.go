@@ -0,0 +1,81 @@
+package demo
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// HomeDir is the fictional home directory used for synthetic paths
+const HomeDir = "/Users/demo"
+
+// categories lists the synthetic categories and how many items each gets
+var categories = []struct {
+	name  string
+	items int
+}{
+	{"Node Modules", 2500},
+	{"Cache Files", 800},
+	{"Python Artifacts", 600},
+	{"Build Artifacts", 400},
+	{"Log Files", 1200},
+	{"Old Downloads", 150},
+	{"Xcode Files", 60},
+	{"Trash", 40},
+}
+
+// names mixes plain, long and non-ASCII project names
+var names = []string{
+	"website",
+	"api-server",
+	"プロジェクト",
+	"données-client",
+	"🚀 rocket-launcher",
+	"a-project-with-an-unreasonably-long-name-that-will-never-fit-in-any-column",
+	"ünïcödé-tëst",
+	"mobile app (old)",
+	"Ελληνικά",
+	"client_work_2019_FINAL_final_v3",
+}
+
+// Results returns a large, deterministic synthetic scan result set
+func Results() types.ScanCompleteMsg {
+	rng := rand.New(rand.NewSource(42))
+	msg := types.ScanCompleteMsg{Results: make(map[string]*types.ScanResult)}
+
+	for _, c := range categories {
+		result := &types.ScanResult{Category: c.name}
+		for i := 0; i < c.items; i++ {
+			name := names[rng.Intn(len(names))]
+			depth := rng.Intn(6)
+			segments := []string{HomeDir, "code"}
+			for d := 0; d < depth; d++ {
+				segments = append(segments, fmt.Sprintf("level-%d", d))
+			}
+			segments = append(segments, fmt.Sprintf("%s-%d", name, i))
+
+			// Every tenth item is empty to exercise zero-size rendering
+			var size int64
+			if i%10 != 0 {
+				size = rng.Int63n(4 << 30)
+			}
+
+			path := filepath.Join(segments...)
+			result.Items = append(result.Items, types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  strings.TrimPrefix(path, HomeDir+"/"),
+				Age:   rng.Intn(400),
+				IsDir: rng.Intn(3) > 0,
+			})
+			result.Total += size
+		}
+		msg.Results[c.name] = result
+		msg.TotalSize += result.Total
+	}
+
+	return msg
+}
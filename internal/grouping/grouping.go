@@ -0,0 +1,269 @@
+// Package grouping renames, merges and splits scan categories according to
+// a user's categories.json, so the results view matches how they think
+// about their disk:
+//
+//	{
+//	  "rename": {"Log Files": "Logs"},
+//	  "merge": {"JavaScript": ["NPM/Yarn/PNPM Caches", "Node Modules"]},
+//	  "split": ["Cache Files"]
+//	}
+//
+// Rules name the categories reported by the scanners. A split category
+// becomes one category per item, e.g. "Cache Files: com.apple.Safari".
+// Report-only categories can be renamed or split but never merged, so their
+// items can't end up in a category that gets cleaned.
+package grouping
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/scanner"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// Config holds the grouping rules. A nil Config leaves categories as they
+// are.
+type Config struct {
+	Rename map[string]string   `json:"rename,omitempty"`
+	Merge  map[string][]string `json:"merge,omitempty"`
+	Split  []string            `json:"split,omitempty"`
+}
+
+// DefaultPath returns the default location of the grouping rules
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "cleanwithcli", "categories.json")
+}
+
+// Load reads the rules at path. A missing file has no rules.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// validate rejects categories used by more than one rule
+func (c *Config) validate() error {
+	used := make(map[string]string)
+	claim := func(category, rule string) error {
+		if prev, ok := used[category]; ok {
+			return fmt.Errorf("category %q is used by both %s and %s", category, prev, rule)
+		}
+		used[category] = rule
+		return nil
+	}
+
+	for from := range c.Rename {
+		if err := claim(from, "rename"); err != nil {
+			return err
+		}
+	}
+	for name, sources := range c.Merge {
+		for _, from := range sources {
+			if err := claim(from, fmt.Sprintf("merge %q", name)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, from := range c.Split {
+		if err := claim(from, "split"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergedInto returns the merged category a source belongs to
+func (c *Config) mergedInto(category string) (string, bool) {
+	for name, sources := range c.Merge {
+		for _, from := range sources {
+			if from == category {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (c *Config) splits(category string) bool {
+	for _, from := range c.Split {
+		if from == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply regroups results. remove is the default remover, used for items of
+// merged categories that have no remover of their own.
+func (c *Config) Apply(results map[string]*types.ScanResult, remove func(string) error) map[string]*types.ScanResult {
+	if c == nil {
+		return results
+	}
+
+	out := make(map[string]*types.ScanResult, len(results))
+	merged := make(map[string][]*types.ScanResult)
+
+	add := func(name string, r *types.ScanResult) {
+		if existing, ok := out[name]; ok && existing.Advisory != r.Advisory {
+			// Never mix report-only items into a category that gets cleaned
+			if r.Advisory {
+				r.Category = name + " (report only)"
+				out[r.Category] = r
+				return
+			}
+			existing.Category = name + " (report only)"
+			out[existing.Category] = existing
+			delete(out, name)
+		} else if ok {
+			// Two sources ended up with the same name; combine them
+			merged[name] = append(merged[name], existing, r)
+			delete(out, name)
+			return
+		}
+		if _, ok := merged[name]; ok {
+			merged[name] = append(merged[name], r)
+			return
+		}
+		out[name] = r
+	}
+
+	for category, result := range results {
+		switch name, ok := c.mergedInto(category); {
+		case ok && !result.Advisory:
+			merged[name] = append(merged[name], result)
+		case c.splits(category):
+			for _, item := range result.Items {
+				part := *result
+				part.Category = category + ": " + item.Name
+				part.Items = []types.FileItem{item}
+				part.Total = item.Size
+				add(part.Category, &part)
+			}
+		case c.Rename[category] != "":
+			renamed := *result
+			renamed.Category = c.Rename[category]
+			add(renamed.Category, &renamed)
+		default:
+			add(category, result)
+		}
+	}
+
+	for name, sources := range merged {
+		if existing, ok := out[name]; ok {
+			sources = append(sources, existing)
+		}
+		out[name] = combine(name, sources, remove)
+	}
+	return out
+}
+
+// combine merges several results into one category
+func combine(name string, sources []*types.ScanResult, remove func(string) error) *types.ScanResult {
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Category < sources[j].Category })
+
+	result := &types.ScanResult{Category: name, Items: []types.FileItem{}}
+	owners := make(map[string]func(string) error)
+	var methods []string
+	custom := false
+	for _, src := range sources {
+		result.Items = append(result.Items, src.Items...)
+		result.Total += src.Total
+		result.Errors = append(result.Errors, src.Errors...)
+
+		r, method := src.Remover, src.Method
+		if r == nil {
+			r = remove
+			method = "delete"
+		} else {
+			custom = true
+		}
+		for _, item := range src.Items {
+			owners[item.Path] = r
+		}
+		if !contains(methods, method) {
+			methods = append(methods, method)
+		}
+	}
+
+	if !custom {
+		return result
+	}
+	result.Method = strings.Join(methods, " or ")
+	result.Remover = func(path string) error {
+		// Items may also be found below a category item while exploring
+		for dir := path; ; dir = filepath.Dir(dir) {
+			if r, ok := owners[dir]; ok {
+				return r(path)
+			}
+			if filepath.Dir(dir) == dir {
+				return remove(path)
+			}
+		}
+	}
+	return result
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources returns the scanner categories a displayed category was built from
+func (c *Config) Sources(category string) []string {
+	if c == nil {
+		return []string{category}
+	}
+	if sources, ok := c.Merge[category]; ok {
+		return sources
+	}
+	for from, to := range c.Rename {
+		if to == category {
+			return []string{from}
+		}
+	}
+	for _, from := range c.Split {
+		if strings.HasPrefix(category, from+": ") {
+			return []string{from}
+		}
+	}
+	return []string{category}
+}
+
+// Risk returns the risk of a displayed category: the highest risk of the
+// categories it was built from
+func (c *Config) Risk(category string) types.RiskLevel {
+	sources := c.Sources(category)
+	risk := scanner.CategoryRisk(sources[0])
+	for _, from := range sources[1:] {
+		if r := scanner.CategoryRisk(from); r > risk {
+			risk = r
+		}
+	}
+	return risk
+}
@@ -0,0 +1,111 @@
+package grouping
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func results() map[string]*types.ScanResult {
+	return map[string]*types.ScanResult{
+		"Node Modules": {Category: "Node Modules", Total: 100, Items: []types.FileItem{
+			{Path: "/code/web/node_modules", Size: 100},
+		}},
+		"NPM/Yarn/PNPM Caches": {Category: "NPM/Yarn/PNPM Caches", Total: 30, Items: []types.FileItem{
+			{Path: "/home/.npm", Size: 30},
+		}, Remover: func(string) error { return errors.New("plugin") }, Method: "ask plugin"},
+		"Cache Files": {Category: "Cache Files", Total: 15, Items: []types.FileItem{
+			{Path: "/home/Library/Caches/com.apple.Safari", Name: "com.apple.Safari", Size: 10},
+			{Path: "/home/Library/Caches/com.spotify.client", Name: "com.spotify.client", Size: 5},
+		}},
+		"Log Files": {Category: "Log Files", Total: 7, Items: []types.FileItem{{Path: "/var/log/a", Size: 7}}},
+		"Sound Libraries": {Category: "Sound Libraries", Total: 50, Advisory: true, Items: []types.FileItem{
+			{Path: "/Library/Audio/Apple Loops", Size: 50},
+		}},
+	}
+}
+
+func TestApply(t *testing.T) {
+	c := &Config{
+		Rename: map[string]string{"Log Files": "Logs"},
+		Merge:  map[string][]string{"JavaScript": {"NPM/Yarn/PNPM Caches", "Node Modules", "Sound Libraries"}},
+		Split:  []string{"Cache Files"},
+	}
+
+	var deleted []string
+	remove := func(path string) error {
+		deleted = append(deleted, path)
+		return nil
+	}
+	got := c.Apply(results(), remove)
+
+	want := map[string]int64{
+		"JavaScript":                      130,
+		"Cache Files: com.apple.Safari":   10,
+		"Cache Files: com.spotify.client": 5,
+		"Logs":                            7,
+		"Sound Libraries":                 50, // Report-only categories are never merged
+	}
+	if len(got) != len(want) {
+		t.Fatalf("categories = %v, want %v", keys(got), want)
+	}
+	for name, total := range want {
+		if got[name] == nil || got[name].Total != total || got[name].Category != name {
+			t.Errorf("%s = %+v, want total %d", name, got[name], total)
+		}
+	}
+
+	// Items of a merged category keep their own way of being cleaned
+	js := got["JavaScript"]
+	if err := js.Remover("/home/.npm/_cacache"); err == nil || err.Error() != "plugin" {
+		t.Errorf("npm cache removal = %v, want the plugin remover", err)
+	}
+	if err := js.Remover("/code/web/node_modules"); err != nil || len(deleted) != 1 {
+		t.Errorf("node_modules removal = %v, deleted %v; want the default remover", err, deleted)
+	}
+	if js.Method != "ask plugin or delete" {
+		t.Errorf("method = %q", js.Method)
+	}
+
+	if sources := c.Sources("Cache Files: com.apple.Safari"); len(sources) != 1 || sources[0] != "Cache Files" {
+		t.Errorf("Sources(split) = %v", sources)
+	}
+	if sources := c.Sources("Logs"); len(sources) != 1 || sources[0] != "Log Files" {
+		t.Errorf("Sources(renamed) = %v", sources)
+	}
+	js2 := &Config{Merge: map[string][]string{"JavaScript": {"NPM/Yarn/PNPM Caches", "Node Modules"}}}
+	if risk := js2.Risk("JavaScript"); risk != types.RiskMedium {
+		t.Errorf("Risk(JavaScript) = %v, want the highest of its parts", risk)
+	}
+
+	var none *Config
+	if got := none.Apply(results(), remove); len(got) != 5 {
+		t.Errorf("nil config changed the categories: %v", keys(got))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if c, err := Load(filepath.Join(dir, "missing.json")); c != nil || err != nil {
+		t.Errorf("Load(missing) = %v, %v; want no rules", c, err)
+	}
+
+	path := filepath.Join(dir, "categories.json")
+	if err := os.WriteFile(path, []byte(`{"rename": {"Trash": "Bin"}, "split": ["Trash"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("a category used by two rules was accepted")
+	}
+}
+
+func keys(m map[string]*types.ScanResult) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
@@ -0,0 +1,127 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// CategoryTotal is the size and item count of one category in a scan
+type CategoryTotal struct {
+	Items int   `json:"items"`
+	Total int64 `json:"total"`
+}
+
+// KindClean marks entries recorded after a clean, which only sample the
+// free disk space
+const KindClean = "clean"
+
+// Entry summarizes a single completed scan, or a free space sample taken
+// after a clean
+type Entry struct {
+	Time       time.Time                `json:"time"`
+	Kind       string                   `json:"kind"` // "full", "dev", "quick" or KindClean
+	TotalSize  int64                    `json:"total_size"`
+	Categories map[string]CategoryTotal `json:"categories"`
+	FreeBytes  int64                    `json:"free_bytes,omitempty"` // Free space on the home volume
+	DiskBytes  int64                    `json:"disk_bytes,omitempty"` // Size of the home volume
+	Stats      *types.ScanStats         `json:"stats,omitempty"`      // What the scan cost
+}
+
+// Items returns the number of items found across all categories
+func (e Entry) Items() int {
+	n := 0
+	for _, c := range e.Categories {
+		n += c.Items
+	}
+	return n
+}
+
+// DefaultPath returns the default location of the history file
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "cleanwithcli", "history.jsonl")
+}
+
+// NewEntry builds a history entry from scan results
+func NewEntry(kind string, results map[string]*types.ScanResult, totalSize int64) Entry {
+	entry := Entry{
+		Time:       time.Now(),
+		Kind:       kind,
+		TotalSize:  totalSize,
+		Categories: make(map[string]CategoryTotal, len(results)),
+	}
+	for name, result := range results {
+		entry.Categories[name] = CategoryTotal{Items: len(result.Items), Total: result.Total}
+	}
+	return entry
+}
+
+// Scans returns the entries that record scans, leaving out clean samples
+func Scans(entries []Entry) []Entry {
+	var scans []Entry
+	for _, e := range entries {
+		if e.Kind != KindClean {
+			scans = append(scans, e)
+		}
+	}
+	return scans
+}
+
+// Previous returns the most recent entry of the given kind, or nil if there
+// is none
+func Previous(entries []Entry, kind string) *Entry {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Kind == kind {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// Append adds entry to the end of the history file at path
+func Append(path string, entry Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(entry)
+}
+
+// Load reads all entries from the history file at path, oldest first. A
+// missing file is an empty history; unreadable lines are skipped.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,184 @@
+// Package launchd audits the launch agents and daemons third-party apps
+// install, finding the ones whose program is gone, typically left behind
+// by apps that were deleted rather than uninstalled
+package launchd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// commandTimeout bounds each plutil or launchctl call
+const commandTimeout = 20 * time.Second
+
+// run runs a command and returns its standard output. Tests replace it.
+var run = func(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// Kinds of jobs, by the folder their property list is in
+const (
+	KindUserAgent = "user agent" // ~/Library/LaunchAgents, runs as the user
+	KindAgent     = "agent"      // /Library/LaunchAgents, runs as each user
+	KindDaemon    = "daemon"     // /Library/LaunchDaemons, runs as root
+)
+
+// Job is a launch agent or daemon
+type Job struct {
+	Label   string
+	Plist   string // Path of the job's property list
+	Kind    string
+	Program string // Binary the job runs, empty if the plist names none
+	App     string // App bundle the job runs from, empty if none
+	Missing string // Program or App when it no longer exists
+}
+
+// Stale reports whether the job's program or app is gone
+func (j Job) Stale() bool {
+	return j.Missing != ""
+}
+
+var (
+	labelPattern   = regexp.MustCompile(`<key>Label</key>\s*<string>([^<]*)</string>`)
+	programPattern = regexp.MustCompile(`<key>Program</key>\s*<string>([^<]*)</string>`)
+	argsPattern    = regexp.MustCompile(`<key>ProgramArguments</key>\s*<array>((?:\s*<string>[^<]*</string>)*)`)
+	argPattern     = regexp.MustCompile(`<string>([^<]*)</string>`)
+	appPattern     = regexp.MustCompile(`^(.*?\.app)/`)
+)
+
+// unescape decodes the entities a property list escapes strings with
+var unescape = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&amp;", "&").Replace
+
+// parse reads the label, program and arguments of a property list, converting
+// binary ones to XML with plutil
+func parse(path string) (label, program string, args []string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		if data, err = run("plutil", "-convert", "xml1", "-o", "-", path); err != nil {
+			return "", "", nil, err
+		}
+	}
+	if m := labelPattern.FindSubmatch(data); m != nil {
+		label = unescape(string(m[1]))
+	}
+	if m := programPattern.FindSubmatch(data); m != nil {
+		program = unescape(string(m[1]))
+	}
+	if m := argsPattern.FindSubmatch(data); m != nil {
+		for _, arg := range argPattern.FindAllSubmatch(m[1], -1) {
+			args = append(args, unescape(string(arg[1])))
+		}
+	}
+	// Program defaults to the first argument
+	if program == "" && len(args) > 0 {
+		program = args[0]
+	}
+	return label, program, args, nil
+}
+
+// List returns the third-party jobs in ~/Library/LaunchAgents under home
+// and in /Library/LaunchAgents and /Library/LaunchDaemons under root, stale
+// ones first. Apple's own jobs are left out. Property lists that can't be
+// read are returned as errors alongside the jobs.
+func List(root, home string) ([]Job, []error) {
+	dirs := []struct{ dir, kind string }{
+		{filepath.Join(home, "Library", "LaunchAgents"), KindUserAgent},
+		{filepath.Join(root, "Library", "LaunchAgents"), KindAgent},
+		{filepath.Join(root, "Library", "LaunchDaemons"), KindDaemon},
+	}
+
+	var jobs []Job
+	var errs []error
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d.dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, types.NewPathError("scan", d.dir, err))
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".plist" {
+				continue
+			}
+			path := filepath.Join(d.dir, entry.Name())
+			label, program, args, err := parse(path)
+			if err != nil {
+				errs = append(errs, types.NewPathError("scan", path, err))
+				continue
+			}
+			if label == "" {
+				label = strings.TrimSuffix(entry.Name(), ".plist")
+			}
+			if strings.HasPrefix(label, "com.apple.") {
+				continue
+			}
+			jobs = append(jobs, newJob(root, label, path, d.kind, program, args))
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].Stale() != jobs[j].Stale() {
+			return jobs[i].Stale()
+		}
+		return jobs[i].Label < jobs[j].Label
+	})
+	return jobs, errs
+}
+
+// newJob checks whether the program of a job, and the app it runs from,
+// still exist. Jobs running a script through an interpreter are checked
+// by the first argument inside an app bundle.
+func newJob(root, label, path, kind, program string, args []string) Job {
+	job := Job{Label: label, Plist: path, Kind: kind, Program: program}
+	exists := func(p string) bool {
+		_, err := os.Stat(filepath.Join(root, p))
+		return err == nil
+	}
+	for _, arg := range append([]string{program}, args...) {
+		if m := appPattern.FindStringSubmatch(arg); m != nil && filepath.IsAbs(arg) {
+			job.App = m[1]
+			break
+		}
+	}
+	if job.App != "" && !exists(job.App) {
+		job.Missing = job.App
+	} else if filepath.IsAbs(program) && !exists(program) {
+		job.Missing = program
+	}
+	return job
+}
+
+// Remove unloads a job and deletes its property list. Unloading fails for
+// jobs that aren't loaded, which stale ones rarely are, so its error is
+// ignored; daemons and /Library agents need admin rights to delete.
+func Remove(job Job) error {
+	domain := fmt.Sprintf("gui/%d", os.Getuid())
+	if job.Kind == KindDaemon {
+		domain = "system"
+	}
+	run("launchctl", "bootout", domain, job.Plist)
+	if err := os.Remove(job.Plist); err != nil {
+		return types.NewPathError("remove", job.Plist, err)
+	}
+	return nil
+}
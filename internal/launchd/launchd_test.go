@@ -0,0 +1,92 @@
+package launchd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// jobPlist returns a property list running program, with extra arguments
+func jobPlist(label string, args ...string) string {
+	var s strings.Builder
+	s.WriteString("<plist><dict>\n\t<key>Label</key>\n\t<string>" + label + "</string>\n")
+	s.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range args {
+		s.WriteString("\t\t<string>" + arg + "</string>\n")
+	}
+	s.WriteString("\t</array>\n</dict></plist>\n")
+	return s.String()
+}
+
+func TestList(t *testing.T) {
+	root, home := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(home, "Library/LaunchAgents/com.gone.updater.plist"):    jobPlist("com.gone.updater", "/Applications/Gone.app/Contents/MacOS/Updater"),
+		filepath.Join(home, "Library/LaunchAgents/com.kept.helper.plist"):     jobPlist("com.kept.helper", "/Applications/Kept.app/Contents/MacOS/Helper", "--daemon"),
+		filepath.Join(root, "Library/LaunchAgents/com.apple.something.plist"): jobPlist("com.apple.something", "/nowhere"),
+		filepath.Join(root, "Library/LaunchDaemons/com.vendor.script.plist"):  jobPlist("com.vendor.script", "/bin/sh", "/Applications/Vendor.app/Contents/Resources/run.sh"),
+		filepath.Join(root, "Library/LaunchDaemons/org.tool.daemon.plist"):    "<plist><dict><key>Label</key><string>org.tool.daemon</string><key>Program</key><string>/usr/local/bin/tool</string></dict></plist>",
+		filepath.Join(root, "Applications/Kept.app/Contents/MacOS/Helper"):    "",
+		filepath.Join(root, "bin/sh"):                                         "",
+		filepath.Join(root, "usr/local/bin/tool"):                             "",
+		filepath.Join(home, "Library/LaunchAgents/notes.txt"):                 "",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs, errs := List(root, home)
+	if len(errs) != 0 {
+		t.Errorf("errors = %v", errs)
+	}
+	want := []Job{
+		{Label: "com.gone.updater", Kind: KindUserAgent, Program: "/Applications/Gone.app/Contents/MacOS/Updater", App: "/Applications/Gone.app", Missing: "/Applications/Gone.app"},
+		{Label: "com.vendor.script", Kind: KindDaemon, Program: "/bin/sh", App: "/Applications/Vendor.app", Missing: "/Applications/Vendor.app"},
+		{Label: "com.kept.helper", Kind: KindUserAgent, Program: "/Applications/Kept.app/Contents/MacOS/Helper", App: "/Applications/Kept.app"},
+		{Label: "org.tool.daemon", Kind: KindDaemon, Program: "/usr/local/bin/tool"},
+	}
+	if len(jobs) != len(want) {
+		t.Fatalf("jobs = %+v, want %d", jobs, len(want))
+	}
+	for i, w := range want {
+		got := jobs[i]
+		got.Plist = ""
+		if got != w {
+			t.Errorf("job %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	defer func(orig func(string, ...string) ([]byte, error)) { run = orig }(run)
+	var ran []string
+	run = func(name string, args ...string) ([]byte, error) {
+		ran = append(ran, name+" "+strings.Join(args[:2], " "))
+		return nil, errors.New(name + ": no such process")
+	}
+
+	plist := filepath.Join(t.TempDir(), "com.gone.updater.plist")
+	if err := os.WriteFile(plist, []byte(jobPlist("com.gone.updater", "/gone")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(Job{Label: "com.gone.updater", Plist: plist, Kind: KindDaemon}); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := os.Stat(plist); !os.IsNotExist(err) {
+		t.Errorf("plist still exists: %v", err)
+	}
+	if len(ran) != 1 || ran[0] != "launchctl bootout system" {
+		t.Errorf("ran %v, want launchctl bootout system", ran)
+	}
+
+	if err := Remove(Job{Plist: plist}); err == nil {
+		t.Error("removing a missing plist succeeded")
+	}
+}
@@ -0,0 +1,25 @@
+// Package notify posts macOS user notifications
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Send posts a notification, through terminal-notifier when it is installed
+// and osascript otherwise
+func Send(title, message string) error {
+	var cmd *exec.Cmd
+	if path, err := exec.LookPath("terminal-notifier"); err == nil {
+		cmd = exec.Command(path, "-title", title, "-message", message, "-group", "cleanwithcli")
+	} else {
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+		cmd = exec.Command("osascript", "-e", script)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
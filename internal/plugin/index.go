@@ -0,0 +1,211 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultIndex is where the community plugin index is published. Set
+// CLEANWITHCLI_PLUGIN_INDEX to use another index, such as a local file.
+const DefaultIndex = "https://raw.githubusercontent.com/rahulvramesh/cleanWithCli/main/plugins/index.json"
+
+// disabledDir holds installed plugins that are not loaded, inside the
+// plugins directory
+const disabledDir = "disabled"
+
+const downloadTimeout = 2 * time.Minute
+
+// Listing is a plugin published in an index
+type Listing struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+	URL         string `json:"url"`    // Executable to download
+	SHA256      string `json:"sha256"` // Hex checksum of the executable
+}
+
+// Index is a list of published plugins:
+//
+//	{"plugins": [{"name": "unreal", "description": "Unreal Engine DDC",
+//	  "version": "1.0.0", "url": "https://…/unreal", "sha256": "…"}]}
+type Index struct {
+	Plugins []Listing `json:"plugins"`
+}
+
+// Find returns the listing called name
+func (idx *Index) Find(name string) (Listing, bool) {
+	for _, l := range idx.Plugins {
+		if l.Name == name {
+			return l, true
+		}
+	}
+	return Listing{}, false
+}
+
+// IndexLocation returns the index to use, honoring CLEANWITHCLI_PLUGIN_INDEX
+func IndexLocation() string {
+	if loc := os.Getenv("CLEANWITHCLI_PLUGIN_INDEX"); loc != "" {
+		return loc
+	}
+	return DefaultIndex
+}
+
+// open reads a local path, file:// URL or http(s) URL
+func open(location string) (io.ReadCloser, error) {
+	switch {
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		client := &http.Client{Timeout: downloadTimeout}
+		resp, err := client.Get(location)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("fetching %s: %s", location, resp.Status)
+		}
+		return resp.Body, nil
+	default:
+		return os.Open(strings.TrimPrefix(location, "file://"))
+	}
+}
+
+// FetchIndex reads the index at location
+func FetchIndex(location string) (*Index, error) {
+	r, err := open(location)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var idx Index
+	if err := json.NewDecoder(r).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("invalid plugin index %s: %w", location, err)
+	}
+	return &idx, nil
+}
+
+// validName reports whether name can be used as a plugin file name
+func validName(name string) bool {
+	return name != "" && name != disabledDir && !strings.HasPrefix(name, ".") &&
+		!strings.ContainsAny(name, `/\`)
+}
+
+// Install downloads the listing's executable into dir after verifying its
+// checksum, replacing any installed version
+func Install(dir string, l Listing) error {
+	if !validName(l.Name) {
+		return fmt.Errorf("invalid plugin name %q", l.Name)
+	}
+	if l.SHA256 == "" {
+		return fmt.Errorf("plugin %s has no checksum in the index", l.Name)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	r, err := open(l.URL)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	tmp, err := os.CreateTemp(dir, "."+l.Name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", l.Name, err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, l.SHA256) {
+		return fmt.Errorf("checksum mismatch for %s: got %s, index lists %s", l.Name, sum, l.SHA256)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return err
+	}
+	// A reinstall also re-enables the plugin
+	os.Remove(filepath.Join(dir, disabledDir, l.Name))
+	return os.Rename(tmp.Name(), filepath.Join(dir, l.Name))
+}
+
+// Installed describes a plugin file in the plugins directory
+type Installed struct {
+	Name    string
+	Enabled bool
+}
+
+// ListInstalled returns the enabled and disabled plugins in dir
+func ListInstalled(dir string) ([]Installed, error) {
+	var installed []Installed
+	for _, sub := range []struct {
+		dir     string
+		enabled bool
+	}{{dir, true}, {filepath.Join(dir, disabledDir), false}} {
+		plugins, err := Discover(sub.dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range plugins {
+			installed = append(installed, Installed{Name: p.Name, Enabled: sub.enabled})
+		}
+	}
+	return installed, nil
+}
+
+// locate returns where an installed plugin lives and whether it is enabled
+func locate(dir, name string) (string, bool, error) {
+	if !validName(name) {
+		return "", false, fmt.Errorf("invalid plugin name %q", name)
+	}
+	for _, enabled := range []bool{true, false} {
+		path := filepath.Join(dir, name)
+		if !enabled {
+			path = filepath.Join(dir, disabledDir, name)
+		}
+		if _, err := os.Lstat(path); err == nil {
+			return path, enabled, nil
+		}
+	}
+	return "", false, fmt.Errorf("plugin %s is not installed", name)
+}
+
+// Uninstall deletes an installed plugin, enabled or not
+func Uninstall(dir, name string) error {
+	path, _, err := locate(dir, name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// SetEnabled moves an installed plugin in or out of the disabled directory.
+// Disabled plugins stay installed but are not loaded.
+func SetEnabled(dir, name string, enabled bool) error {
+	path, current, err := locate(dir, name)
+	if err != nil || current == enabled {
+		return err
+	}
+	dest := filepath.Join(dir, name)
+	if !enabled {
+		if err := os.MkdirAll(filepath.Join(dir, disabledDir), 0o755); err != nil {
+			return err
+		}
+		dest = filepath.Join(dir, disabledDir, name)
+	}
+	return os.Rename(path, dest)
+}
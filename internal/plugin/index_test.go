@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallFromIndex(t *testing.T) {
+	script := []byte("#!/bin/sh\necho '{\"items\": []}'\n")
+	sum := sha256.Sum256(script)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/unreal":
+			w.Write(script)
+		case "/index.json":
+			w.Write([]byte(`{"plugins": [{"name": "unreal", "version": "1.0.0", "url": "` +
+				"http://" + r.Host + `/unreal", "sha256": "` + hex.EncodeToString(sum[:]) + `"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	idx, err := FetchIndex(srv.URL + "/index.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, ok := idx.Find("unreal")
+	if !ok {
+		t.Fatalf("index %+v has no unreal plugin", idx)
+	}
+
+	dir := t.TempDir()
+	if err := Install(dir, l); err != nil {
+		t.Fatal(err)
+	}
+	plugins, err := Discover(dir)
+	if err != nil || len(plugins) != 1 || plugins[0].Name != "unreal" {
+		t.Fatalf("Discover() = %+v, %v; want the installed plugin", plugins, err)
+	}
+
+	// A tampered download is refused and leaves nothing behind
+	bad := l
+	bad.Name = "tampered"
+	bad.SHA256 = hex.EncodeToString(make([]byte, sha256.Size))
+	if err := Install(dir, bad); err == nil {
+		t.Error("Install with a wrong checksum succeeded")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("plugins dir has %d entries after a failed install, want 1", len(entries))
+	}
+
+	bad.Name = "../escape"
+	if err := Install(dir, bad); err == nil {
+		t.Error("Install with a path in the name succeeded")
+	}
+}
+
+func TestEnableDisableRemove(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "acme", acmeScript, 0o755)
+
+	if err := SetEnabled(dir, "acme", false); err != nil {
+		t.Fatal(err)
+	}
+	if plugins, _ := Discover(dir); len(plugins) != 0 {
+		t.Errorf("disabled plugin is still loaded: %+v", plugins)
+	}
+	installed, err := ListInstalled(dir)
+	if err != nil || len(installed) != 1 || installed[0].Enabled {
+		t.Errorf("ListInstalled() = %+v, %v; want acme disabled", installed, err)
+	}
+
+	if err := SetEnabled(dir, "acme", true); err != nil {
+		t.Fatal(err)
+	}
+	if plugins, _ := Discover(dir); len(plugins) != 1 {
+		t.Errorf("enabled plugin is not loaded")
+	}
+
+	if err := Uninstall(dir, "acme"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "acme")); !os.IsNotExist(err) {
+		t.Errorf("acme still installed, stat err = %v", err)
+	}
+	if err := Uninstall(dir, "acme"); err == nil {
+		t.Error("removing a missing plugin succeeded")
+	}
+}
@@ -0,0 +1,191 @@
+// Package plugin runs external scanner executables. A plugin is any
+// executable file in the plugins directory. It is started once per request,
+// reads a single JSON request from stdin and writes a single JSON response
+// to stdout.
+//
+// Scan request and response:
+//
+//	{"action": "scan"}
+//	{"category": "Acme Build Cache", "items": [{"path": "/Users/me/.acme/cache", "size": 1024, "name": "Acme cache"}]}
+//
+// Clean request and response; an empty or missing error means the path was
+// removed:
+//
+//	{"action": "clean", "paths": ["/Users/me/.acme/cache"]}
+//	{"results": [{"path": "/Users/me/.acme/cache", "error": ""}]}
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// Timeouts for a single plugin invocation
+const (
+	scanTimeout  = 5 * time.Minute
+	cleanTimeout = time.Minute
+)
+
+// Plugin is an external scanner executable
+type Plugin struct {
+	Name string // File name, used as the category when the plugin gives none
+	Path string
+}
+
+type request struct {
+	Action string   `json:"action"`
+	Paths  []string `json:"paths,omitempty"`
+}
+
+type item struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Name string `json:"name"`
+}
+
+type scanResponse struct {
+	Category string `json:"category"`
+	Items    []item `json:"items"`
+}
+
+type cleanResult struct {
+	Path  string `json:"path"`
+	Error string `json:"error,omitempty"`
+}
+
+type cleanResponse struct {
+	Results []cleanResult `json:"results"`
+}
+
+// DefaultDir returns the directory plugins are loaded from
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "cleanwithcli", "plugins")
+}
+
+// Discover returns the executable files in dir, sorted by name. A missing
+// directory has no plugins.
+func Discover(dir string) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var plugins []*Plugin
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		plugins = append(plugins, &Plugin{
+			Name: entry.Name(),
+			Path: filepath.Join(dir, entry.Name()),
+		})
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, nil
+}
+
+// call runs the plugin with req on stdin and decodes its stdout into resp
+func (p *Plugin) call(ctx context.Context, req request, resp any) error {
+	in, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("plugin %s: %w: %s", p.Name, err, msg)
+		}
+		return fmt.Errorf("plugin %s: %w", p.Name, err)
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return fmt.Errorf("plugin %s: invalid response: %w", p.Name, err)
+	}
+	return nil
+}
+
+// Scan asks the plugin for its items. Failures are recorded as scan errors
+// on the result, and the plugin cleans its own items.
+func (p *Plugin) Scan() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: p.Name,
+		Items:    []types.FileItem{},
+		Remover:  p.Remove,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scanTimeout)
+	defer cancel()
+
+	var resp scanResponse
+	if err := p.call(ctx, request{Action: "scan"}, &resp); err != nil {
+		result.AddError(p.Path, err)
+		return result
+	}
+
+	if resp.Category != "" {
+		result.Category = resp.Category
+	}
+	for _, it := range resp.Items {
+		if it.Path == "" || it.Size <= 0 {
+			continue
+		}
+		name := it.Name
+		if name == "" {
+			name = filepath.Base(it.Path)
+		}
+		result.Items = append(result.Items, types.FileItem{
+			Path: it.Path,
+			Size: it.Size,
+			Name: name,
+		})
+		result.Total += it.Size
+	}
+	return result
+}
+
+// Remove asks the plugin to clean path
+func (p *Plugin) Remove(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanTimeout)
+	defer cancel()
+
+	var resp cleanResponse
+	if err := p.call(ctx, request{Action: "clean", Paths: []string{path}}, &resp); err != nil {
+		return types.NewPathError("remove", path, err)
+	}
+	for _, r := range resp.Results {
+		if r.Path == path {
+			if r.Error != "" {
+				return types.NewPathError("remove", path, errors.New(r.Error))
+			}
+			return nil
+		}
+	}
+	return types.NewPathError("remove", path, fmt.Errorf("plugin %s did not report a result", p.Name))
+}
@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// acmeScript answers scan requests with two items and fails to clean
+// anything under /locked
+const acmeScript = `#!/bin/sh
+req=$(cat)
+case "$req" in
+*'"scan"'*)
+	echo '{"category": "Acme Cache", "items": [{"path": "/acme/a", "size": 10, "name": "A"}, {"path": "/acme/b", "size": 5}, {"path": "/acme/empty", "size": 0}]}'
+	;;
+*'/locked'*)
+	echo '{"results": [{"path": "/locked", "error": "busy"}]}'
+	;;
+*)
+	echo '{"results": [{"path": "/acme/a"}]}'
+	;;
+esac
+`
+
+func writePlugin(t *testing.T, dir, name, script string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPluginProtocol(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "acme", acmeScript, 0o755)
+	writePlugin(t, dir, "broken", "#!/bin/sh\necho oops >&2\nexit 3\n", 0o755)
+	writePlugin(t, dir, "README", "not a plugin", 0o644)
+
+	plugins, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 2 || plugins[0].Name != "acme" || plugins[1].Name != "broken" {
+		t.Fatalf("Discover() = %+v, want acme and broken", plugins)
+	}
+
+	result := plugins[0].Scan()
+	if result.Category != "Acme Cache" || len(result.Items) != 2 || result.Total != 15 {
+		t.Errorf("Scan() = %+v, want 2 items totalling 15 in Acme Cache", result)
+	}
+	if len(result.Items) == 2 && result.Items[1].Name != "b" {
+		t.Errorf("unnamed item name = %q, want the base name", result.Items[1].Name)
+	}
+	if result.Remover == nil {
+		t.Error("plugin result has no remover")
+	}
+
+	if err := plugins[0].Remove("/acme/a"); err != nil {
+		t.Errorf("Remove(/acme/a) = %v", err)
+	}
+	var pathErr *types.PathError
+	if err := plugins[0].Remove("/locked"); !errors.As(err, &pathErr) {
+		t.Errorf("Remove(/locked) = %v, want a *types.PathError", err)
+	}
+
+	broken := plugins[1].Scan()
+	if broken.Category != "broken" || len(broken.Errors) != 1 {
+		t.Errorf("failing plugin: category = %q, errors = %v", broken.Category, broken.Errors)
+	}
+}
+
+func TestDiscoverMissingDir(t *testing.T) {
+	plugins, err := Discover(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || plugins != nil {
+		t.Errorf("Discover(missing) = %v, %v, want no plugins", plugins, err)
+	}
+}
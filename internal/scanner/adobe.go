@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "adobe", Category: "Adobe Caches", Risk: types.RiskLow, Sets: []string{SetFull}, Order: 68, Scan: (*Scanner).ScanAdobeCaches})
+}
+
+// adobeCaches are the default cache folders of Adobe apps, relative to the
+// home directory
+var adobeCaches = []struct {
+	path []string
+	name string
+}{
+	{[]string{"Library", "Application Support", "Adobe", "Common", "Media Cache Files"}, "Media Cache Files"},
+	{[]string{"Library", "Application Support", "Adobe", "Common", "Media Cache"}, "Media Cache database"},
+	{[]string{"Library", "Application Support", "Adobe", "Common", "Peak Files"}, "Peak Files"},
+	{[]string{"Library", "Caches", "Adobe", "After Effects"}, "After Effects disk cache"},
+	{[]string{"Library", "Caches", "Adobe", "Premiere Pro"}, "Premiere Pro cache"},
+	{[]string{"Library", "Caches", "Adobe Camera Raw"}, "Camera Raw cache"},
+	{[]string{"Library", "Caches", "Adobe Camera Raw 2"}, "Camera Raw cache"},
+}
+
+// ScanAdobeCaches scans the media caches of Premiere Pro and After Effects,
+// the Camera Raw cache, and the previews of Lightroom Classic catalogs in
+// ~/Pictures, which regularly reach tens of GB
+func (s *Scanner) ScanAdobeCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Adobe Caches",
+		Items:    []types.FileItem{},
+	}
+	add := func(path, name string) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: "🅰️ " + name, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	for _, cache := range adobeCaches {
+		add(filepath.Join(append([]string{s.HomeDir}, cache.path...)...), cache.name)
+	}
+
+	// Lightroom Classic keeps previews next to each catalog in "<catalog>
+	// Previews.lrdata". Smart previews are kept: they let photos be edited
+	// while their originals are offline.
+	picturesDir := filepath.Join(s.HomeDir, "Pictures")
+	s.walkDir(picturesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(path, err)
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if utils.ShouldSkipDir(path) || strings.HasSuffix(d.Name(), ".photoslibrary") {
+			return filepath.SkipDir
+		}
+		catalog, ok := strings.CutSuffix(d.Name(), " Previews.lrdata")
+		if !ok {
+			return nil
+		}
+		if !strings.HasSuffix(catalog, " Smart") {
+			add(path, "Lightroom previews: "+catalog)
+		}
+		return filepath.SkipDir
+	})
+
+	return result
+}
@@ -0,0 +1,206 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "android", Category: "Android SDK", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 195, Scan: (*Scanner).ScanAndroidSDK})
+}
+
+// androidSDKDir returns the Android SDK location, from ANDROID_HOME or
+// ANDROID_SDK_ROOT if set, otherwise where Android Studio installs it
+func (s *Scanner) androidSDKDir() string {
+	for _, env := range []string{"ANDROID_HOME", "ANDROID_SDK_ROOT"} {
+		if dir := os.Getenv(env); dir != "" {
+			return dir
+		}
+	}
+	return filepath.Join(s.HomeDir, "Library", "Android", "sdk")
+}
+
+// ScanAndroidSDK lists emulator system images by API level, platforms,
+// build tools and NDKs older than the newest installed, the legacy
+// ndk-bundle, and emulator virtual devices (AVDs) with the API level they
+// run
+func (s *Scanner) ScanAndroidSDK() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Android SDK",
+		Items:    []types.FileItem{},
+	}
+	add := func(path, name string) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: name, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	// AVDs first, so system images can say which devices use them
+	usedBy := make(map[string][]string)
+	avdDir := filepath.Join(s.HomeDir, ".android", "avd")
+	if entries, err := s.readDir(avdDir); err == nil {
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), ".avd")
+			if !ok || !entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(avdDir, entry.Name())
+			label := "🤖 Emulator: " + name
+			if image := avdSystemImage(filepath.Join(path, "config.ini")); image != "" {
+				usedBy[image] = append(usedBy[image], name)
+				label += " (" + apiLevel(strings.Split(image, "/")[1]) + ")"
+			}
+			add(path, label)
+		}
+	}
+
+	sdk := s.androidSDKDir()
+
+	imagesDir := filepath.Join(sdk, "system-images")
+	s.walkSDKLevels(imagesDir, 3, func(path string, parts []string) {
+		label := fmt.Sprintf("🤖 System image: %s %s %s", apiLevel(parts[0]), parts[1], parts[2])
+		if avds := usedBy[strings.Join(append([]string{"system-images"}, parts...), "/")]; len(avds) > 0 {
+			label += " (used by " + strings.Join(avds, ", ") + ")"
+		}
+		add(path, label)
+	})
+
+	var sideBySideNDK bool
+	for _, kind := range []struct{ dir, label string }{
+		{"platforms", "🤖 Platform: %s"},
+		{"build-tools", "🤖 Build tools %s"},
+		{"ndk", "🤖 NDK %s"},
+	} {
+		dir := filepath.Join(sdk, kind.dir)
+		entries, err := s.readDir(dir)
+		if err != nil {
+			continue
+		}
+		var versions []string
+		for _, entry := range entries {
+			if entry.IsDir() {
+				versions = append(versions, entry.Name())
+			}
+		}
+		if kind.dir == "ndk" {
+			sideBySideNDK = len(versions) > 0
+		}
+		// Keep the newest, which current projects most likely build against
+		sort.Slice(versions, func(i, j int) bool { return versionLess(versions[i], versions[j]) })
+		for _, version := range versions[:max(0, len(versions)-1)] {
+			name := version
+			if kind.dir == "platforms" {
+				name = apiLevel(version)
+			}
+			add(filepath.Join(dir, version), fmt.Sprintf(kind.label, name))
+		}
+	}
+
+	// The NDK Android Studio installed before side by side versions, kept
+	// when it's the only one
+	bundle := filepath.Join(sdk, "ndk-bundle")
+	if _, err := os.Stat(bundle); err == nil && sideBySideNDK {
+		label := "🤖 NDK (ndk-bundle)"
+		if version := sdkPackageRevision(filepath.Join(bundle, "source.properties")); version != "" {
+			label = "🤖 NDK " + version + " (ndk-bundle)"
+		}
+		add(bundle, label)
+	}
+
+	return result
+}
+
+// sdkPackageRevision reads Pkg.Revision from an SDK package's
+// source.properties
+func sdkPackageRevision(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "Pkg.Revision" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// walkSDKLevels calls fn for each directory depth levels below root, such
+// as system-images/android-34/google_apis/arm64-v8a, with the names of the
+// directories on the way
+func (s *Scanner) walkSDKLevels(root string, depth int, fn func(path string, parts []string)) {
+	var walk func(dir string, parts []string)
+	walk = func(dir string, parts []string) {
+		if len(parts) == depth {
+			fn(dir, parts)
+			return
+		}
+		entries, err := s.readDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				walk(filepath.Join(dir, entry.Name()), append(parts[:len(parts):len(parts)], entry.Name()))
+			}
+		}
+	}
+	walk(root, nil)
+}
+
+// avdSystemImage reads the system image an AVD boots from its config.ini,
+// e.g. "system-images/android-34/google_apis/arm64-v8a"
+func avdSystemImage(configPath string) string {
+	f, err := os.Open(configPath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), "=")
+		if ok && strings.TrimSpace(key) == "image.sysdir.1" {
+			image := strings.Trim(filepath.ToSlash(strings.TrimSpace(value)), "/")
+			if strings.Count(image, "/") == 3 {
+				return image
+			}
+		}
+	}
+	return ""
+}
+
+// apiLevel turns an SDK directory name such as "android-34" into "API 34"
+func apiLevel(dir string) string {
+	if level, ok := strings.CutPrefix(dir, "android-"); ok {
+		return "API " + level
+	}
+	return dir
+}
+
+// versionLess compares dotted versions such as build tools "33.0.2" and
+// platforms "android-34" numerically, falling back to string order
+func versionLess(a, b string) bool {
+	pa := strings.FieldsFunc(a, func(r rune) bool { return r == '.' || r == '-' })
+	pb := strings.FieldsFunc(b, func(r rune) bool { return r == '.' || r == '-' })
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			return na < nb
+		case (errA != nil || errB != nil) && pa[i] != pb[i]:
+			return pa[i] < pb[i]
+		}
+	}
+	return len(pa) < len(pb)
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "appledouble", Category: "AppleDouble Files", Risk: types.RiskLow, Sets: []string{SetFull}, Order: 36, Scan: (*Scanner).ScanAppleDouble})
+}
+
+// appleDoubleMagic starts every AppleDouble file
+var appleDoubleMagic = []byte{0x00, 0x05, 0x16, 0x07}
+
+// nativeMetadataFS are the file systems keeping extended attributes and
+// resource forks themselves, which never get AppleDouble files
+var nativeMetadataFS = []string{"apfs", "hfs"}
+
+// fsType returns the file system type of the mount holding path
+func (s *Scanner) fsType(path string) string {
+	if s.mountType != nil {
+		return s.mountType(path)
+	}
+	return utils.MountType(path)
+}
+
+// isAppleDouble reports whether path is an AppleDouble file, checking its
+// header so files that merely start with ._ are left alone
+func isAppleDouble(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, len(appleDoubleMagic))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, appleDoubleMagic)
+}
+
+// ScanAppleDouble finds the ._ files macOS writes next to files on FAT,
+// exFAT, NTFS and network volumes to hold their extended attributes and
+// resource forks, like dot_clean does. APFS and HFS+
+// volumes keep these natively and are skipped, as is the home folder.
+func (s *Scanner) ScanAppleDouble() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "AppleDouble Files",
+		Items:    []types.FileItem{},
+	}
+
+	roots := s.Roots
+	if len(roots) == 0 {
+		roots = s.volumes()
+	}
+	var foreign []string
+	for _, root := range roots {
+		// An unknown type means the volume is gone or unreadable
+		if fsType := s.fsType(root); fsType != "" && !contains(nativeMetadataFS, fsType) {
+			foreign = append(foreign, root)
+		}
+	}
+
+	s.addRootFiles(result, foreign, "🍏", func(path string, d fs.DirEntry) bool {
+		return strings.HasPrefix(d.Name(), "._") && isAppleDouble(path)
+	})
+	return result
+}
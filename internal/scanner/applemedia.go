@@ -0,0 +1,90 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "apple-media", Category: "Apple Media Downloads", Risk: types.RiskHigh, Sets: []string{SetFull}, Order: 66, Scan: (*Scanner).ScanAppleMediaDownloads})
+}
+
+// mediaExtensions are the file types of downloaded episodes, songs and
+// videos
+var mediaExtensions = []string{".mp3", ".m4a", ".m4p", ".aac", ".mp4", ".m4v", ".mov", ".movpkg"}
+
+// ScanAppleMediaDownloads reports the episodes, songs and videos the
+// Podcasts, TV and Music apps downloaded, and their caches. It is advisory:
+// the apps track downloads in their libraries, so they should remove them.
+func (s *Scanner) ScanAppleMediaDownloads() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Apple Media Downloads",
+		Items:    []types.FileItem{},
+		Advisory: true,
+		Hint:     "Remove downloads in the apps: in Podcasts, Settings › Remove played downloads; in Music and TV, select items and choose Remove Download.",
+	}
+	// add reports a folder, counting the media files in it when noun is set
+	add := func(path, name, noun string) {
+		size := s.dirSize(path)
+		if size == 0 {
+			return
+		}
+		if noun != "" {
+			if n := s.countMedia(path); n == 1 {
+				name += " (1 " + strings.TrimSuffix(noun, "s") + ")"
+			} else if n > 1 {
+				name += fmt.Sprintf(" (%d %s)", n, noun)
+			}
+		}
+		result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: name, IsDir: true})
+		result.Total += size
+	}
+
+	containers := filepath.Join(s.HomeDir, "Library", "Containers")
+	podcasts := filepath.Join(s.HomeDir, "Library", "Group Containers", "243LU875E5.groups.com.apple.podcasts", "Library", "Cache")
+	add(podcasts, "🎙️ Podcasts: downloaded episodes", "episodes")
+	add(filepath.Join(containers, "com.apple.podcasts", "Data", "Library", "Caches"), "🎙️ Podcasts: cache", "")
+
+	tvMedia := filepath.Join(s.HomeDir, "Movies", "TV", "Media")
+	entries, err := s.readDir(tvMedia)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(tvMedia, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			add(filepath.Join(tvMedia, entry.Name()), "📺 TV: "+entry.Name(), "videos")
+		}
+	}
+	add(filepath.Join(containers, "com.apple.TV", "Data", "Library", "Caches"), "📺 TV: cache", "")
+
+	// The Music library's media folder is Media.localized on newer systems
+	for _, media := range []string{"Media.localized", "Media"} {
+		add(filepath.Join(s.HomeDir, "Music", "Music", media, "Apple Music"), "🎵 Music: Apple Music downloads", "songs")
+	}
+
+	return result
+}
+
+// countMedia counts the episodes, songs and videos in dir
+func (s *Scanner) countMedia(dir string) int {
+	n := 0
+	s.walkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if contains(mediaExtensions, strings.ToLower(filepath.Ext(path))) {
+			n++
+			// HLS downloads are folders
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return n
+}
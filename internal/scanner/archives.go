@@ -0,0 +1,180 @@
+package scanner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "extracted-archives", Category: "Extracted Archives", Risk: types.RiskMedium, Sets: []string{SetFull}, Order: 42, Scan: (*Scanner).ScanExtractedArchives,
+		Strategy: func(s *Scanner) strategy.Strategy { return strategy.MoveToTrash(s.HomeDir) }})
+}
+
+// extractedMatch is the share of an archive's files that must be found,
+// with the same size, in the folder next to it
+const extractedMatch = 0.9
+
+// archiveExtensions are the archives checked, longest first so .tar.gz
+// wins over .gz
+var archiveExtensions = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// archiveFile is a file listed in an archive
+type archiveFile struct {
+	name string // Slash-separated path inside the archive
+	size int64
+}
+
+// archiveBase returns name without its archive extension, and whether it
+// has one
+func archiveBase(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) && len(name) > len(ext) {
+			return name[:len(name)-len(ext)], true
+		}
+	}
+	return "", false
+}
+
+// listArchive returns the files in a zip file or tarball, leaving out the
+// resource forks and Finder files macOS adds to zips
+func listArchive(file string) ([]archiveFile, error) {
+	var files []archiveFile
+	add := func(name string, size int64) {
+		// Some Windows zips separate with backslashes
+		name = strings.TrimPrefix(strings.ReplaceAll(name, `\`, "/"), "./")
+		if strings.HasPrefix(name, "__MACOSX/") || path.Base(name) == ".DS_Store" {
+			return
+		}
+		files = append(files, archiveFile{name: name, size: size})
+	}
+
+	if strings.HasSuffix(strings.ToLower(file), ".zip") {
+		r, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		for _, f := range r.File {
+			if !f.Mode().IsDir() {
+				add(f.Name, int64(f.UncompressedSize64))
+			}
+		}
+		return files, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if !strings.HasSuffix(strings.ToLower(file), ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag == tar.TypeReg {
+			add(h.Name, h.Size)
+		}
+	}
+}
+
+// extractedShare returns the share of files found, with the same size, in
+// dir. Archive Utility extracts an archive holding a single folder of the
+// same name as that folder, so the folder is also tried without it.
+func extractedShare(files []archiveFile, dir string) float64 {
+	if len(files) == 0 {
+		return 0
+	}
+	prefix := filepath.Base(dir) + "/"
+	found := 0
+	for _, f := range files {
+		for _, name := range []string{f.name, strings.TrimPrefix(f.name, prefix)} {
+			info, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(name)))
+			if err == nil && info.Mode().IsRegular() && info.Size() == f.size {
+				found++
+				break
+			}
+		}
+	}
+	return float64(found) / float64(len(files))
+}
+
+// ScanExtractedArchives finds zip files and tarballs in Downloads and on
+// the Desktop sitting next to a folder of the same name that holds their
+// files, so the space is taken twice. The archive is listed; to keep it
+// instead, delete the folder.
+func (s *Scanner) ScanExtractedArchives() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Extracted Archives",
+		Items:    []types.FileItem{},
+	}
+
+	for _, name := range []string{"Downloads", "Desktop"} {
+		dir := filepath.Join(s.HomeDir, name)
+		entries, err := s.readDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(dir, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			base, ok := archiveBase(entry.Name())
+			if !ok || !entry.Type().IsRegular() {
+				continue
+			}
+			folder := filepath.Join(dir, base)
+			if info, err := os.Stat(folder); err != nil || !info.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			files, err := listArchive(path)
+			if err != nil {
+				result.AddError(path, err)
+				continue
+			}
+			share := extractedShare(files, folder)
+			if share < extractedMatch {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || info.Size() == 0 {
+				continue
+			}
+			result.Items = append(result.Items, types.FileItem{
+				Path: path,
+				Size: info.Size(),
+				Name: fmt.Sprintf("🗜️ %s/%s (extracted to %s/, %.0f%% of files match)", name, entry.Name(), base, share*100),
+				Age:  int(time.Since(info.ModTime()).Hours() / 24),
+			})
+			result.Total += info.Size()
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "carthage", Category: "Carthage Artifacts", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 225, Scan: (*Scanner).ScanCarthageArtifacts})
+}
+
+// isCarthageProject reports whether dir declares Carthage dependencies
+func isCarthageProject(dir string) bool {
+	for _, name := range []string{"Cartfile", "Cartfile.resolved"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanCarthageArtifacts scans the Carthage/Build and Carthage/Checkouts
+// folders of projects with a Cartfile, and CarthageKit's cache of
+// downloaded dependencies and their builds
+func (s *Scanner) ScanCarthageArtifacts() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Carthage Artifacts",
+		Items:    []types.FileItem{},
+	}
+
+	cache := filepath.Join(s.HomeDir, "Library", "Caches", "org.carthage.CarthageKit")
+	if size := s.dirSize(cache); size > 0 {
+		result.Items = append(result.Items, types.FileItem{Path: cache, Size: size, Name: "Carthage cache", IsDir: true})
+		result.Total += size
+	}
+
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if utils.ShouldSkipDir(path) || d.Name() == "node_modules" {
+			return filepath.SkipDir
+		}
+		if d.Name() != "Carthage" || !isCarthageProject(filepath.Dir(path)) {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(s.HomeDir, filepath.Dir(path))
+		for _, name := range []string{"Build", "Checkouts"} {
+			dir := filepath.Join(path, name)
+			if size := s.dirSize(dir); size > 0 {
+				result.Items = append(result.Items, types.FileItem{
+					Path:  dir,
+					Size:  size,
+					Name:  fmt.Sprintf("📱 %s (Carthage/%s)", relPath, name),
+					IsDir: true,
+				})
+				result.Total += size
+			}
+		}
+		return filepath.SkipDir
+	})
+
+	return result
+}
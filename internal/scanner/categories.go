@@ -3,37 +3,74 @@ package scanner
 import (
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
 	"github.com/rahulvramesh/cleanWithCli/internal/types"
-	"github.com/rahulvramesh/cleanWithCli/internal/utils"
 )
 
-// ScanXcodeFiles scans Xcode build artifacts
+func init() {
+	Register(Registration{Name: "installers", Category: "macOS Installers", Risk: types.RiskLow, Sets: []string{SetFull}, Order: 50, Scan: (*Scanner).ScanInstallers})
+	Register(Registration{Name: "sound-libraries", Category: "Sound Libraries", Risk: types.RiskHigh, Sets: []string{SetFull}, Order: 60, Scan: (*Scanner).ScanSoundLibraries})
+	Register(Registration{Name: "speech-assets", Category: "Speech & ML Assets", Risk: types.RiskHigh, Sets: []string{SetFull}, Order: 70, Scan: (*Scanner).ScanSpeechAssets})
+	Register(Registration{Name: "xcode", Category: "Xcode Files", Risk: types.RiskMedium, Sets: []string{SetFull, SetDev}, Order: 80, Scan: (*Scanner).ScanXcodeFiles})
+	Register(Registration{Name: "js-caches", Category: "NPM/Yarn/PNPM Caches", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 150, Scan: (*Scanner).ScanNpmYarnCaches,
+		Strategy: (*Scanner).jsCacheStrategy})
+	Register(Registration{Name: "deno", Category: "Deno Cache", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 155, Scan: (*Scanner).ScanDenoCache})
+	Register(Registration{Name: "java", Category: "Java/JVM Artifacts", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 170, Scan: (*Scanner).ScanJavaArtifacts})
+	Register(Registration{Name: "ruby", Category: "Ruby Artifacts", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 180, Scan: (*Scanner).ScanRubyArtifacts,
+		Strategy: (*Scanner).rubyStrategy})
+	Register(Registration{Name: "ide-caches", Category: "IDE Caches", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 200, Scan: (*Scanner).ScanIDECaches})
+}
+
+// ScanXcodeFiles scans Xcode build artifacts and simulators. Simulators
+// are listed and deleted through simctl when Xcode is installed, so
+// CoreSimulator stays consistent; otherwise their folders are deleted.
 func (s *Scanner) ScanXcodeFiles() *types.ScanResult {
 	result := &types.ScanResult{
 		Category: "Xcode Files",
 		Items:    []types.FileItem{},
 	}
 
+	devicesDir := filepath.Join(s.HomeDir, "Library", "Developer", "CoreSimulator", "Devices")
+	derivedDir := filepath.Join(s.HomeDir, "Library", "Developer", "Xcode", "DerivedData")
+	archivesDir := filepath.Join(s.HomeDir, "Library", "Developer", "Xcode", "Archives")
 	xcodeDirs := []string{
-		filepath.Join(s.HomeDir, "Library", "Developer", "Xcode", "DerivedData"),
-		filepath.Join(s.HomeDir, "Library", "Developer", "Xcode", "Archives"),
-		filepath.Join(s.HomeDir, "Library", "Developer", "CoreSimulator", "Devices"),
+		derivedDir,
+		archivesDir,
+		devicesDir,
 	}
 
 	for _, dir := range xcodeDirs {
 		if _, err := os.Stat(dir); err != nil {
 			continue
 		}
+		if dir == devicesDir {
+			if remover, err := s.scanSimulators(result, dir); err == nil {
+				result.Remover = remover
+				result.Method = "delete, or xcrun simctl delete for simulators"
+				continue
+			}
+		}
+		if dir == derivedDir {
+			s.scanDerivedData(result, dir)
+			continue
+		}
+		if dir == archivesDir {
+			s.scanArchives(result, dir)
+			continue
+		}
 
-		entries, err := os.ReadDir(dir)
+		entries, err := s.readDir(dir)
 		if err != nil {
+			result.AddError(dir, err)
 			continue
 		}
 
 		for _, entry := range entries {
 			path := filepath.Join(dir, entry.Name())
-			size, _ := utils.GetDirSize(path)
+			size := s.dirSize(path)
 			if size > 0 {
 				result.Items = append(result.Items, types.FileItem{
 					Path: path,
@@ -48,94 +85,157 @@ func (s *Scanner) ScanXcodeFiles() *types.ScanResult {
 	return result
 }
 
-// ScanBrewCache scans Homebrew cache
-func (s *Scanner) ScanBrewCache() *types.ScanResult {
+// ScanInstallers scans "Install macOS" app bundles in /Applications and
+// update assets left in /Library/Updates after OS upgrades
+func (s *Scanner) ScanInstallers() *types.ScanResult {
 	result := &types.ScanResult{
-		Category: "Homebrew Cache",
+		Category: "macOS Installers",
 		Items:    []types.FileItem{},
 	}
 
-	brewCache := filepath.Join(s.HomeDir, "Library", "Caches", "Homebrew")
-	if _, err := os.Stat(brewCache); err != nil {
-		return result
-	}
-
-	entries, err := os.ReadDir(brewCache)
-	if err != nil {
-		return result
+	appsDir := s.systemPath("Applications")
+	if entries, err := s.readDir(appsDir); err == nil {
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, "Install macOS") || !strings.HasSuffix(name, ".app") {
+				continue
+			}
+			path := filepath.Join(appsDir, name)
+			size := s.dirSize(path)
+			if size > 0 {
+				result.Items = append(result.Items, types.FileItem{
+					Path:  path,
+					Size:  size,
+					Name:  "💿 " + strings.TrimSuffix(name, ".app"),
+					IsDir: true,
+				})
+				result.Total += size
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		result.AddError(appsDir, err)
 	}
 
-	for _, entry := range entries {
-		path := filepath.Join(brewCache, entry.Name())
-		size, _ := utils.GetDirSize(path)
-		result.Items = append(result.Items, types.FileItem{
-			Path: path,
-			Size: size,
-			Name: "Brew: " + entry.Name(),
-		})
-		result.Total += size
+	updatesDir := s.systemPath("Library", "Updates")
+	if entries, err := s.readDir(updatesDir); err == nil {
+		for _, entry := range entries {
+			path := filepath.Join(updatesDir, entry.Name())
+			size := s.dirSize(path)
+			if size > 0 {
+				result.Items = append(result.Items, types.FileItem{
+					Path:  path,
+					Size:  size,
+					Name:  "Update: " + entry.Name(),
+					IsDir: entry.IsDir(),
+				})
+				result.Total += size
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		result.AddError(updatesDir, err)
 	}
 
 	return result
 }
 
-// ScanGoArtifacts scans Go build artifacts and module cache
-func (s *Scanner) ScanGoArtifacts() *types.ScanResult {
+// ScanSoundLibraries reports the GarageBand and Logic sound library content.
+// It is advisory: the apps manage these files and should remove them.
+func (s *Scanner) ScanSoundLibraries() *types.ScanResult {
 	result := &types.ScanResult{
-		Category: "Go Artifacts",
+		Category: "Sound Libraries",
 		Items:    []types.FileItem{},
+		Advisory: true,
+		Hint:     "Remove sound packs in Logic Pro › Sound Library › Open Sound Library Manager, or from GarageBand › Sound Library.",
 	}
 
-	// Go module cache
-	goPath := os.Getenv("GOPATH")
-	if goPath == "" {
-		goPath = filepath.Join(s.HomeDir, "go")
-	}
-
-	goCaches := []string{
-		filepath.Join(goPath, "pkg", "mod"),
-		filepath.Join(s.HomeDir, ".cache", "go-build"),
-		filepath.Join(s.HomeDir, "Library", "Caches", "go-build"),
+	libraries := []struct {
+		path []string
+		name string
+	}{
+		{[]string{"Library", "Application Support", "GarageBand"}, "GarageBand instruments and loops"},
+		{[]string{"Library", "Application Support", "Logic"}, "Logic Pro sound library"},
+		{[]string{"Library", "Audio", "Apple Loops"}, "Apple Loops"},
 	}
 
-	for _, dir := range goCaches {
-		if _, err := os.Stat(dir); err == nil {
-			size, _ := utils.GetDirSize(dir)
-			if size > 0 {
-				result.Items = append(result.Items, types.FileItem{
-					Path: dir,
-					Size: size,
-					Name: "Go: " + filepath.Base(dir),
-				})
-				result.Total += size
-			}
+	for _, lib := range libraries {
+		path := s.systemPath(lib.path...)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		size := s.dirSize(path)
+		if size > 0 {
+			result.Items = append(result.Items, types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  "🎵 " + lib.name,
+				IsDir: true,
+			})
+			result.Total += size
 		}
 	}
 
 	return result
 }
 
-// ScanDockerArtifacts scans Docker artifacts
-func (s *Scanner) ScanDockerArtifacts() *types.ScanResult {
+// speechAssetKeywords pick the speech, dictation and Siri entries out of the
+// system's downloadable asset store
+var speechAssetKeywords = []string{"Siri", "Speech", "Dictation", "Voice", "TTS"}
+
+// ScanSpeechAssets reports offline speech, dictation and Siri assets and
+// on-device ML model data. It is advisory: macOS manages these files.
+func (s *Scanner) ScanSpeechAssets() *types.ScanResult {
 	result := &types.ScanResult{
-		Category: "Docker Artifacts",
+		Category: "Speech & ML Assets",
 		Items:    []types.FileItem{},
+		Advisory: true,
+		Hint:     "Managed by macOS. Remove voices in System Settings › Accessibility › Spoken Content; turning off Siri or Dictation lets macOS reclaim their assets.",
 	}
 
-	// Docker Desktop data
-	dockerData := filepath.Join(s.HomeDir, "Library", "Containers", "com.docker.docker", "Data")
-	if _, err := os.Stat(dockerData); err == nil {
-		size, _ := utils.GetDirSize(dockerData)
-		if size > 100*1024*1024 { // Only if > 100MB
+	add := func(path, name string) {
+		size := s.dirSize(path)
+		if size > 0 {
 			result.Items = append(result.Items, types.FileItem{
-				Path: dockerData,
-				Size: size,
-				Name: "Docker: Desktop Data",
+				Path:  path,
+				Size:  size,
+				Name:  name,
+				IsDir: true,
 			})
 			result.Total += size
 		}
 	}
 
+	fixed := []struct {
+		path string
+		name string
+	}{
+		{s.systemPath("System", "Library", "Speech"), "🗣 Speech voices"},
+		{filepath.Join(s.HomeDir, "Library", "Assistant"), "🗣 Siri and dictation data"},
+		{filepath.Join(s.HomeDir, "Library", "Trial"), "🧠 On-device ML experiment assets"},
+	}
+	for _, f := range fixed {
+		if _, err := os.Stat(f.path); err == nil {
+			add(f.path, f.name)
+		}
+	}
+
+	assetsDir := s.systemPath("System", "Library", "AssetsV2")
+	entries, err := s.readDir(assetsDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(assetsDir, err)
+		}
+		return result
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		for _, keyword := range speechAssetKeywords {
+			if strings.Contains(name, keyword) {
+				add(filepath.Join(assetsDir, name), "🧠 "+strings.TrimPrefix(name, "com_apple_MobileAsset_"))
+				break
+			}
+		}
+	}
+
 	return result
 }
 
@@ -155,7 +255,7 @@ func (s *Scanner) ScanIDECaches() *types.ScanResult {
 
 	for _, dir := range vscodeDirs {
 		if _, err := os.Stat(dir); err == nil {
-			size, _ := utils.GetDirSize(dir)
+			size := s.dirSize(dir)
 			if size > 0 {
 				result.Items = append(result.Items, types.FileItem{
 					Path: dir,
@@ -178,15 +278,16 @@ func (s *Scanner) ScanIDECaches() *types.ScanResult {
 			continue
 		}
 
-		entries, err := os.ReadDir(dir)
+		entries, err := s.readDir(dir)
 		if err != nil {
+			result.AddError(dir, err)
 			continue
 		}
 
 		for _, entry := range entries {
 			if entry.IsDir() {
 				path := filepath.Join(dir, entry.Name())
-				size, _ := utils.GetDirSize(path)
+				size := s.dirSize(path)
 				if size > 0 {
 					result.Items = append(result.Items, types.FileItem{
 						Path: path,
@@ -212,7 +313,7 @@ func (s *Scanner) ScanJavaArtifacts() *types.ScanResult {
 	// Maven cache
 	m2Repo := filepath.Join(s.HomeDir, ".m2", "repository")
 	if _, err := os.Stat(m2Repo); err == nil {
-		size, _ := utils.GetDirSize(m2Repo)
+		size := s.dirSize(m2Repo)
 		if size > 0 {
 			result.Items = append(result.Items, types.FileItem{
 				Path: m2Repo,
@@ -223,24 +324,127 @@ func (s *Scanner) ScanJavaArtifacts() *types.ScanResult {
 		}
 	}
 
-	// Gradle cache
-	gradleCache := filepath.Join(s.HomeDir, ".gradle", "caches")
-	if _, err := os.Stat(gradleCache); err == nil {
-		size, _ := utils.GetDirSize(gradleCache)
-		if size > 0 {
+	s.addGradle(result)
+	return result
+}
+
+// addGradle adds what Gradle keeps in ~/.gradle as separate items: each
+// cache, the build cache, each wrapper distribution and the daemon logs
+func (s *Scanner) addGradle(result *types.ScanResult) {
+	gradleHome := filepath.Join(s.HomeDir, ".gradle")
+	add := func(path, name string, isDir bool) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: name, IsDir: isDir})
+			result.Total += size
+		}
+	}
+
+	cachesDir := filepath.Join(gradleHome, "caches")
+	if entries, err := s.readDir(cachesDir); err == nil {
+		for _, entry := range entries {
+			name := entry.Name()
+			label := "Gradle: caches/" + name
+			switch {
+			case strings.HasPrefix(name, "build-cache"):
+				label = "Gradle: build cache (" + name + ")"
+			case name == "modules-2":
+				label = "Gradle: dependency cache"
+			case len(name) > 0 && name[0] >= '0' && name[0] <= '9':
+				label = "Gradle: caches for Gradle " + name
+			}
+			add(filepath.Join(cachesDir, name), label, entry.IsDir())
+		}
+	}
+
+	// One distribution per version, e.g. wrapper/dists/gradle-8.5-bin
+	distsDir := filepath.Join(gradleHome, "wrapper", "dists")
+	if entries, err := s.readDir(distsDir); err == nil {
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), "gradle-")
+			if !ok || !entry.IsDir() {
+				continue
+			}
+			label := "Gradle: wrapper " + name
+			if i := strings.LastIndexByte(name, '-'); i > 0 {
+				label = "Gradle: wrapper " + name[:i] + " (" + name[i+1:] + ")"
+			}
+			add(filepath.Join(distsDir, entry.Name()), label, true)
+		}
+	}
+
+	// Daemon logs, one directory per Gradle version
+	daemonDir := filepath.Join(gradleHome, "daemon")
+	versions, _ := s.readDir(daemonDir)
+	for _, version := range versions {
+		if !version.IsDir() {
+			continue
+		}
+		dir := filepath.Join(daemonDir, version.Name())
+		logs, _ := s.readDir(dir)
+		for _, log := range logs {
+			if log.IsDir() || !strings.HasSuffix(log.Name(), ".log") {
+				continue
+			}
+			info, err := log.Info()
+			if err != nil || info.Size() == 0 {
+				continue
+			}
 			result.Items = append(result.Items, types.FileItem{
-				Path: gradleCache,
-				Size: size,
-				Name: "Gradle: caches",
+				Path: filepath.Join(dir, log.Name()),
+				Size: info.Size(),
+				Name: "Gradle: daemon log " + version.Name() + "/" + log.Name(),
+				Age:  int(time.Since(info.ModTime()).Hours() / 24),
 			})
-			result.Total += size
+			result.Total += info.Size()
 		}
 	}
+}
+
+// denoFolders describes the folders of Deno's cache directory
+var denoFolders = map[string]string{
+	"deps":       "remote modules",
+	"gen":        "transpiled and type-checked code",
+	"npm":        "npm packages",
+	"registries": "registry metadata",
+	"remote":     "remote modules",
+}
 
+// ScanDenoCache scans Deno's cache directory, DENO_DIR or
+// ~/Library/Caches/deno, one item per folder such as deps and gen
+func (s *Scanner) ScanDenoCache() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Deno Cache",
+		Items:    []types.FileItem{},
+	}
+
+	denoDir := os.Getenv("DENO_DIR")
+	if denoDir == "" {
+		denoDir = filepath.Join(s.HomeDir, "Library", "Caches", "deno")
+	}
+	entries, err := s.readDir(denoDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(denoDir, err)
+		}
+		return result
+	}
+	for _, entry := range entries {
+		path := filepath.Join(denoDir, entry.Name())
+		size := s.dirSize(path)
+		if size == 0 {
+			continue
+		}
+		name := "Deno: " + entry.Name()
+		if about, ok := denoFolders[entry.Name()]; ok {
+			name += " (" + about + ")"
+		}
+		result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: name, IsDir: entry.IsDir()})
+		result.Total += size
+	}
 	return result
 }
 
-// ScanNpmYarnCaches scans NPM, Yarn, and PNPM caches
+// ScanNpmYarnCaches scans NPM, Yarn, PNPM and Bun caches
 func (s *Scanner) ScanNpmYarnCaches() *types.ScanResult {
 	result := &types.ScanResult{
 		Category: "NPM/Yarn/PNPM Caches",
@@ -255,12 +459,15 @@ func (s *Scanner) ScanNpmYarnCaches() *types.ScanResult {
 		{filepath.Join(s.HomeDir, "Library", "Caches", "npm"), "NPM cache (Library)"},
 		{filepath.Join(s.HomeDir, ".yarn", "cache"), "Yarn cache"},
 		{filepath.Join(s.HomeDir, "Library", "Caches", "Yarn"), "Yarn cache (Library)"},
-		{filepath.Join(s.HomeDir, ".pnpm-store"), "PNPM store"},
+		{s.pnpmStores()[0], "PNPM store"},
+		{s.pnpmStores()[1], "PNPM store (Library)"},
+		{filepath.Join(s.HomeDir, ".bun", "install", "cache"), "Bun cache"},
+		{filepath.Join(s.HomeDir, ".bun", "install", "global"), "Bun global packages"},
 	}
 
 	for _, cache := range nodeCaches {
 		if _, err := os.Stat(cache.path); err == nil {
-			size, _ := utils.GetDirSize(cache.path)
+			size := s.dirSize(cache.path)
 			if size > 0 {
 				result.Items = append(result.Items, types.FileItem{
 					Path: cache.path,
@@ -275,6 +482,29 @@ func (s *Scanner) ScanNpmYarnCaches() *types.ScanResult {
 	return result
 }
 
+// pnpmStores returns where pnpm keeps its content-addressable store: the
+// older ~/.pnpm-store and the current default on macOS
+func (s *Scanner) pnpmStores() []string {
+	return []string{
+		filepath.Join(s.HomeDir, ".pnpm-store"),
+		filepath.Join(s.HomeDir, "Library", "pnpm", "store"),
+	}
+}
+
+// jsCacheStrategy prunes the pnpm store with `pnpm store prune`, since
+// projects hard link their packages from it, cleans the npm cache through
+// npm, and deletes the other caches
+func (s *Scanner) jsCacheStrategy() strategy.Strategy {
+	prune := strategy.RunCommand("pnpm", "store", "prune")
+	paths := map[string]strategy.Strategy{
+		filepath.Join(s.HomeDir, ".npm"): &npmCache{s: s},
+	}
+	for _, store := range s.pnpmStores() {
+		paths[store] = prune
+	}
+	return strategy.Switch{Paths: paths, Fallback: s.deleter()}
+}
+
 // ScanRubyArtifacts scans Ruby gems and caches
 func (s *Scanner) ScanRubyArtifacts() *types.ScanResult {
 	result := &types.ScanResult{
@@ -283,13 +513,9 @@ func (s *Scanner) ScanRubyArtifacts() *types.ScanResult {
 	}
 
 	// Ruby gems
-	gemHome := os.Getenv("GEM_HOME")
-	if gemHome == "" {
-		gemHome = filepath.Join(s.HomeDir, ".gem")
-	}
-
+	gemHome := s.gemHome()
 	if _, err := os.Stat(gemHome); err == nil {
-		size, _ := utils.GetDirSize(gemHome)
+		size := s.dirSize(gemHome)
 		if size > 0 {
 			result.Items = append(result.Items, types.FileItem{
 				Path: gemHome,
@@ -303,7 +529,7 @@ func (s *Scanner) ScanRubyArtifacts() *types.ScanResult {
 	// Bundler
 	bundleCache := filepath.Join(s.HomeDir, ".bundle", "cache")
 	if _, err := os.Stat(bundleCache); err == nil {
-		size, _ := utils.GetDirSize(bundleCache)
+		size := s.dirSize(bundleCache)
 		if size > 0 {
 			result.Items = append(result.Items, types.FileItem{
 				Path: bundleCache,
@@ -317,25 +543,19 @@ func (s *Scanner) ScanRubyArtifacts() *types.ScanResult {
 	return result
 }
 
-// ScanCocoaPods scans CocoaPods cache
-func (s *Scanner) ScanCocoaPods() *types.ScanResult {
-	result := &types.ScanResult{
-		Category: "CocoaPods",
-		Items:    []types.FileItem{},
+// gemHome returns where RubyGems installs gems
+func (s *Scanner) gemHome() string {
+	if gemHome := os.Getenv("GEM_HOME"); gemHome != "" {
+		return gemHome
 	}
+	return filepath.Join(s.HomeDir, ".gem")
+}
 
-	cocoapodsCache := filepath.Join(s.HomeDir, "Library", "Caches", "CocoaPods")
-	if _, err := os.Stat(cocoapodsCache); err == nil {
-		size, _ := utils.GetDirSize(cocoapodsCache)
-		if size > 0 {
-			result.Items = append(result.Items, types.FileItem{
-				Path: cocoapodsCache,
-				Size: size,
-				Name: "CocoaPods cache",
-			})
-			result.Total += size
-		}
+// rubyStrategy leaves installed gems to `gem cleanup`, which only removes
+// versions nothing depends on, and deletes the Bundler cache
+func (s *Scanner) rubyStrategy() strategy.Strategy {
+	return strategy.Switch{
+		Paths:    map[string]strategy.Strategy{s.gemHome(): strategy.RunCommand("gem", "cleanup")},
+		Fallback: s.deleter(),
 	}
-
-	return result
 }
@@ -0,0 +1,71 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "chat-apps", Category: "Chat App Caches", Risk: types.RiskMedium, Sets: []string{SetFull}, Order: 16, Scan: (*Scanner).ScanChatAppCaches})
+}
+
+// chatApps are the Application Support folders of the Electron chat apps
+// listed under Chat App Caches rather than Electron App Caches
+var chatApps = []struct{ dir, name string }{
+	{"Slack", "Slack"},
+	{"discord", "Discord"},
+	{"discordptb", "Discord PTB"},
+	{"discordcanary", "Discord Canary"},
+}
+
+// ScanChatAppCaches scans the caches of Slack, Microsoft Teams and
+// Discord, including their App Store versions, and Zoom's data folder,
+// one item per app and cache
+func (s *Scanner) ScanChatAppCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Chat App Caches",
+		Items:    []types.FileItem{},
+	}
+	add := func(path, name string) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: "💬 " + name, IsDir: true})
+			result.Total += size
+		}
+	}
+	// addChromium adds the Chromium caches in an app's data folder
+	addChromium := func(dir, app string) {
+		for _, name := range electronCaches {
+			add(filepath.Join(dir, name), app+": "+name)
+		}
+	}
+
+	supportDir := filepath.Join(s.HomeDir, "Library", "Application Support")
+	containersDir := filepath.Join(s.HomeDir, "Library", "Containers")
+
+	for _, app := range chatApps {
+		addChromium(filepath.Join(supportDir, app.dir), app.name)
+	}
+	addChromium(filepath.Join(containersDir, "com.tinyspeck.slackmacgap", "Data", "Library", "Application Support", "Slack"), "Slack (App Store)")
+
+	// Classic Teams is an Electron app; the new one keeps WebView2
+	// profiles and its own caches in its container
+	addChromium(filepath.Join(supportDir, "Microsoft", "Teams"), "Teams classic")
+	teamsDir := filepath.Join(containersDir, "com.microsoft.teams2", "Data", "Library")
+	webViewDir := filepath.Join(teamsDir, "Application Support", "Microsoft", "MSTeams", "EBWebView")
+	profiles, err := s.readDir(webViewDir)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(webViewDir, err)
+	}
+	for _, profile := range profiles {
+		if profile.IsDir() {
+			addChromium(filepath.Join(webViewDir, profile.Name()), "Teams "+profile.Name())
+		}
+	}
+	add(filepath.Join(teamsDir, "Caches"), "Teams: Caches")
+
+	add(filepath.Join(supportDir, "zoom.us", "data"), "Zoom: data (signs you out and clears local chat history)")
+
+	return result
+}
@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "cocoapods", Category: "CocoaPods", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 220, Scan: (*Scanner).ScanCocoaPods})
+}
+
+// ScanCocoaPods scans the CocoaPods cache and the Pods folders of projects,
+// those next to a Podfile, which pod install recreates from Podfile.lock
+func (s *Scanner) ScanCocoaPods() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "CocoaPods",
+		Items:    []types.FileItem{},
+	}
+
+	cocoapodsCache := filepath.Join(s.HomeDir, "Library", "Caches", "CocoaPods")
+	if _, err := os.Stat(cocoapodsCache); err == nil {
+		size := s.dirSize(cocoapodsCache)
+		if size > 0 {
+			result.Items = append(result.Items, types.FileItem{
+				Path: cocoapodsCache,
+				Size: size,
+				Name: "CocoaPods cache",
+			})
+			result.Total += size
+		}
+	}
+
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if utils.ShouldSkipDir(path) || d.Name() == "node_modules" {
+			return filepath.SkipDir
+		}
+		if d.Name() != "Pods" {
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(filepath.Dir(path), "Podfile")); err != nil {
+			return nil
+		}
+
+		if size := s.dirSize(path); size > 0 {
+			relPath, _ := filepath.Rel(s.HomeDir, filepath.Dir(path))
+			result.Items = append(result.Items, types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  fmt.Sprintf("📱 %s (Pods)", relPath),
+				IsDir: true,
+			})
+			result.Total += size
+		}
+		return filepath.SkipDir
+	})
+
+	return result
+}
@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "conda", Category: "Conda Environments", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 115, Scan: (*Scanner).ScanCondaEnvironments})
+}
+
+// condaTimeout bounds a single conda call; conda is slow to start
+const condaTimeout = 2 * time.Minute
+
+// errNoConda is returned by runConda when neither conda nor mamba is
+// installed
+var errNoConda = errors.New("conda is not installed")
+
+// condaInstalls are the usual install locations of Anaconda, Miniconda,
+// Miniforge and micromamba, relative to the home directory
+var condaInstalls = []string{
+	".conda",
+	"anaconda3",
+	"miniconda3",
+	"miniforge3",
+	"mambaforge",
+	"micromamba",
+	filepath.Join("opt", "anaconda3"),
+	filepath.Join("opt", "miniconda3"),
+}
+
+// runConda runs conda, or mamba or micromamba, whose commands are the same,
+// and returns its combined output
+func (s *Scanner) runConda(args ...string) ([]byte, error) {
+	if s.conda != nil {
+		return s.conda(args...)
+	}
+	tool := ""
+	for _, name := range []string{"conda", "mamba", "micromamba"} {
+		if _, err := exec.LookPath(name); err == nil {
+			tool = name
+			break
+		}
+	}
+	if tool == "" {
+		return nil, errNoConda
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), condaTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, tool, args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return nil, fmt.Errorf("%s %s: %w: %s", tool, strings.Join(args[:min(2, len(args))], " "), err, msg)
+	}
+	return out, nil
+}
+
+// condaEnvironments returns the environment folders conda lists, those it
+// recorded in ~/.conda/environments.txt and those in the envs folder of
+// the usual installs, so environments are found without conda on PATH
+func (s *Scanner) condaEnvironments() []string {
+	var envs []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if path != "." && !seen[path] {
+			seen[path] = true
+			envs = append(envs, path)
+		}
+	}
+
+	if out, err := s.runConda("env", "list", "--json"); err == nil {
+		var list struct {
+			Envs []string `json:"envs"`
+		}
+		if json.Unmarshal(out, &list) == nil {
+			for _, env := range list.Envs {
+				add(env)
+			}
+		}
+	}
+	if f, err := os.Open(filepath.Join(s.HomeDir, ".conda", "environments.txt")); err == nil {
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if line := strings.TrimSpace(sc.Text()); line != "" {
+				add(line)
+			}
+		}
+		f.Close()
+	}
+	for _, install := range condaInstalls {
+		entries, _ := s.readDir(filepath.Join(s.HomeDir, install, "envs"))
+		for _, entry := range entries {
+			if entry.IsDir() {
+				add(filepath.Join(s.HomeDir, install, "envs", entry.Name()))
+			}
+		}
+	}
+	return envs
+}
+
+// ScanCondaEnvironments lists conda and mamba environments other than the
+// base install and the active one, noting when each was last used. They
+// are removed with `conda remove --all`, or deleted when conda is gone.
+func (s *Scanner) ScanCondaEnvironments() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Conda Environments",
+		Items:    []types.FileItem{},
+		Method:   "conda remove --all",
+	}
+
+	active := os.Getenv("CONDA_PREFIX")
+	envs := make(map[string]bool)
+	for _, path := range s.condaEnvironments() {
+		// Only environments have conda-meta; the base install also has
+		// condabin, and removing it would remove conda itself
+		if _, err := os.Stat(filepath.Join(path, "conda-meta")); err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(path, "condabin")); err == nil || path == active {
+			continue
+		}
+		size := s.dirSize(path)
+		if size == 0 {
+			continue
+		}
+
+		item := types.FileItem{Path: path, Size: size, IsDir: true}
+		if used, ok := condaLastUsed(path); ok {
+			item.Age = int(time.Since(used).Hours() / 24)
+			item.Name = fmt.Sprintf("🐍 Conda env: %s (last used %s)", filepath.Base(path), daysAgo(item.Age))
+		} else {
+			item.Name = "🐍 Conda env: " + filepath.Base(path)
+		}
+		result.Items = append(result.Items, item)
+		result.Total += size
+		envs[path] = true
+	}
+
+	result.Remover = func(path string) error {
+		if !envs[path] {
+			return s.deleter().Remove(path)
+		}
+		if _, err := s.runConda("remove", "--all", "--prefix", path, "--yes"); err != nil {
+			if errors.Is(err, errNoConda) {
+				return s.deleter().Remove(path)
+			}
+			return types.NewPathError("remove", path, err)
+		}
+		return nil
+	}
+	return result
+}
+
+// condaLastUsed estimates when an environment was last used: conda doesn't
+// record activations, so it takes the later of when its Python was last
+// run and when packages were last installed into it
+func condaLastUsed(env string) (time.Time, bool) {
+	var last time.Time
+	if info, err := os.Stat(filepath.Join(env, "bin", "python")); err == nil {
+		last = utils.AccessTime(info)
+	}
+	if info, err := os.Stat(filepath.Join(env, "conda-meta", "history")); err == nil && info.ModTime().After(last) {
+		last = info.ModTime()
+	}
+	return last, !last.IsZero()
+}
+
+// daysAgo describes a number of days in the past, e.g. "3 days ago"
+func daysAgo(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
@@ -10,6 +10,14 @@ import (
 	"github.com/rahulvramesh/cleanWithCli/internal/utils"
 )
 
+func init() {
+	Register(Registration{Name: "node-modules", Category: "Node Modules", Risk: types.RiskMedium, Sets: []string{SetFull, SetDev}, Order: 100, Scan: (*Scanner).ScanNodeModules})
+	Register(Registration{Name: "python", Category: "Python Artifacts", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 110, Scan: (*Scanner).ScanPythonArtifacts})
+	Register(Registration{Name: "rust", Category: "Rust Artifacts", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 120, Scan: (*Scanner).ScanRustArtifacts})
+	Register(Registration{Name: "build-artifacts", Category: "Build Artifacts", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 130, Scan: (*Scanner).ScanBuildArtifacts})
+	Register(Registration{Name: "unity", Category: "Unity Artifacts", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 135, Scan: (*Scanner).ScanUnityArtifacts})
+}
+
 // ScanNodeModules scans for node_modules directories
 func (s *Scanner) ScanNodeModules() *types.ScanResult {
 	result := &types.ScanResult{
@@ -18,8 +26,9 @@ func (s *Scanner) ScanNodeModules() *types.ScanResult {
 	}
 
 	// Deep scan entire home directory
-	filepath.WalkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
+			result.AddError(path, err)
 			return nil
 		}
 
@@ -29,15 +38,19 @@ func (s *Scanner) ScanNodeModules() *types.ScanResult {
 			}
 
 			if d.Name() == "node_modules" {
-				size, _ := utils.GetDirSize(path)
+				size := s.dirSize(path)
 				if size > 0 {
 					// Get project path for better context
 					projectPath := filepath.Dir(path)
 					relPath, _ := filepath.Rel(s.HomeDir, projectPath)
+					name := fmt.Sprintf("📦 %s", relPath)
+					if isBunProject(projectPath) {
+						name += " (Bun)"
+					}
 					result.Items = append(result.Items, types.FileItem{
 						Path:  path,
 						Size:  size,
-						Name:  fmt.Sprintf("📦 %s", relPath),
+						Name:  name,
 						IsDir: true,
 					})
 					result.Total += size
@@ -52,6 +65,17 @@ func (s *Scanner) ScanNodeModules() *types.ScanResult {
 	return result
 }
 
+// isBunProject reports whether the project in dir installs its
+// dependencies with Bun, which reinstalls them from its cache in seconds
+func isBunProject(dir string) bool {
+	for _, lockfile := range []string{"bun.lockb", "bun.lock"} {
+		if _, err := os.Stat(filepath.Join(dir, lockfile)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // ScanPythonArtifacts scans Python virtual environments and caches
 func (s *Scanner) ScanPythonArtifacts() *types.ScanResult {
 	result := &types.ScanResult{
@@ -69,7 +93,7 @@ func (s *Scanner) ScanPythonArtifacts() *types.ScanResult {
 	// Add Python cache directories
 	for _, dir := range pythonCaches {
 		if _, err := os.Stat(dir); err == nil {
-			size, _ := utils.GetDirSize(dir)
+			size := s.dirSize(dir)
 			if size > 0 {
 				result.Items = append(result.Items, types.FileItem{
 					Path: dir,
@@ -82,8 +106,9 @@ func (s *Scanner) ScanPythonArtifacts() *types.ScanResult {
 	}
 
 	// Deep scan for Python virtual environments and caches
-	filepath.WalkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
+			result.AddError(path, err)
 			return nil
 		}
 
@@ -96,7 +121,11 @@ func (s *Scanner) ScanPythonArtifacts() *types.ScanResult {
 			if name == "__pycache__" || name == "venv" || name == ".venv" ||
 				name == "env" || name == ".env" || name == "virtualenv" ||
 				name == ".pytest_cache" || name == ".tox" || name == ".mypy_cache" {
-				size, _ := utils.GetDirSize(path)
+				// Conda environments are listed under Conda Environments
+				if _, err := os.Stat(filepath.Join(path, "conda-meta")); err == nil {
+					return filepath.SkipDir
+				}
+				size := s.dirSize(path)
 				if size > 0 {
 					projectPath := filepath.Dir(path)
 					relPath, _ := filepath.Rel(s.HomeDir, projectPath)
@@ -133,7 +162,7 @@ func (s *Scanner) ScanRustArtifacts() *types.ScanResult {
 
 	registryCache := filepath.Join(cargoHome, "registry", "cache")
 	if _, err := os.Stat(registryCache); err == nil {
-		size, _ := utils.GetDirSize(registryCache)
+		size := s.dirSize(registryCache)
 		if size > 0 {
 			result.Items = append(result.Items, types.FileItem{
 				Path:  registryCache,
@@ -146,8 +175,9 @@ func (s *Scanner) ScanRustArtifacts() *types.ScanResult {
 	}
 
 	// Deep scan for Rust target directories
-	filepath.WalkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
+			result.AddError(path, err)
 			return nil
 		}
 
@@ -159,7 +189,7 @@ func (s *Scanner) ScanRustArtifacts() *types.ScanResult {
 			if d.Name() == "target" {
 				// Check if it's a Rust project (has Cargo.toml in parent)
 				if _, err := os.Stat(filepath.Join(filepath.Dir(path), "Cargo.toml")); err == nil {
-					size, _ := utils.GetDirSize(path)
+					size := s.dirSize(path)
 					if size > 0 {
 						projectPath := filepath.Dir(path)
 						relPath, _ := filepath.Rel(s.HomeDir, projectPath)
@@ -190,8 +220,9 @@ func (s *Scanner) ScanBuildArtifacts() *types.ScanResult {
 	}
 
 	// Deep scan for various build directories
-	filepath.WalkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
+			result.AddError(path, err)
 			return nil
 		}
 
@@ -209,7 +240,7 @@ func (s *Scanner) ScanBuildArtifacts() *types.ScanResult {
 				// Check if it's likely a project build dir (has package.json, Cargo.toml, etc. in parent)
 				parentDir := filepath.Dir(path)
 				if utils.IsProjectDir(parentDir) {
-					size, _ := utils.GetDirSize(path)
+					size := s.dirSize(path)
 					if size > 0 {
 						relPath, _ := filepath.Rel(s.HomeDir, parentDir)
 						result.Items = append(result.Items, types.FileItem{
@@ -230,3 +261,60 @@ func (s *Scanner) ScanBuildArtifacts() *types.ScanResult {
 
 	return result
 }
+
+// unityGenerated are the folders Unity regenerates inside a project
+var unityGenerated = []string{"Library", "Temp", "Logs"}
+
+// isUnityProject reports whether dir holds a Unity project, which has both
+// an Assets and a ProjectSettings folder
+func isUnityProject(dir string) bool {
+	for _, name := range []string{"Assets", "ProjectSettings"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// ScanUnityArtifacts finds Unity projects and lists the Library, Temp and
+// Logs folders Unity generates in each
+func (s *Scanner) ScanUnityArtifacts() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Unity Artifacts",
+		Items:    []types.FileItem{},
+	}
+
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if utils.ShouldSkipDir(path) || d.Name() == "node_modules" {
+			return filepath.SkipDir
+		}
+		if !isUnityProject(path) {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(s.HomeDir, path)
+		for _, name := range unityGenerated {
+			dir := filepath.Join(path, name)
+			if size := s.dirSize(dir); size > 0 {
+				result.Items = append(result.Items, types.FileItem{
+					Path:  dir,
+					Size:  size,
+					Name:  fmt.Sprintf("🎮 %s (%s)", relPath, name),
+					IsDir: true,
+				})
+				result.Total += size
+			}
+		}
+		// Nested projects are rare, and Library holds thousands of files
+		return filepath.SkipDir
+	})
+
+	return result
+}
@@ -0,0 +1,305 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "docker", Category: "Docker Artifacts", Risk: types.RiskHigh, Sets: []string{SetDev}, Order: 190, Scan: (*Scanner).ScanDockerArtifacts})
+}
+
+// dockerTimeout bounds a single docker CLI call, so a stuck daemon doesn't
+// hold up the scan
+const dockerTimeout = 30 * time.Second
+
+// Docker items are not files; their paths name the object to remove
+const dockerScheme = "docker://"
+
+// ScanDockerArtifacts lists dangling and unused images, stopped
+// containers, unused volumes and build cache entries when the Docker daemon
+// is reachable, through its API or else the docker CLI. Otherwise it falls
+// back to the size of the Docker Desktop data directory as a single item,
+// report-only: what in it could go is unknown without the daemon, and
+// deleting it would take every image, container and volume with it.
+func (s *Scanner) ScanDockerArtifacts() *types.ScanResult {
+	if result, err := s.scanDocker(false); err == nil {
+		return result
+	}
+
+	result := &types.ScanResult{
+		Category: "Docker Artifacts",
+		Items:    []types.FileItem{},
+		Advisory: true,
+	}
+
+	// Docker Desktop data
+	dockerData := filepath.Join(s.HomeDir, "Library", "Containers", "com.docker.docker", "Data")
+	if _, err := os.Stat(dockerData); err == nil {
+		size := s.dirSize(dockerData)
+		if size > 100*1024*1024 { // Only if > 100MB
+			result.Items = append(result.Items, types.FileItem{
+				Path: dockerData,
+				Size: size,
+				Name: "Docker: Desktop Data (start Docker to list what can be removed)",
+			})
+			result.Total += size
+		}
+	}
+
+	return result
+}
+
+// runDocker runs the docker CLI and returns its standard output
+func (s *Scanner) runDocker(args ...string) ([]byte, error) {
+	if s.docker != nil {
+		return s.docker(args...)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dockerTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("docker %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("docker %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// dockerDF is the part of `docker system df -v --format '{{json .}}'` used
+// here. Sizes and times are formatted for humans, e.g. "1.2GB" and
+// "2 weeks ago".
+type dockerDF struct {
+	Images []struct {
+		ID           string
+		Repository   string
+		Tag          string
+		Size         string
+		UniqueSize   string // Space only this image uses, freed by removing it
+		CreatedSince string
+		Containers   string
+	}
+	Containers []struct {
+		ID         string
+		Names      string
+		Image      string
+		State      string
+		Size       string // e.g. "2kB (virtual 190MB)"; only the first part is the container's own
+		RunningFor string
+	}
+	Volumes []struct {
+		Name  string
+		Size  string
+		Links string
+	}
+	BuildCache []struct {
+		ID            string
+		CacheType     string
+		Description   string
+		Size          string
+		LastUsedSince string
+		CreatedSince  string
+		InUse         any // A bool, or "true"/"false" in some versions
+	}
+}
+
+// scanDocker lists the Docker objects nothing uses through the Engine API,
+// or the docker CLI when the daemon's socket can't be reached. Node images
+// of local Kubernetes clusters are listed on their own when nodeImages is
+// set, and left out otherwise.
+func (s *Scanner) scanDocker(nodeImages bool) (*types.ScanResult, error) {
+	if result, err := s.scanDockerAPI(nodeImages); err == nil {
+		return result, nil
+	}
+	return s.scanDockerObjects(nodeImages)
+}
+
+// scanDockerObjects asks the docker CLI for images, containers, volumes and
+// build cache entries that nothing uses, one item each; see scanDocker for
+// nodeImages
+func (s *Scanner) scanDockerObjects(nodeImages bool) (*types.ScanResult, error) {
+	out, err := s.runDocker("system", "df", "-v", "--format", "{{json .}}")
+	if err != nil {
+		return nil, err
+	}
+	var df dockerDF
+	if err := json.Unmarshal(out, &df); err != nil {
+		return nil, fmt.Errorf("docker system df: %w", err)
+	}
+
+	result := &types.ScanResult{
+		Category: "Docker Artifacts",
+		Items:    []types.FileItem{},
+		Remover:  s.removeDockerObject,
+		Method:   "docker image rm, container rm, volume rm or builder prune",
+	}
+	add := func(kind, id, name, size, since string) {
+		n, err := humanize.ParseBytes(size)
+		if err != nil || n == 0 {
+			return
+		}
+		result.Items = append(result.Items, types.FileItem{
+			Path: dockerScheme + kind + "/" + id,
+			Size: int64(n),
+			Name: name,
+			Age:  sinceDays(since),
+		})
+		result.Total += int64(n)
+	}
+
+	for _, img := range df.Images {
+		if n, _ := strconv.Atoi(img.Containers); n > 0 {
+			continue
+		}
+		tool := kubeNodeImage(img.Repository)
+		if (tool != "") != nodeImages {
+			continue
+		}
+		name := "🐳 Image: " + img.Repository + ":" + img.Tag
+		switch {
+		case tool != "":
+			name = fmt.Sprintf("☸️ %s node image: %s", tool, img.Tag)
+		case img.Repository == "<none>":
+			name = "🐳 Dangling image: " + shortID(img.ID)
+		}
+		size := img.UniqueSize
+		if size == "" {
+			size = img.Size
+		}
+		add("image", img.ID, name, size, img.CreatedSince)
+	}
+	if nodeImages {
+		return result, nil
+	}
+	for _, c := range df.Containers {
+		if c.State == "running" || c.State == "paused" || c.State == "restarting" {
+			continue
+		}
+		size, _, _ := strings.Cut(c.Size, " ")
+		add("container", c.ID, fmt.Sprintf("🐳 Container: %s (%s, %s)", c.Names, c.State, c.Image), size, c.RunningFor)
+	}
+	for _, vol := range df.Volumes {
+		if n, _ := strconv.Atoi(vol.Links); n > 0 {
+			continue
+		}
+		add("volume", vol.Name, "🐳 Volume: "+vol.Name, vol.Size, "")
+	}
+	for _, bc := range df.BuildCache {
+		if bc.InUse == true || bc.InUse == "true" {
+			continue
+		}
+		since := bc.LastUsedSince
+		if since == "" {
+			since = bc.CreatedSince
+		}
+		name := "🐳 Build cache: " + bc.CacheType + " " + shortID(bc.ID)
+		if bc.Description != "" {
+			name += " " + bc.Description
+		}
+		add("build-cache", bc.ID, name, bc.Size, since)
+	}
+	return result, nil
+}
+
+// removeDockerObject removes the image, volume or build cache entry an
+// item path refers to
+func (s *Scanner) removeDockerObject(path string) error {
+	kind, id, err := parseDockerPath(path)
+	if err != nil {
+		return types.NewPathError("remove", path, err)
+	}
+
+	var args []string
+	switch kind {
+	case "image":
+		args = []string{"image", "rm", id}
+	case "container":
+		args = []string{"container", "rm", id}
+	case "volume":
+		args = []string{"volume", "rm", id}
+	case "build-cache":
+		args = []string{"builder", "prune", "--force", "--filter", "id=" + id}
+	default:
+		return types.NewPathError("remove", path, fmt.Errorf("unknown docker object %q", kind))
+	}
+	if _, err := s.runDocker(args...); err != nil {
+		return types.NewPathError("remove", path, err)
+	}
+	return nil
+}
+
+// kubeNodeImage returns the local Kubernetes tool whose cluster nodes run
+// from images of repo, such as "kind" for kindest/node, or "" for others
+func kubeNodeImage(repo string) string {
+	switch {
+	case repo == "kindest/node":
+		return "kind"
+	case strings.HasSuffix(repo, "rancher/k3s"), strings.HasPrefix(repo, "ghcr.io/k3d-io/"), strings.HasPrefix(repo, "rancher/k3d-"):
+		return "k3d"
+	case strings.HasSuffix(repo, "k8s-minikube/kicbase"):
+		return "minikube"
+	}
+	return ""
+}
+
+// parseDockerPath splits an item path such as docker://volume/pgdata into
+// the kind of object and its ID
+func parseDockerPath(path string) (kind, id string, err error) {
+	kind, id, ok := strings.Cut(strings.TrimPrefix(path, dockerScheme), "/")
+	if !ok || !strings.HasPrefix(path, dockerScheme) || id == "" {
+		return "", "", fmt.Errorf("not a docker object")
+	}
+	return kind, id, nil
+}
+
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+var sinceRe = regexp.MustCompile(`^(\d+|an?|about an?) (second|minute|hour|day|week|month|year)s? ago$`)
+
+// sinceDays converts Docker's relative times, such as "3 weeks ago" or
+// "About an hour ago", into whole days
+func sinceDays(since string) int {
+	m := sinceRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(since)))
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		n = 1
+	}
+	switch m[2] {
+	case "day":
+		return n
+	case "week":
+		return n * 7
+	case "month":
+		return n * 30
+	case "year":
+		return n * 365
+	}
+	return 0
+}
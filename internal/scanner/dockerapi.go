@@ -0,0 +1,263 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// dockerSocket returns the Unix socket the Docker daemon listens on: the
+// one DOCKER_HOST names, Docker Desktop's per-user socket or the system one
+func (s *Scanner) dockerSocket() (string, error) {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		if sock, ok := strings.CutPrefix(host, "unix://"); ok {
+			return sock, nil
+		}
+		return "", fmt.Errorf("DOCKER_HOST %s is not a Unix socket", host)
+	}
+	candidates := []string{
+		filepath.Join(s.HomeDir, ".docker", "run", "docker.sock"),
+		s.systemPath("var", "run", "docker.sock"),
+	}
+	for _, sock := range candidates {
+		if _, err := os.Stat(sock); err == nil {
+			return sock, nil
+		}
+	}
+	return "", errors.New("no Docker socket found")
+}
+
+// callDockerAPI sends a request to the Docker Engine API and returns the
+// response body, turning error responses into errors carrying the daemon's
+// message
+func (s *Scanner) callDockerAPI(method, path string) ([]byte, error) {
+	if s.dockerAPI != nil {
+		return s.dockerAPI(method, path)
+	}
+	sock, err := s.dockerSocket()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dockerTimeout)
+	defer cancel()
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		},
+	}}
+	req, err := http.NewRequestWithContext(ctx, method, "http://docker"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("docker API %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("docker API %s: %w", path, err)
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct{ Message string }
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("docker API %s: %s", path, apiErr.Message)
+		}
+		return nil, fmt.Errorf("docker API %s: %s", path, resp.Status)
+	}
+	return body, nil
+}
+
+// dockerAPIDF is the part of the Engine API's GET /system/df response used
+// here. Unlike the CLI, sizes are in bytes and times are exact.
+type dockerAPIDF struct {
+	Images []struct {
+		ID         string `json:"Id"`
+		RepoTags   []string
+		Created    int64 // Unix time
+		Size       int64
+		SharedSize int64 // -1 when not computed
+		Containers int64
+	}
+	Containers []struct {
+		ID      string `json:"Id"`
+		Names   []string
+		Image   string
+		State   string
+		Created int64 // Unix time
+		SizeRw  int64 // What the container wrote on top of its image
+	}
+	Volumes []struct {
+		Name      string
+		UsageData *struct {
+			Size     int64
+			RefCount int64
+		}
+	}
+	BuildCache []struct {
+		ID          string
+		Type        string
+		Description string
+		InUse       bool
+		Size        int64
+		CreatedAt   time.Time
+		LastUsedAt  *time.Time
+	}
+}
+
+// scanDockerAPI asks the daemon, through the Engine API, for dangling and
+// unused images, stopped containers, unused volumes and build cache entries
+// nothing uses, one item each; see scanDocker for
+// nodeImages
+func (s *Scanner) scanDockerAPI(nodeImages bool) (*types.ScanResult, error) {
+	out, err := s.callDockerAPI(http.MethodGet, "/system/df")
+	if err != nil {
+		return nil, err
+	}
+	var df dockerAPIDF
+	if err := json.Unmarshal(out, &df); err != nil {
+		return nil, fmt.Errorf("docker API /system/df: %w", err)
+	}
+
+	result := &types.ScanResult{
+		Category: "Docker Artifacts",
+		Items:    []types.FileItem{},
+		Remover:  s.removeDockerObjectAPI,
+		Method:   "Docker API: remove image, container or volume, or prune build cache",
+	}
+	add := func(kind, id, name string, size int64, at time.Time) {
+		if size <= 0 {
+			return
+		}
+		item := types.FileItem{Path: dockerScheme + kind + "/" + id, Size: size, Name: name}
+		if !at.IsZero() {
+			item.Age = int(time.Since(at).Hours() / 24)
+		}
+		result.Items = append(result.Items, item)
+		result.Total += size
+	}
+
+	for _, img := range df.Images {
+		if img.Containers > 0 {
+			continue
+		}
+		size := img.Size
+		if img.SharedSize > 0 {
+			size -= img.SharedSize
+		}
+		tags := taggedOnly(img.RepoTags)
+		var tool string
+		if len(tags) > 0 {
+			repo, _ := splitImageTag(tags[0])
+			tool = kubeNodeImage(repo)
+		}
+		if (tool != "") != nodeImages {
+			continue
+		}
+		name := "🐳 Dangling image: " + shortID(img.ID)
+		switch {
+		case tool != "":
+			_, tag := splitImageTag(tags[0])
+			name = fmt.Sprintf("☸️ %s node image: %s", tool, tag)
+		case len(tags) > 0:
+			name = "🐳 Image: " + strings.Join(tags, ", ")
+		}
+		add("image", img.ID, name, size, time.Unix(img.Created, 0))
+	}
+	if nodeImages {
+		return result, nil
+	}
+	for _, c := range df.Containers {
+		if c.State == "running" || c.State == "paused" || c.State == "restarting" {
+			continue
+		}
+		name := shortID(c.ID)
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		add("container", c.ID, fmt.Sprintf("🐳 Container: %s (%s, %s)", name, c.State, c.Image), c.SizeRw, time.Unix(c.Created, 0))
+	}
+	for _, vol := range df.Volumes {
+		if vol.UsageData == nil || vol.UsageData.RefCount > 0 {
+			continue
+		}
+		add("volume", vol.Name, "🐳 Volume: "+vol.Name, vol.UsageData.Size, time.Time{})
+	}
+	for _, bc := range df.BuildCache {
+		if bc.InUse {
+			continue
+		}
+		used := bc.CreatedAt
+		if bc.LastUsedAt != nil {
+			used = *bc.LastUsedAt
+		}
+		name := "🐳 Build cache: " + bc.Type + " " + shortID(bc.ID)
+		if bc.Description != "" {
+			name += " " + bc.Description
+		}
+		add("build-cache", bc.ID, name, bc.Size, used)
+	}
+	return result, nil
+}
+
+// taggedOnly leaves out the "<none>:<none>" tag dangling images report
+func taggedOnly(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		if tag != "<none>:<none>" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// splitImageTag splits a reference such as localhost:5000/app:v1 into its
+// repository and tag
+func splitImageTag(ref string) (repo, tag string) {
+	if i := strings.LastIndexByte(ref, ':'); i > strings.LastIndexByte(ref, '/') {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, "latest"
+}
+
+// removeDockerObjectAPI removes the object an item path refers to through
+// the Engine API, pruning build cache entries by ID
+func (s *Scanner) removeDockerObjectAPI(path string) error {
+	kind, id, err := parseDockerPath(path)
+	if err != nil {
+		return types.NewPathError("remove", path, err)
+	}
+
+	var method, endpoint string
+	switch kind {
+	case "image":
+		method, endpoint = http.MethodDelete, "/images/"+url.PathEscape(id)
+	case "container":
+		method, endpoint = http.MethodDelete, "/containers/"+url.PathEscape(id)
+	case "volume":
+		method, endpoint = http.MethodDelete, "/volumes/"+url.PathEscape(id)
+	case "build-cache":
+		filters, _ := json.Marshal(map[string][]string{"id": {id}})
+		method, endpoint = http.MethodPost, "/build/prune?filters="+url.QueryEscape(string(filters))
+	default:
+		return types.NewPathError("remove", path, fmt.Errorf("unknown docker object %q", kind))
+	}
+	if _, err := s.callDockerAPI(method, endpoint); err != nil {
+		return types.NewPathError("remove", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "dotnet", Category: ".NET Artifacts", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 175, Scan: (*Scanner).ScanDotnetArtifacts})
+}
+
+// dotnetProjectExts are the project files the .NET SDK builds into bin and
+// obj next to them
+var dotnetProjectExts = []string{".csproj", ".fsproj", ".vbproj", ".sln"}
+
+// ScanDotnetArtifacts scans the NuGet package and HTTP caches, and the bin
+// and obj folders of .NET projects
+func (s *Scanner) ScanDotnetArtifacts() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: ".NET Artifacts",
+		Items:    []types.FileItem{},
+	}
+
+	nugetDir := filepath.Join(s.HomeDir, ".local", "share", "NuGet")
+	caches := []struct {
+		path string
+		name string
+	}{
+		{filepath.Join(s.HomeDir, ".nuget", "packages"), "NuGet: packages"},
+		{filepath.Join(nugetDir, "http-cache"), "NuGet: HTTP cache"},
+		{filepath.Join(nugetDir, "v3-cache"), "NuGet: HTTP cache (v3)"},
+		{filepath.Join(nugetDir, "plugins-cache"), "NuGet: plugins cache"},
+	}
+	for _, cache := range caches {
+		if _, err := os.Stat(cache.path); err == nil {
+			if size := s.dirSize(cache.path); size > 0 {
+				result.Items = append(result.Items, types.FileItem{
+					Path:  cache.path,
+					Size:  size,
+					Name:  cache.name,
+					IsDir: true,
+				})
+				result.Total += size
+			}
+		}
+	}
+
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+		if d.IsDir() {
+			if utils.ShouldSkipDir(path) || d.Name() == "node_modules" || path == filepath.Join(s.HomeDir, ".nuget") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isDotnetProject(d.Name()) {
+			return nil
+		}
+
+		projectDir := filepath.Dir(path)
+		relPath, _ := filepath.Rel(s.HomeDir, projectDir)
+		for _, name := range []string{"bin", "obj"} {
+			dir := filepath.Join(projectDir, name)
+			if containsItem(result.Items, dir) {
+				continue // Several project files share the folder
+			}
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+			if size := s.dirSize(dir); size > 0 {
+				result.Items = append(result.Items, types.FileItem{
+					Path:  dir,
+					Size:  size,
+					Name:  fmt.Sprintf("🟣 %s (%s)", relPath, name),
+					IsDir: true,
+				})
+				result.Total += size
+			}
+		}
+		return nil
+	})
+
+	return result
+}
+
+func isDotnetProject(name string) bool {
+	for _, ext := range dotnetProjectExts {
+		if strings.EqualFold(filepath.Ext(name), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsItem(items []types.FileItem, path string) bool {
+	for _, item := range items {
+		if item.Path == path {
+			return true
+		}
+	}
+	return false
+}
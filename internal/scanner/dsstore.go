@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "ds-store", Category: ".DS_Store Files", Risk: types.RiskLow, Sets: []string{SetFull}, Order: 35, Scan: (*Scanner).ScanDSStore})
+}
+
+// volumes returns the volumes mounted in /Volumes, leaving out the startup
+// disk, which is linked there, and Time Machine backups
+func (s *Scanner) volumes() []string {
+	dir := s.systemPath("Volumes")
+	entries, err := s.readDir(dir)
+	if err != nil {
+		return nil
+	}
+	var volumes []string
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name()[0] == '.' {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(path, "Backups.backupdb")); err == nil {
+			continue
+		}
+		volumes = append(volumes, path)
+	}
+	return volumes
+}
+
+// hygieneRoots returns the folders walked for Finder's metadata files: the
+// chosen roots, or the home folder and every mounted volume
+func (s *Scanner) hygieneRoots() []string {
+	if len(s.Roots) > 0 {
+		return s.Roots
+	}
+	return append([]string{s.HomeDir}, s.volumes()...)
+}
+
+// rootLabel names a root in item names: ~ for the home folder, the volume
+// name for volumes
+func (s *Scanner) rootLabel(root string) string {
+	if root == s.HomeDir {
+		return "~"
+	}
+	if filepath.Dir(root) == s.systemPath("Volumes") {
+		return filepath.Base(root)
+	}
+	return root
+}
+
+// ScanDSStore finds the .DS_Store files Finder leaves in every folder it
+// opens; they are worth clearing on external and network volumes shared
+// with other systems
+func (s *Scanner) ScanDSStore() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: ".DS_Store Files",
+		Items:    []types.FileItem{},
+	}
+	s.addRootFiles(result, s.hygieneRoots(), "🗂️", func(path string, d fs.DirEntry) bool {
+		return d.Name() == ".DS_Store"
+	})
+	return result
+}
+
+// addRootFiles adds the regular files under roots that match, one item per
+// file, so a root is never itself an item. Trash folders are skipped.
+func (s *Scanner) addRootFiles(result *types.ScanResult, roots []string, icon string, match func(path string, d fs.DirEntry) bool) {
+	for _, root := range roots {
+		s.walkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if !os.IsNotExist(err) {
+					result.AddError(path, err)
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if name := d.Name(); path != root && (name == ".Trash" || name == ".Trashes") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || !match(path, d) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			rel, _ := filepath.Rel(root, path)
+			result.Items = append(result.Items, types.FileItem{
+				Path: path,
+				Size: info.Size(),
+				Name: icon + " " + filepath.Join(s.rootLabel(root), rel),
+				Age:  int(time.Since(info.ModTime()).Hours() / 24),
+			})
+			result.Total += info.Size()
+			return nil
+		})
+	}
+}
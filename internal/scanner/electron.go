@@ -0,0 +1,92 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "electron", Category: "Electron App Caches", Risk: types.RiskLow, Sets: []string{SetFull}, Order: 15, Scan: (*Scanner).ScanElectronCaches})
+}
+
+// electronCaches are the cache folders Chromium keeps in an Electron app's
+// data folder
+var electronCaches = []string{
+	"Cache",
+	"Code Cache",
+	"GPUCache",
+	"DawnCache",
+	"DawnGraphiteCache",
+	"DawnWebGPUCache",
+	filepath.Join("Service Worker", "CacheStorage"),
+	filepath.Join("Service Worker", "ScriptCache"),
+}
+
+// isElectronApp reports whether an Application Support folder belongs to
+// an Electron app, which keeps at least two of Chromium's caches there
+func isElectronApp(dir string) bool {
+	found := 0
+	for _, name := range []string{"Cache", "Code Cache", "GPUCache"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+			found++
+		}
+	}
+	return found >= 2
+}
+
+// ScanElectronCaches scans the Chromium caches of Electron apps such as
+// Notion, Figma and Obsidian in ~/Library/Application Support, one item per
+// app and cache
+func (s *Scanner) ScanElectronCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Electron App Caches",
+		Items:    []types.FileItem{},
+	}
+
+	supportDir := filepath.Join(s.HomeDir, "Library", "Application Support")
+	entries, err := s.readDir(supportDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(supportDir, err)
+		}
+		return result
+	}
+	// VS Code's cache is listed under IDE Caches
+	ideCache := filepath.Join(supportDir, "Code", "Cache")
+
+	// Chat apps are listed under Chat App Caches, and browsers' Service
+	// Worker caches under Browser Site Data
+	skip := make(map[string]bool)
+	for _, app := range chatApps {
+		skip[app.dir] = true
+	}
+	for _, browser := range chromiumBrowsers {
+		skip[browser.dir[0]] = true
+	}
+
+	for _, entry := range entries {
+		appDir := filepath.Join(supportDir, entry.Name())
+		if !entry.IsDir() || skip[entry.Name()] || !isElectronApp(appDir) {
+			continue
+		}
+		for _, name := range electronCaches {
+			path := filepath.Join(appDir, name)
+			if path == ideCache {
+				continue
+			}
+			if size := s.dirSize(path); size > 0 {
+				result.Items = append(result.Items, types.FileItem{
+					Path:  path,
+					Size:  size,
+					Name:  "⚛️ " + entry.Name() + ": " + name,
+					IsDir: true,
+				})
+				result.Total += size
+			}
+		}
+	}
+
+	return result
+}
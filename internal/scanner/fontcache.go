@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"path/filepath"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "font-icon-caches", Category: "Font & Icon Caches", Risk: types.RiskLow, Sets: []string{SetFull, SetQuick}, Order: 14, Scan: (*Scanner).ScanFontIconCaches,
+		Strategy: (*Scanner).fontIconStrategy})
+}
+
+// fontCaches returns the font caches in the per-user cache folder, which
+// are reset through atsutil since fontd keeps them open
+func fontCaches() []string {
+	cacheDir := darwinUserCacheDir()
+	if cacheDir == "" {
+		return nil
+	}
+	return []string{
+		filepath.Join(cacheDir, "com.apple.FontRegistry"),
+		filepath.Join(cacheDir, "com.apple.ATS"),
+	}
+}
+
+// ScanFontIconCaches scans the font caches and the Icon Services caches,
+// which hold stale or broken icons after apps are updated
+func (s *Scanner) ScanFontIconCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Font & Icon Caches",
+		Items:    []types.FileItem{},
+	}
+	add := func(path, name string) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: name, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	for _, path := range fontCaches() {
+		add(path, "🔤 Font cache ("+filepath.Base(path)+")")
+	}
+	if cacheDir := darwinUserCacheDir(); cacheDir != "" {
+		add(filepath.Join(cacheDir, "com.apple.iconservices"), "🧩 Icon Services cache")
+	}
+	// The shared store is owned by root, so deleting it needs admin rights
+	add(s.systemPath("Library", "Caches", "com.apple.iconservices.store"), "🧩 Icon Services store (shared)")
+
+	return result
+}
+
+// fontIconStrategy resets the font caches with `atsutil databases
+// -removeUser`; the Icon Services caches have no reset command and are
+// deleted, and rebuilt once the Dock and Finder restart.
+func (s *Scanner) fontIconStrategy() strategy.Strategy {
+	reset := strategy.RunCommand("atsutil", "databases", "-removeUser")
+	paths := make(map[string]strategy.Strategy)
+	for _, path := range fontCaches() {
+		paths[path] = reset
+	}
+	return strategy.Switch{Paths: paths, Fallback: s.deleter()}
+}
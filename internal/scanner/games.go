@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "games", Category: "Games", Risk: types.RiskLow, Sets: []string{SetFull}, Order: 76, Scan: (*Scanner).ScanGameCaches})
+}
+
+// steamGameName reads a game's name from its app manifest in steamapps,
+// falling back to its app ID
+func steamGameName(steamapps, appID string) string {
+	f, err := os.Open(filepath.Join(steamapps, "appmanifest_"+appID+".acf"))
+	if err != nil {
+		return "app " + appID
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		// Lines look like: "name"		"Portal 2"
+		fields := strings.Split(strings.TrimSpace(sc.Text()), `"`)
+		if len(fields) >= 4 && fields[1] == "name" {
+			return fields[3]
+		}
+	}
+	return "app " + appID
+}
+
+// ScanGameCaches scans Steam's shader caches, per game, its unfinished and
+// temporary downloads and web caches, and the Epic Games Launcher's web
+// caches and logs. Installed games are left alone.
+func (s *Scanner) ScanGameCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Games",
+		Items:    []types.FileItem{},
+	}
+	add := func(path, name string) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: "🎮 " + name, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	steamDir := filepath.Join(s.HomeDir, "Library", "Application Support", "Steam")
+	steamapps := filepath.Join(steamDir, "steamapps")
+	shaderDir := filepath.Join(steamapps, "shadercache")
+	shaders, err := s.readDir(shaderDir)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(shaderDir, err)
+	}
+	for _, entry := range shaders {
+		if entry.IsDir() {
+			add(filepath.Join(shaderDir, entry.Name()), "Steam shader cache: "+steamGameName(steamapps, entry.Name()))
+		}
+	}
+	add(filepath.Join(steamapps, "downloading"), "Steam: unfinished downloads")
+	add(filepath.Join(steamapps, "temp"), "Steam: temporary files")
+	add(filepath.Join(steamDir, "appcache", "httpcache"), "Steam: web cache")
+	add(filepath.Join(steamDir, "logs"), "Steam: logs")
+
+	epicSaved := filepath.Join(s.HomeDir, "Library", "Application Support", "Epic", "EpicGamesLauncher", "Saved")
+	caches, _ := filepath.Glob(filepath.Join(epicSaved, "webcache*"))
+	for _, path := range caches {
+		add(path, "Epic Games Launcher: web cache")
+	}
+	add(filepath.Join(epicSaved, "Logs"), "Epic Games Launcher: logs")
+
+	return result
+}
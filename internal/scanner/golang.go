@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "go", Category: "Go Artifacts", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 160, Scan: (*Scanner).ScanGoArtifacts,
+		Strategy: (*Scanner).goStrategy})
+}
+
+// goModCache returns the module cache, from GOMODCACHE if set, otherwise
+// pkg/mod in the first GOPATH entry
+func (s *Scanner) goModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	goPath := filepath.SplitList(os.Getenv("GOPATH"))
+	if len(goPath) == 0 || goPath[0] == "" {
+		return filepath.Join(s.HomeDir, "go", "pkg", "mod")
+	}
+	return filepath.Join(goPath[0], "pkg", "mod")
+}
+
+// goBuildCache returns the build cache the go command uses, from GOCACHE if
+// set, otherwise its macOS default
+func (s *Scanner) goBuildCache() string {
+	if dir := os.Getenv("GOCACHE"); dir != "" {
+		return dir
+	}
+	return filepath.Join(s.HomeDir, "Library", "Caches", "go-build")
+}
+
+// ScanGoArtifacts scans the Go module and build caches, and the extra Go
+// SDKs golang.org/dl wrappers such as go1.21.5 download to ~/sdk
+func (s *Scanner) ScanGoArtifacts() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Go Artifacts",
+		Items:    []types.FileItem{},
+	}
+	add := func(path, name string) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: name, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	add(s.goModCache(), "Go: module cache")
+	add(s.goBuildCache(), "Go: build cache")
+	// Where the build cache lived before, or with XDG_CACHE_HOME set
+	if other := filepath.Join(s.HomeDir, ".cache", "go-build"); other != s.goBuildCache() {
+		add(other, "Go: build cache (~/.cache)")
+	}
+
+	sdkDir := filepath.Join(s.HomeDir, "sdk")
+	entries, err := s.readDir(sdkDir)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(sdkDir, err)
+	}
+	var sdks []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), "go1") {
+			sdks = append(sdks, entry.Name())
+		}
+	}
+	sort.Slice(sdks, func(i, j int) bool {
+		return versionLess(strings.TrimPrefix(sdks[i], "go"), strings.TrimPrefix(sdks[j], "go"))
+	})
+	for _, sdk := range sdks {
+		add(filepath.Join(sdkDir, sdk), "🐹 Go SDK "+strings.TrimPrefix(sdk, "go"))
+	}
+
+	return result
+}
+
+// goStrategy cleans the module and build caches with `go clean`: module
+// files are read-only, so deleting the module cache outright fails. Other
+// items are deleted.
+func (s *Scanner) goStrategy() strategy.Strategy {
+	return strategy.Switch{
+		Paths: map[string]strategy.Strategy{
+			s.goModCache():   strategy.RunCommand("go", "clean", "-modcache"),
+			s.goBuildCache(): strategy.RunCommand("go", "clean", "-cache"),
+		},
+		Fallback: s.deleter(),
+	}
+}
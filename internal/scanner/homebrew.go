@@ -0,0 +1,186 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "homebrew", Category: "Homebrew Cache", Risk: types.RiskLow, Sets: []string{SetFull, SetDev}, Order: 90, Scan: (*Scanner).ScanBrewCache,
+		Strategy: func(*Scanner) strategy.Strategy { return brewCleanup() }})
+}
+
+// brewTimeout bounds a single brew call
+const brewTimeout = 2 * time.Minute
+
+// brewCleanupArgs also removes the downloads of installed versions (-s) and
+// every cached download regardless of age
+var brewCleanupArgs = []string{"cleanup", "-s", "--prune=all"}
+
+// brewCleanup returns the strategy cleaning the Homebrew cache, which
+// reports what brew says it freed
+func brewCleanup() *strategy.Command {
+	cmd := strategy.RunCommand(append([]string{"brew"}, brewCleanupArgs...)...)
+	cmd.Parse = brewFreed
+	return cmd
+}
+
+// runBrew runs brew and returns its combined output
+func (s *Scanner) runBrew(args ...string) ([]byte, error) {
+	if s.brew != nil {
+		return s.brew(args...)
+	}
+	if _, err := exec.LookPath("brew"); err != nil {
+		return nil, fmt.Errorf("brew is not installed: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), brewTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "brew", args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return nil, fmt.Errorf("brew %s: %w: %s", args[0], err, msg)
+	}
+	return out, nil
+}
+
+// ScanBrewCache sizes what `brew cleanup -n` says the cleanup would remove:
+// cached downloads, old versions of installed packages and old logs. The
+// cleanup removes all of it at once, so it is a single item. Without brew
+// the entries of the Homebrew cache are listed and deleted instead.
+func (s *Scanner) ScanBrewCache() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Homebrew Cache",
+		Items:    []types.FileItem{},
+	}
+
+	brewCache := filepath.Join(s.HomeDir, "Library", "Caches", "Homebrew")
+	if out, err := s.runBrew(append(brewCleanupArgs, "-n")...); err == nil {
+		items := parseBrewDryRun(string(out), brewCache)
+		for _, item := range items {
+			result.Total += item.Size
+		}
+		if len(items) > 0 {
+			result.Items = append(result.Items, types.FileItem{
+				Path:  brewCache,
+				Size:  result.Total,
+				Name:  fmt.Sprintf("Brew: everything brew cleanup would remove (%d entries)", len(items)),
+				IsDir: true,
+			})
+		}
+		return result
+	}
+
+	// The strategy needs brew, so these are deleted
+	result.Remover = s.deleter().Remove
+	result.Method = s.deleter().Name()
+
+	if _, err := os.Stat(brewCache); err != nil {
+		return result
+	}
+
+	entries, err := s.readDir(brewCache)
+	if err != nil {
+		result.AddError(brewCache, err)
+		return result
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(brewCache, entry.Name())
+		size := s.dirSize(path)
+		result.Items = append(result.Items, types.FileItem{
+			Path: path,
+			Size: size,
+			Name: "Brew: " + entry.Name(),
+		})
+		result.Total += size
+	}
+
+	return result
+}
+
+// brewWouldRemove matches a line of `brew cleanup -n`, such as
+// "Would remove: /opt/homebrew/Cellar/wget/1.21.3 (91 files, 4.2MB)"
+var brewWouldRemove = regexp.MustCompile(`^Would remove: (.+) \((?:[\d,]+ files?, )?([\d.]+\s*[KMGT]?B)\)$`)
+
+// parseBrewDryRun turns the output of `brew cleanup -n` into items, sized
+// as brew reports them
+func parseBrewDryRun(out, brewCache string) []types.FileItem {
+	var items []types.FileItem
+	for _, line := range strings.Split(out, "\n") {
+		m := brewWouldRemove.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		path := m[1]
+		size, ok := parseBrewSize(m[2])
+		if !ok || size == 0 {
+			continue
+		}
+
+		base := filepath.Base(path)
+		name := "Brew: " + base
+		switch root := filepath.Base(filepath.Dir(filepath.Dir(path))); {
+		case root == "Cellar" || root == "Caskroom":
+			name = fmt.Sprintf("Brew: %s %s (old version)", filepath.Base(filepath.Dir(path)), base)
+		case filepath.Dir(path) == filepath.Join(brewCache, "downloads"):
+			// Downloads are named after the hash of their URL
+			if hash, rest, ok := strings.Cut(base, "--"); ok && len(hash) == 64 {
+				name = "Brew: " + rest
+			}
+		}
+
+		item := types.FileItem{Path: path, Size: size, Name: name}
+		if info, err := os.Lstat(path); err == nil {
+			item.IsDir = info.IsDir()
+			item.Age = int(time.Since(info.ModTime()).Hours() / 24)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// parseBrewSize parses a size as brew prints it, such as "4.2MB", in
+// powers of 1024
+func parseBrewSize(text string) (int64, bool) {
+	text = strings.TrimSpace(text)
+	units := []struct {
+		suffix string
+		factor float64
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(text, unit.suffix); ok {
+			n, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil {
+				return 0, false
+			}
+			return int64(n * unit.factor), true
+		}
+	}
+	return 0, false
+}
+
+// brewFreedLine matches the summary `brew cleanup` ends with
+var brewFreedLine = regexp.MustCompile(`This operation has freed approximately ([\d.]+\s*[KMGT]?B) of disk space`)
+
+// brewFreed returns what brew says a cleanup freed, e.g. "brew freed
+// approximately 1.2GB", empty when it freed nothing
+func brewFreed(out string) string {
+	if m := brewFreedLine.FindStringSubmatch(out); m != nil {
+		return "brew freed approximately " + m[1]
+	}
+	return ""
+}
@@ -0,0 +1,323 @@
+package scanner
+
+import "github.com/rahulvramesh/cleanWithCli/internal/types"
+
+// categoryInfo describes the built-in categories, keyed by category
+var categoryInfo = map[string]types.CategoryInfo{
+	"Cache Files": {
+		Description:  "Data apps keep to start and load faster: downloaded images, compiled shaders, web caches.",
+		Consequences: "Apps start slower the first time and may ask you to sign in to some services again.",
+		Regeneration: "Rebuilt automatically as you use each app.",
+	},
+	"Chat App Caches": {
+		Description:  "Caches of Slack, Microsoft Teams and Discord, and Zoom's data folder.",
+		Consequences: "Apps start slower and download messages, images and files again; clearing Zoom's data signs you out and removes its local chat history. Quit the apps before cleaning.",
+		Regeneration: "Refilled by the apps as they are used; sign in to Zoom again.",
+	},
+	"Electron App Caches": {
+		Description:  "Chromium caches of Electron apps such as Notion, Figma and Obsidian: HTTP, compiled code, GPU shader and service worker caches.",
+		Consequences: "Apps start a little slower and download images and scripts again; quit them before cleaning.",
+		Regeneration: "Refilled by the apps as they are used.",
+	},
+	"Media App Caches": {
+		Description:  "Streaming caches of Spotify, Plex, TIDAL, Deezer and Amazon Music.",
+		Consequences: "Music streams again instead of playing from the cache, and Spotify's offline playlists must be downloaded again.",
+		Regeneration: "Refilled by the apps as media is played; download offline playlists again.",
+	},
+	"Browser Site Data": {
+		Description:  "Sites storing over 50 MB in a browser profile through IndexedDB or Service Worker caches, one item per site, in Chromium-based browsers and Firefox.",
+		Consequences: "Web apps lose their offline data and saved state and may sign you out; data not yet synced to the site is lost. Quit the browser before cleaning.",
+		Regeneration: "Sites store their data again as they are used.",
+	},
+	"Saved Application State": {
+		Description:  "The window state apps save to reopen their windows where they were, one item per app; misbehaving apps can grow it to GBs.",
+		Consequences: "Apps open with fresh windows instead of restoring the previous ones.",
+		Regeneration: "Saved again by apps as they are used.",
+	},
+	"QuickLook Thumbnails": {
+		Description:  "The thumbnail cache QuickLook and Finder keep for file previews, reset with qlmanage -r cache.",
+		Consequences: "Finder and QuickLook show previews a little slower until thumbnails are generated again.",
+		Regeneration: "Thumbnails are generated again as files are previewed.",
+	},
+	"Font & Icon Caches": {
+		Description:  "The font caches, reset with atsutil databases -removeUser, and the Icon Services caches, which keep stale or broken icons after apps are updated.",
+		Consequences: "Fonts and icons load a little slower until the caches are rebuilt; log out and in, or restart the Dock and Finder, to see fresh icons. The shared icon store needs admin rights to delete.",
+		Regeneration: "Rebuilt by macOS as fonts and icons are used.",
+	},
+	"Log Files": {
+		Description:  "Diagnostic messages written by macOS and apps.",
+		Consequences: "Past crash and error details are no longer available for troubleshooting.",
+		Regeneration: "New logs are written as soon as apps run again.",
+	},
+	"Old Log Files": {
+		Description:  "Log files from macOS and apps that have not been written to for a week.",
+		Consequences: "Details of old crashes and errors are no longer available for troubleshooting.",
+		Regeneration: "None needed; current logs are kept.",
+	},
+	"Trash": {
+		Description:  "Files you already moved to the Trash.",
+		Consequences: "They can no longer be restored from the Trash.",
+		Regeneration: "None; emptying the Trash is permanent.",
+	},
+	".DS_Store Files": {
+		Description:  "The .DS_Store files Finder writes in every folder it opens to remember icon positions and view options, found in the home folder and on mounted volumes, where they litter drives and shares used with other systems.",
+		Consequences: "Folders forget their custom view settings, icon positions and background in Finder.",
+		Regeneration: "Written again by Finder as folders are opened.",
+	},
+	"AppleDouble Files": {
+		Description:  "The ._ files macOS writes next to files on FAT, exFAT, NTFS and network volumes to keep their extended attributes and resource forks, which show up as clutter on other systems.",
+		Consequences: "Files on those volumes lose their Finder tags, comments, quarantine flags and any resource forks; the files themselves are kept.",
+		Regeneration: "Written again as files on the volume are copied or changed from a Mac.",
+	},
+	"Largest Files": {
+		Description:  "The 100 largest files in the chosen folder, whatever they are: forgotten disk images, videos, archives and backups often outweigh every cache combined.",
+		Consequences: "They are moved to the Trash, so they can still be restored until it is emptied. Check each file; nothing is known about what uses it.",
+		Regeneration: "Never; these are your files.",
+	},
+	"Installers": {
+		Description:  "Disk images (.dmg, .iso) and installer packages (.pkg) in Downloads and on the Desktop not opened for two weeks (set with -installer-age); images still mounted are skipped.",
+		Consequences: "They are moved to the Trash, so they can still be restored until it is emptied. Installing the app again means downloading it again.",
+		Regeneration: "Only by downloading them again, if they are still available.",
+	},
+	"Old Downloads": {
+		Description:  "Files in ~/Downloads not modified for more than 30 days: installers, archives, documents.",
+		Consequences: "They are moved to the Trash, so they can still be restored until it is emptied.",
+		Regeneration: "Only by downloading them again, if they are still available.",
+	},
+	"Extracted Archives": {
+		Description:  "Zip files and tarballs in ~/Downloads and on the Desktop next to a folder of the same name that already holds at least 90% of their files, at the same sizes, so the same data is stored twice.",
+		Consequences: "The archive is moved to the Trash and the extracted folder is kept. To keep the archive instead, delete the folder in Finder and leave this item unselected.",
+		Regeneration: "By compressing the folder again, or downloading the archive again.",
+	},
+	"Screenshots": {
+		Description:  "Screenshots and CleanShot captures left on the Desktop for more than 30 days (set with -screenshot-age), listed one per file with their count and total size.",
+		Consequences: "They are moved to the Trash, or into the folder given with -screenshot-archive, where they are kept.",
+		Regeneration: "Not regenerated; restore them from the Trash or the archive folder.",
+	},
+	"App Leftovers": {
+		Description:  "Application support files, caches, containers and preferences in ~/Library named after apps whose vendor no longer has any app installed in /Applications or ~/Applications.",
+		Consequences: "Settings and data of the removed apps are lost; reinstalling them starts from scratch. Apps installed elsewhere, such as on another volume, are not recognized, so check each item.",
+		Regeneration: "Not regenerated unless the app is installed again.",
+	},
+	"Xcode Installations": {
+		Description:  "Extra copies of Xcode in /Applications, such as betas or older versions kept for a project.",
+		Consequences: "Projects that pin that Xcode version in .xcode-version cannot build until it is reinstalled.",
+		Regeneration: "A multi-gigabyte download from the Apple developer site, plus installing its components.",
+	},
+	"macOS Installers": {
+		Description:  "\"Install macOS\" apps and update packages left over after upgrading.",
+		Consequences: "You cannot create a bootable installer or reinstall that version without downloading it.",
+		Regeneration: "Download again from the App Store or Software Update (10+ GB).",
+	},
+	"Sound Libraries": {
+		Description:  "Instruments and Apple Loops used by GarageBand and Logic Pro.",
+		Consequences: "Projects using these sounds play back silent or with substitutes.",
+		Regeneration: "Download again from within GarageBand or Logic Pro.",
+	},
+	"Virtual Machines": {
+		Description:  "Virtual machines of UTM, Parallels Desktop and VMware Fusion, each a whole operating system with its apps, files and snapshots.",
+		Consequences: "Everything inside a deleted machine is lost, including files that exist nowhere else.",
+		Regeneration: "Cannot be regenerated; a new machine must be installed and set up from scratch.",
+	},
+	"Mail Attachments": {
+		Description:  "Attachments Mail downloaded, per mailbox, and the copies it saved in Mail Downloads when they were opened. Report only.",
+		Consequences: "Deleting Mail's files by hand leaves messages with missing attachments or makes Mail rebuild its mailboxes and download everything again.",
+		Regeneration: "Mail downloads attachments again from the server as messages are opened.",
+	},
+	"Apple Media Downloads": {
+		Description:  "Episodes downloaded by Podcasts, movies and shows downloaded by TV, songs downloaded from Apple Music, and the apps' caches. Report only.",
+		Consequences: "Files deleted by hand stay listed in the apps as missing; removed downloads are no longer playable offline.",
+		Regeneration: "Download them again in the apps.",
+	},
+	"Photos Library Caches": {
+		Description:  "Previews, thumbnails, rendered edits and other files Photos generates inside each library in ~/Pictures. Report only: never deleted by this tool.",
+		Consequences: "Changing files inside a Photos library by hand can corrupt it.",
+		Regeneration: "Photos generates them as needed.",
+	},
+	"Adobe Caches": {
+		Description:  "Media caches and peak files of Premiere Pro and After Effects, the After Effects disk cache, the Camera Raw cache and the previews of Lightroom Classic catalogs.",
+		Consequences: "Projects take longer to open while media is indexed again, and Lightroom shows lower-quality previews until it renders them again. Quit Adobe apps before cleaning.",
+		Regeneration: "Rebuilt by the apps as projects and catalogs are opened.",
+	},
+	"Production Render Files": {
+		Description:  "Render files and optimized or proxy media inside Final Cut Pro libraries, and freeze files and bounces inside Logic Pro projects, per library or project. Report only.",
+		Consequences: "Deleting them by hand can leave libraries and projects with missing media; the apps remove them safely.",
+		Regeneration: "Final Cut Pro renders and transcodes again as needed; Logic Pro freezes tracks again.",
+	},
+	"Speech & ML Assets": {
+		Description:  "Offline voices, Siri and dictation models and on-device machine learning data managed by macOS.",
+		Consequences: "Dictation, Siri and spoken content may stop working offline until macOS restores them.",
+		Regeneration: "macOS downloads them again in the background when needed.",
+	},
+	"Time Machine Snapshots": {
+		Description:  "Local snapshots Time Machine keeps on this disk between backups. They hold on to files you deleted since, so deleting files does not free space until they expire. Sizes are estimates: the purgeable space on the volume split evenly between them.",
+		Consequences: "You can no longer restore files from those points in time unless they were also backed up to the Time Machine disk.",
+		Regeneration: "Time Machine takes new snapshots with each hourly backup; macOS also thins them on its own when space runs low.",
+	},
+	"Games": {
+		Description:  "Steam shader caches per game, unfinished and temporary Steam downloads, Steam's web cache and logs, and the Epic Games Launcher's web caches and logs. Installed games are not touched.",
+		Consequences: "Games stutter while shaders compile again, and unfinished downloads start over. Quit Steam and the Epic Games Launcher before cleaning.",
+		Regeneration: "Shader caches rebuild as games are played; downloads resume from scratch.",
+	},
+	"Xcode Files": {
+		Description:  "DerivedData (build products and indexes), Archives (builds submitted to the App Store) and CoreSimulator Devices (every simulator you created, with the apps and data installed on it).",
+		Consequences: "The next build is a full rebuild, archived builds and their debug symbols are gone, and simulators lose their installed apps and data.",
+		Regeneration: "DerivedData rebuilds on the next build; simulators are recreated from Xcode; archives cannot be recovered.",
+	},
+	"Xcode Device Support": {
+		Description:  "Debug symbols Xcode copies from an iPhone, Apple Watch, Apple TV or Vision Pro the first time it connects, one folder per OS version.",
+		Consequences: "The next time a device on that OS version connects, Xcode has to copy its symbols again before you can debug on it.",
+		Regeneration: "Copied again automatically when the device connects; this takes a few minutes.",
+	},
+	"Simulator Runtimes": {
+		Description:  "Downloaded iOS, watchOS, tvOS and visionOS simulator runtimes.",
+		Consequences: "Simulators using a deleted runtime become unavailable until it is downloaded again.",
+		Regeneration: "Download the runtime again from Xcode's Platforms settings (several GB each).",
+	},
+	"Simulator Caches": {
+		Description:  "Caches CoreSimulator shares between all simulators, mostly the dyld shared caches it builds for each runtime and macOS version.",
+		Consequences: "The first simulator launch on each runtime takes a few minutes longer.",
+		Regeneration: "Rebuilt automatically the next time a simulator boots.",
+	},
+	"Android SDK": {
+		Description:  "Android emulator system images, platforms, build tools and NDKs older than the newest installed, and emulator virtual devices (AVDs) with their data.",
+		Consequences: "Emulators using a deleted system image stop booting, deleted AVDs lose their installed apps and data, and projects pinning a deleted platform, build tools or NDK version don't build.",
+		Regeneration: "Install again from Android Studio's SDK Manager or with sdkmanager; recreate AVDs in the Device Manager.",
+	},
+	"Homebrew Cache": {
+		Description:  "Downloaded bottles and source archives Homebrew keeps after installing packages, and old versions of installed packages, as brew cleanup -n lists them; the cleanup removes all of them at once. Without brew, the entries of the Homebrew cache are deleted directly.",
+		Consequences: "Reinstalling or downgrading a package downloads it again; old versions can no longer be linked back with brew link.",
+		Regeneration: "Downloaded on demand by brew.",
+	},
+	"Node Modules": {
+		Description:  "Dependencies installed into each JavaScript project.",
+		Consequences: "Projects do not run or build until dependencies are installed again.",
+		Regeneration: "Run npm, yarn or pnpm install in the project; can take minutes and needs network access. Projects marked (Bun) have a bun.lockb and bun install restores them in seconds from its cache.",
+	},
+	"Python Artifacts": {
+		Description:  "Virtual environments, bytecode caches and pip or conda package caches.",
+		Consequences: "Projects lose their installed packages; bytecode is recompiled on import.",
+		Regeneration: "Recreate the virtualenv and reinstall requirements.",
+	},
+	"Conda Environments": {
+		Description:  "Conda and mamba environments other than the base install and the active one, with when each was last used.",
+		Consequences: "Code that runs in a removed environment fails until it is created again, and its packages are downloaded again unless they are still in the package cache.",
+		Regeneration: "conda env create from the project's environment.yml, or conda create with the packages it needs.",
+	},
+	"ML Model Caches": {
+		Description:  "Model weights and datasets downloaded by Hugging Face libraries, PyTorch, Keras and Whisper, one item per model.",
+		Consequences: "The next program loading a removed model downloads it again, which can take a long time for models of several GB.",
+		Regeneration: "Downloaded again from the Hugging Face Hub or the model's source the next time it is loaded.",
+	},
+	"Ollama Models": {
+		Description:  "Models pulled with Ollama, sized by the blobs no other model shares, and blobs no model uses such as unfinished downloads.",
+		Consequences: "Removed models are no longer available to ollama run or to apps using the local Ollama server.",
+		Regeneration: "ollama pull downloads the model again.",
+	},
+	"Rust Artifacts": {
+		Description:  "Cargo target directories and the crate registry cache.",
+		Consequences: "The next cargo build compiles everything from scratch.",
+		Regeneration: "Rebuilt by cargo; large projects can take many minutes.",
+	},
+	"Rust Toolchains": {
+		Description:  "Rust toolchains rustup installed besides the default one, such as dated nightlies and pinned versions, and rustup's leftover downloads.",
+		Consequences: "Projects pinned to a removed toolchain by rust-toolchain.toml or rustup override install it again on the next cargo command.",
+		Regeneration: "rustup toolchain install, or any cargo command in a pinned project, downloads it again.",
+	},
+	"Runtime Versions": {
+		Description:  "Node, Python and Ruby versions installed with nvm, pyenv and rbenv other than the one each is set to use.",
+		Consequences: "Projects that pin a removed version in .nvmrc, .python-version or .ruby-version stop working until it is installed again, along with the packages installed into it.",
+		Regeneration: "nvm install, pyenv install or rbenv install builds or downloads the version again.",
+	},
+	"Build Artifacts": {
+		Description:  "Build output directories such as dist, build and .next in projects.",
+		Consequences: "Projects need to be built again before running or deploying.",
+		Regeneration: "Run the project's build.",
+	},
+	"NPM/Yarn/PNPM Caches": {
+		Description:  "Package archives shared by all JavaScript projects, from npm, Yarn, pnpm and Bun, and Bun's globally installed packages.",
+		Consequences: "The next install in any project downloads packages again; global Bun packages must be installed again with bun add -g. The pnpm store is pruned with pnpm store prune instead, which keeps the packages projects still link to. The npm cache is cleared by npm itself, which reports what it reclaimed.",
+		Regeneration: "Refilled by package installs.",
+	},
+	"Deno Cache": {
+		Description:  "Remote modules and npm packages Deno downloaded, and the code it transpiled and type-checked, shared by all Deno projects.",
+		Consequences: "The next run of each script downloads and compiles its dependencies again.",
+		Regeneration: "Refilled by deno run, deno cache or deno install.",
+	},
+	"Go Artifacts": {
+		Description:  "The Go build cache and downloaded module cache, cleaned with go clean since module files are read-only, and extra Go versions downloaded to ~/sdk.",
+		Consequences: "Builds recompile dependencies and download modules again; a removed SDK's goX.Y.Z command stops working until downloaded again.",
+		Regeneration: "Refilled by go build and go mod download; run goX.Y.Z download to get an SDK back.",
+	},
+	"Java/JVM Artifacts": {
+		Description:  "The Maven local repository, Gradle caches and build cache, Gradle wrapper distributions (about 150 MB per version) and Gradle daemon logs.",
+		Consequences: "The next build downloads every dependency again, and projects using a deleted wrapper version download it first.",
+		Regeneration: "Refilled by Maven or Gradle builds.",
+	},
+	"Ruby Artifacts": {
+		Description:  "Installed gems and the Bundler download cache.",
+		Consequences: "gem cleanup only removes gem versions nothing uses; the Bundler cache is downloaded again.",
+		Regeneration: "Run bundle install.",
+	},
+	"Unity Artifacts": {
+		Description:  "The Library, Temp and Logs folders Unity generates in each project: imported assets, shader caches and editor logs.",
+		Consequences: "The next time the project opens, Unity imports every asset again, which can take a long time in large projects.",
+		Regeneration: "Rebuilt automatically by the Unity editor when it opens the project.",
+	},
+	".NET Artifacts": {
+		Description:  "NuGet's global packages folder and HTTP caches, and the bin and obj folders of .NET projects.",
+		Consequences: "The next restore downloads packages again and projects build from scratch.",
+		Regeneration: "Run dotnet restore and dotnet build.",
+	},
+	"Docker Artifacts": {
+		Description:  "Dangling and unused images, stopped containers, and volumes and build cache entries nothing uses, listed by the Docker daemon. When Docker is not running, Docker Desktop's whole virtual disk is shown instead, report-only; start Docker to list what can be removed.",
+		Consequences: "Removed images, containers and build cache are gone from the machine, along with anything a container wrote outside its volumes; a removed volume deletes the data stored in it for good.",
+		Regeneration: "Images are pulled or built again and the build cache refills on the next build; volume data cannot be recovered.",
+	},
+	"Colima & Lima VMs": {
+		Description:  "Disks of stopped Colima and Lima virtual machines, data disks no machine uses, and the Linux images they downloaded. Docker under Colima keeps all its images, containers and volumes inside the VM disk.",
+		Consequences: "Everything inside a deleted machine is gone, including Docker images, containers and volumes kept there; the instance must be created again.",
+		Regeneration: "colima start or limactl start creates a fresh machine, downloading its image again if the cache was cleaned; data inside the old one cannot be recovered.",
+	},
+	"Local Kubernetes": {
+		Description:  "Stopped minikube machines, the ISOs, preloaded images and binaries minikube downloaded for each Kubernetes version, and kind, k3d and minikube node images no cluster uses.",
+		Consequences: "A deleted minikube machine loses its cluster and everything deployed to it; the next cluster of a cleaned version downloads its ISO, images or node image again.",
+		Regeneration: "minikube start, kind create cluster or k3d cluster create downloads what it needs and sets up a fresh cluster; workloads must be deployed again.",
+	},
+	"Kubernetes Tooling": {
+		Description:  "kubectl's API discovery and HTTP caches, and Helm's repository indexes, downloaded charts and other caches.",
+		Consequences: "The next kubectl command against each cluster is slower while it discovers the API again; Helm can't install from a repository until its index is downloaded again.",
+		Regeneration: "kubectl rebuilds its caches on the next command; helm repo update downloads repository indexes again and charts are fetched on install.",
+	},
+	"Terraform Artifacts": {
+		Description:  "Provider binaries in Terraform's shared plugin cache, one entry per version, and the .terraform folders of projects, holding their own providers and downloaded modules.",
+		Consequences: "Plans and applies fail in a cleaned project until it is initialized again; the lock file and state are not touched.",
+		Regeneration: "Run terraform init in the project; providers and modules are downloaded again, which can take minutes.",
+	},
+	"Vagrant Boxes": {
+		Description:  "Base images Vagrant downloaded to create machines from, one entry per box version and provider.",
+		Consequences: "vagrant up for a machine using a removed box downloads it again; vagrant box remove refuses boxes a machine still uses.",
+		Regeneration: "Downloaded again by vagrant up or vagrant box add, which can take a while for multi-GB boxes.",
+	},
+	"IDE Caches": {
+		Description:  "VS Code and JetBrains caches, indexes and installed extensions.",
+		Consequences: "Editors re-index projects, and removed extensions must be reinstalled.",
+		Regeneration: "Indexes rebuild on open; extensions are reinstalled from the marketplace.",
+	},
+	"Carthage Artifacts": {
+		Description:  "The Carthage/Build and Carthage/Checkouts folders of projects with a Cartfile, and Carthage's cache of downloaded dependencies.",
+		Consequences: "Projects don't build until carthage bootstrap checks out and builds their dependencies again, which can take a long time.",
+		Regeneration: "carthage bootstrap recreates them from Cartfile.resolved.",
+	},
+	"CocoaPods": {
+		Description:  "Downloaded pod specs and sources, and the Pods folders of projects with a Podfile.",
+		Consequences: "Projects whose Pods folder was removed don't build until pod install runs, which downloads pods again if they left the cache.",
+		Regeneration: "pod install recreates Pods from the Podfile.lock.",
+	},
+}
+
+// Info returns the description of a built-in category
+func Info(category string) (types.CategoryInfo, bool) {
+	info, ok := categoryInfo[category]
+	return info, ok
+}
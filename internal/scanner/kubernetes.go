@@ -0,0 +1,185 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// minikubeDownloads names the minikube cache folders listed file by file
+var minikubeDownloads = map[string]string{
+	"iso":               "ISO",
+	"preloaded-tarball": "preloaded images",
+}
+
+func init() {
+	Register(Registration{Name: "local-k8s", Category: "Local Kubernetes", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 193, Scan: (*Scanner).ScanLocalKubernetes})
+	Register(Registration{Name: "k8s-tooling", Category: "Kubernetes Tooling", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 194, Scan: (*Scanner).ScanKubernetesTooling})
+}
+
+// ScanLocalKubernetes lists what local Kubernetes clusters leave behind:
+// minikube's stopped machines, ISOs, preloaded images and other caches, and
+// the kind, k3d and minikube node images Docker keeps that no cluster uses
+func (s *Scanner) ScanLocalKubernetes() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Local Kubernetes",
+		Items:    []types.FileItem{},
+	}
+
+	minikube := filepath.Join(s.HomeDir, ".minikube")
+	s.addMinikubeMachines(result, filepath.Join(minikube, "machines"))
+	s.addMinikubeCache(result, filepath.Join(minikube, "cache"))
+
+	// Node images are Docker objects, removed through Docker
+	if images, err := s.scanDocker(true); err == nil && len(images.Items) > 0 {
+		result.Items = append(result.Items, images.Items...)
+		result.Total += images.Total
+		result.Method = "delete, or remove node images through Docker"
+		removeImage := images.Remover
+		result.Remover = func(path string) error {
+			if strings.HasPrefix(path, dockerScheme) {
+				return removeImage(path)
+			}
+			return s.deleter().Remove(path)
+		}
+	}
+
+	return result
+}
+
+// addMinikubeMachines adds the VMs minikube created, leaving out those
+// whose hypervisor is running, which keeps a pid file next to the disk
+func (s *Scanner) addMinikubeMachines(result *types.ScanResult, machinesDir string) {
+	entries, err := s.readDir(machinesDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(machinesDir, err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue // server.pem and other shared certificates
+		}
+		path := filepath.Join(machinesDir, entry.Name())
+		if pids, _ := filepath.Glob(filepath.Join(path, "*.pid")); len(pids) > 0 {
+			continue
+		}
+		s.addVMDisk(result, path, "☸️ minikube machine: "+entry.Name())
+	}
+}
+
+// addMinikubeCache adds minikube's downloads: one item per ISO and
+// preloaded image tarball, which are kept per Kubernetes version, and one
+// per other cache, such as saved images and kubectl binaries
+func (s *Scanner) addMinikubeCache(result *types.ScanResult, cacheDir string) {
+	entries, err := s.readDir(cacheDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(cacheDir, err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		path := filepath.Join(cacheDir, entry.Name())
+		if label, ok := minikubeDownloads[entry.Name()]; ok {
+			s.walkDir(path, func(file string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return nil
+				}
+				info, err := d.Info()
+				if err != nil || info.Size() == 0 {
+					return nil
+				}
+				result.Items = append(result.Items, types.FileItem{
+					Path: file,
+					Size: info.Size(),
+					Name: "☸️ minikube " + label + ": " + d.Name(),
+					Age:  int(time.Since(info.ModTime()).Hours() / 24),
+				})
+				result.Total += info.Size()
+				return nil
+			})
+			continue
+		}
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: "☸️ minikube cache: " + entry.Name(), IsDir: entry.IsDir()})
+			result.Total += size
+		}
+	}
+}
+
+// ScanKubernetesTooling lists the caches of kubectl, its API discovery and
+// HTTP caches, and of Helm, one item per repository index and downloaded
+// chart and one per other Helm cache
+func (s *Scanner) ScanKubernetesTooling() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Kubernetes Tooling",
+		Items:    []types.FileItem{},
+	}
+	add := func(path, name string) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: name, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	kubeCache := os.Getenv("KUBECACHEDIR")
+	if kubeCache == "" {
+		kubeCache = filepath.Join(s.HomeDir, ".kube", "cache")
+	}
+	add(filepath.Join(kubeCache, "discovery"), "☸️ kubectl: API discovery cache")
+	add(filepath.Join(kubeCache, "http"), "☸️ kubectl: HTTP cache")
+	add(filepath.Join(s.HomeDir, ".kube", "http-cache"), "☸️ kubectl: HTTP cache (old kubectl)")
+
+	helmCache := os.Getenv("HELM_CACHE_HOME")
+	if helmCache == "" {
+		helmCache = filepath.Join(s.HomeDir, "Library", "Caches", "helm")
+	}
+	entries, err := s.readDir(helmCache)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(helmCache, err)
+		}
+		return result
+	}
+	for _, entry := range entries {
+		path := filepath.Join(helmCache, entry.Name())
+		if entry.Name() != "repository" {
+			add(path, "☸️ Helm cache: "+entry.Name())
+			continue
+		}
+		files, err := s.readDir(path)
+		if err != nil {
+			result.AddError(path, err)
+			continue
+		}
+		for _, file := range files {
+			info, err := file.Info()
+			if err != nil || file.IsDir() || info.Size() == 0 {
+				continue
+			}
+			name := "☸️ Helm: " + file.Name()
+			if repo, ok := strings.CutSuffix(file.Name(), "-index.yaml"); ok {
+				name = "☸️ Helm repository index: " + repo
+			} else if repo, ok := strings.CutSuffix(file.Name(), "-charts.txt"); ok {
+				name = "☸️ Helm repository chart list: " + repo
+			} else if chart, ok := strings.CutSuffix(file.Name(), ".tgz"); ok {
+				name = "☸️ Helm chart: " + chart
+			}
+			result.Items = append(result.Items, types.FileItem{
+				Path: filepath.Join(path, file.Name()),
+				Size: info.Size(),
+				Name: name,
+				Age:  int(time.Since(info.ModTime()).Hours() / 24),
+			})
+			result.Total += info.Size()
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"container/heap"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// LargestFilesCategory names the results of LargestFiles
+const LargestFilesCategory = "Largest Files"
+
+// largestSkipDirs are where walking from / would leave the startup volume
+// or list files that aren't files
+var largestSkipDirs = []string{"/Volumes", "/System/Volumes", "/dev", "/private/var/vm"}
+
+// fileHeap keeps the largest files seen so far, smallest on top
+type fileHeap []types.FileItem
+
+func (h fileHeap) Len() int           { return len(h) }
+func (h fileHeap) Less(i, j int) bool { return h[i].Size < h[j].Size }
+func (h fileHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *fileHeap) Push(x any)        { *h = append(*h, x.(types.FileItem)) }
+func (h *fileHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// LargestFiles walks root and returns its n largest files, whatever they
+// are, largest first, sized by what they take on disk. Other volumes are
+// not entered. Files are moved to the Trash rather than deleted, since
+// nothing is known about them.
+func (s *Scanner) LargestFiles(root string, n int) *types.ScanResult {
+	result := &types.ScanResult{
+		Category: LargestFilesCategory,
+		Items:    []types.FileItem{},
+	}
+
+	h := &fileHeap{}
+	s.walkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(path, err)
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && contains(largestSkipDirs, path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		size := utils.FileAllocatedSize(info)
+		if size == 0 || (h.Len() == n && size <= (*h)[0].Size) {
+			return nil
+		}
+		heap.Push(h, types.FileItem{
+			Path: path,
+			Size: size,
+			Name: "📄 " + d.Name(),
+			Age:  int(time.Since(info.ModTime()).Hours() / 24),
+		})
+		if h.Len() > n {
+			heap.Pop(h)
+		}
+		return nil
+	})
+
+	result.Items = append(result.Items, *h...)
+	sort.Slice(result.Items, func(i, j int) bool { return result.Items[i].Size > result.Items[j].Size })
+	for _, item := range result.Items {
+		result.Total += item.Size
+	}
+
+	trash := strategy.MoveToTrash(s.HomeDir)
+	result.Remover = trash.Remove
+	result.Method = trash.Name()
+	return result
+}
@@ -0,0 +1,152 @@
+package scanner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "app-leftovers", Category: "App Leftovers", Risk: types.RiskMedium, Sets: []string{SetFull}, Order: 45, Scan: (*Scanner).ScanAppLeftovers})
+}
+
+// leftoverDirs are the folders of ~/Library where apps keep data named by
+// their bundle ID, the suffix the names carry there, and the scanner that
+// already lists every entry of the folder, if any
+var leftoverDirs = []struct{ dir, suffix, owner string }{
+	{"Application Support", "", ""},
+	{"Caches", "", "caches"},
+	{"Containers", "", ""},
+	{"HTTPStorages", "", ""},
+	{"Preferences", ".plist", ""},
+	{"Saved Application State", ".savedState", "saved-state"},
+	{"WebKit", "", ""},
+}
+
+// bundleIDPattern matches reverse-DNS names such as com.example.App
+var bundleIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9_-]+){2,}$`)
+
+// bundleIDsInBinary finds every reverse-DNS string in a binary property
+// list, which this package can't parse
+var bundleIDsInBinary = regexp.MustCompile(`[A-Za-z0-9-]+(\.[A-Za-z0-9_-]+){2,}`)
+
+// appBundleIDs returns the bundle ID of the app at path. For binary
+// Info.plists it returns every bundle ID–like string in it, so an app is
+// never taken for uninstalled because its ID couldn't be read.
+func appBundleIDs(app string) []string {
+	info := filepath.Join(app, "Contents", "Info.plist")
+	if id := readPlistString(info, "CFBundleIdentifier"); id != "" {
+		return []string{id}
+	}
+	data, err := os.ReadFile(info)
+	if err != nil || !bytes.HasPrefix(data, []byte("bplist")) {
+		return nil
+	}
+	var ids []string
+	for _, m := range bundleIDsInBinary.FindAll(data, -1) {
+		ids = append(ids, string(m))
+	}
+	return ids
+}
+
+// bundleVendor returns the first two labels of a bundle ID, e.g.
+// com.microsoft for com.microsoft.Word
+func bundleVendor(id string) string {
+	parts := strings.SplitN(strings.ToLower(id), ".", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// installedVendors returns the vendors of the apps in /Applications, its
+// subfolders such as Utilities, /System/Applications and ~/Applications
+func (s *Scanner) installedVendors() map[string]bool {
+	vendors := make(map[string]bool)
+	var scan func(dir string, depth int)
+	scan = func(dir string, depth int) {
+		entries, err := s.readDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			switch {
+			case strings.HasSuffix(entry.Name(), ".app"):
+				for _, id := range appBundleIDs(path) {
+					if vendor := bundleVendor(id); vendor != "" {
+						vendors[vendor] = true
+					}
+				}
+			case entry.IsDir() && depth > 0:
+				scan(path, depth-1)
+			}
+		}
+	}
+	scan(s.systemPath("Applications"), 1)
+	scan(s.systemPath("System", "Applications"), 1)
+	scan(filepath.Join(s.HomeDir, "Applications"), 1)
+	return vendors
+}
+
+// ScanAppLeftovers lists the data in ~/Library of apps that are no longer
+// installed: folders and preferences named by a bundle ID whose vendor has
+// no app left. Matching whole vendors, and never Apple, keeps the data of
+// helpers and tools that came with an installed app.
+func (s *Scanner) ScanAppLeftovers() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "App Leftovers",
+		Items:    []types.FileItem{},
+	}
+
+	vendors := s.installedVendors()
+	// Without any installed app found, everything would look left over
+	if len(vendors) == 0 {
+		return result
+	}
+
+	for _, loc := range leftoverDirs {
+		// Listed twice, the folder would count twice in the totals
+		if loc.owner != "" && s.runs(loc.owner) {
+			continue
+		}
+		dir := filepath.Join(s.HomeDir, "Library", loc.dir)
+		entries, err := s.readDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(dir, err)
+			}
+			continue
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+		for _, entry := range entries {
+			id, ok := strings.CutSuffix(entry.Name(), loc.suffix)
+			if !ok || !bundleIDPattern.MatchString(id) {
+				continue
+			}
+			vendor := bundleVendor(id)
+			if vendor == "com.apple" || vendors[vendor] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			size := s.dirSize(path)
+			if size == 0 {
+				continue
+			}
+			item := types.FileItem{Path: path, Size: size, Name: "📦 " + id + " (" + loc.dir + ")", IsDir: entry.IsDir()}
+			if info, err := entry.Info(); err == nil {
+				item.Age = int(time.Since(info.ModTime()).Hours() / 24)
+			}
+			result.Items = append(result.Items, item)
+			result.Total += size
+		}
+	}
+
+	return result
+}
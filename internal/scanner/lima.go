@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "lima", Category: "Colima & Lima VMs", Risk: types.RiskHigh, Sets: []string{SetDev}, Order: 192, Scan: (*Scanner).ScanLimaVMs})
+}
+
+// ScanLimaVMs lists the virtual machines Colima and Lima keep, one item per
+// stopped instance and per detached data disk, and the VM images they
+// downloaded. Colima runs its VMs with Lima under ~/.colima/_lima.
+func (s *Scanner) ScanLimaVMs() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Colima & Lima VMs",
+		Items:    []types.FileItem{},
+	}
+
+	s.addLimaInstances(result, filepath.Join(s.HomeDir, ".lima"), "Lima")
+	s.addLimaInstances(result, filepath.Join(s.HomeDir, ".colima", "_lima"), "Colima")
+
+	caches := []struct {
+		path string
+		name string
+	}{
+		{filepath.Join(s.HomeDir, "Library", "Caches", "lima"), "🖥️ Lima: downloaded images"},
+		{filepath.Join(s.HomeDir, "Library", "Caches", "colima"), "🖥️ Colima: downloaded images"},
+	}
+	for _, cache := range caches {
+		if size := s.dirSize(cache.path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: cache.path, Size: size, Name: cache.name, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	return result
+}
+
+// addLimaInstances adds the instances and data disks in a Lima directory.
+// Running instances, which have a host agent pid file, and disks attached
+// to an instance are left out.
+func (s *Scanner) addLimaInstances(result *types.ScanResult, limaDir, tool string) {
+	entries, err := s.readDir(limaDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(limaDir, err)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), "_") {
+			continue // _config, _disks and other Lima internals
+		}
+		path := filepath.Join(limaDir, entry.Name())
+		if _, err := os.Stat(filepath.Join(path, "ha.pid")); err == nil {
+			continue
+		}
+		s.addVMDisk(result, path, "🖥️ "+tool+": "+limaInstanceName(entry.Name(), tool))
+	}
+
+	disksDir := filepath.Join(limaDir, "_disks")
+	disks, err := s.readDir(disksDir)
+	if err != nil {
+		return
+	}
+	for _, disk := range disks {
+		path := filepath.Join(disksDir, disk.Name())
+		if _, err := os.Lstat(filepath.Join(path, "in_use_by")); err == nil {
+			continue
+		}
+		s.addVMDisk(result, path, "🖥️ "+tool+" disk: "+limaInstanceName(disk.Name(), tool))
+	}
+}
+
+// addVMDisk adds path counting the space its disk images take rather than
+// their length, since VM disks are sparse files
+func (s *Scanner) addVMDisk(result *types.ScanResult, path, name string) {
+	size, err := utils.AllocatedSize(path, s.counts)
+	if err != nil {
+		result.AddError(path, err)
+		return
+	}
+	if size == 0 {
+		return
+	}
+	result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: name, IsDir: true})
+	result.Total += size
+}
+
+// limaInstanceName returns the Colima profile an instance belongs to:
+// "colima" is the default profile and "colima-work" the profile "work"
+func limaInstanceName(instance, tool string) string {
+	if tool != "Colima" {
+		return instance
+	}
+	if profile, ok := strings.CutPrefix(instance, "colima-"); ok {
+		return profile
+	}
+	if instance == "colima" {
+		return "default"
+	}
+	return instance
+}
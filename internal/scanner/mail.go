@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "mail", Category: "Mail Attachments", Risk: types.RiskHigh, Sets: []string{SetFull}, Order: 65, Scan: (*Scanner).ScanMailAttachments})
+}
+
+// ScanMailAttachments reports the attachments Mail keeps, per mailbox, and
+// the copies it saved when they were opened. It is advisory: Mail's store
+// is a database of its own, and files removed behind its back leave
+// messages broken or make Mail download everything again.
+func (s *Scanner) ScanMailAttachments() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Mail Attachments",
+		Items:    []types.FileItem{},
+		Advisory: true,
+		Hint:     "Never delete Mail's files by hand. Remove attachments in Mail with Message › Remove Attachments, or set Download Attachments to None in Mail › Settings › Accounts; quit Mail before emptying Mail Downloads.",
+	}
+
+	downloads := filepath.Join(s.HomeDir, "Library", "Containers", "com.apple.mail", "Data", "Library", "Mail Downloads")
+	if size := s.dirSize(downloads); size > 0 {
+		result.Items = append(result.Items, types.FileItem{Path: downloads, Size: size, Name: "📧 Mail Downloads (copies of opened attachments)", IsDir: true})
+		result.Total += size
+	}
+
+	// Mail keeps each account in ~/Library/Mail/V<n>/<account id>, with
+	// attachments in an Attachments folder inside each mailbox's .mbox
+	mailDir := filepath.Join(s.HomeDir, "Library", "Mail")
+	versions, err := s.readDir(mailDir)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(mailDir, err)
+	}
+	for _, version := range versions {
+		if !version.IsDir() || !strings.HasPrefix(version.Name(), "V") {
+			continue
+		}
+		versionDir := filepath.Join(mailDir, version.Name())
+		accounts, err := s.readDir(versionDir)
+		if err != nil {
+			result.AddError(versionDir, err)
+			continue
+		}
+		for _, account := range accounts {
+			if account.IsDir() && account.Name() != "MailData" {
+				s.addMailboxAttachments(result, filepath.Join(versionDir, account.Name()))
+			}
+		}
+	}
+
+	return result
+}
+
+// addMailboxAttachments adds the attachments of each mailbox of an account
+func (s *Scanner) addMailboxAttachments(result *types.ScanResult, accountDir string) {
+	sizes := make(map[string]int64)
+	s.walkDir(accountDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+		if !d.IsDir() || d.Name() != "Attachments" {
+			return nil
+		}
+		// Attribute the attachments to the innermost mailbox holding them
+		mailbox := filepath.Dir(path)
+		for mailbox != accountDir && !strings.HasSuffix(mailbox, ".mbox") {
+			mailbox = filepath.Dir(mailbox)
+		}
+		if mailbox != accountDir {
+			sizes[mailbox] += s.dirSize(path)
+		}
+		return filepath.SkipDir
+	})
+
+	mailboxes := make([]string, 0, len(sizes))
+	for mailbox := range sizes {
+		mailboxes = append(mailboxes, mailbox)
+	}
+	sort.Strings(mailboxes)
+	account := filepath.Base(accountDir)
+	if len(account) > 8 {
+		account = account[:8]
+	}
+	for _, mailbox := range mailboxes {
+		size := sizes[mailbox]
+		if size == 0 {
+			continue
+		}
+		rel, _ := filepath.Rel(accountDir, mailbox)
+		name := strings.ReplaceAll(filepath.ToSlash(rel), ".mbox", "")
+		result.Items = append(result.Items, types.FileItem{
+			Path:  mailbox,
+			Size:  size,
+			Name:  fmt.Sprintf("📧 %s attachments (account %s)", name, account),
+			IsDir: true,
+		})
+		result.Total += size
+	}
+}
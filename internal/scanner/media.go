@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"path/filepath"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "media-apps", Category: "Media App Caches", Risk: types.RiskMedium, Sets: []string{SetFull}, Order: 17, Scan: (*Scanner).ScanMediaAppCaches})
+}
+
+// mediaCaches are the streaming and artwork caches of media apps, relative
+// to ~/Library/Application Support
+var mediaCaches = []struct {
+	path []string
+	name string
+}{
+	{[]string{"Spotify", "PersistentCache"}, "Spotify: streaming cache (includes songs downloaded for offline listening)"},
+	{[]string{"Plex Media Server", "Cache"}, "Plex Media Server: cache"},
+	{[]string{"Plex", "Cache"}, "Plex: cache"},
+	{[]string{"Tidal", "Cache"}, "TIDAL: cache"},
+	{[]string{"Deezer", "Cache"}, "Deezer: cache"},
+	{[]string{"Amazon Music", "Data", "CacheData"}, "Amazon Music: streaming cache"},
+}
+
+// ScanMediaAppCaches scans the caches of streaming apps, such as the
+// Spotify cache, which grows past 10 GB with offline playlists
+func (s *Scanner) ScanMediaAppCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Media App Caches",
+		Items:    []types.FileItem{},
+	}
+
+	supportDir := filepath.Join(s.HomeDir, "Library", "Application Support")
+	for _, cache := range mediaCaches {
+		path := filepath.Join(append([]string{supportDir}, cache.path...)...)
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: "🎵 " + cache.name, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "ml-models", Category: "ML Model Caches", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 199, Scan: (*Scanner).ScanMLModelCaches})
+}
+
+// envOrHome returns the value of env if set, otherwise the path made of
+// elems in the home directory
+func (s *Scanner) envOrHome(env string, elems ...string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	return filepath.Join(append([]string{s.HomeDir}, elems...)...)
+}
+
+// ScanMLModelCaches lists the model weights and datasets downloaded by
+// Hugging Face libraries, PyTorch, Keras and Whisper, one item per model
+func (s *Scanner) ScanMLModelCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "ML Model Caches",
+		Items:    []types.FileItem{},
+	}
+	add := func(path, name string) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return
+		}
+		size := info.Size()
+		if info.IsDir() {
+			size = s.dirSize(path)
+		}
+		if size == 0 {
+			return
+		}
+		result.Items = append(result.Items, types.FileItem{
+			Path:  path,
+			Size:  size,
+			Name:  name,
+			IsDir: info.IsDir(),
+			Age:   int(time.Since(info.ModTime()).Hours() / 24),
+		})
+		result.Total += size
+	}
+	// each adds an item per entry of dir, named by name, which returns ""
+	// to skip an entry
+	each := func(dir string, name func(entry os.DirEntry) string) {
+		entries, err := s.readDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(dir, err)
+			}
+			return
+		}
+		for _, entry := range entries {
+			if label := name(entry); label != "" {
+				add(filepath.Join(dir, entry.Name()), label)
+			}
+		}
+	}
+
+	// The Hugging Face hub keeps a folder per repository, such as
+	// models--openai--whisper-large-v3
+	hfHome := s.envOrHome("HF_HOME", ".cache", "huggingface")
+	hubDir := os.Getenv("HF_HUB_CACHE")
+	if hubDir == "" {
+		hubDir = filepath.Join(hfHome, "hub")
+	}
+	each(hubDir, func(entry os.DirEntry) string {
+		kind, repo, ok := strings.Cut(entry.Name(), "--")
+		if !ok || !entry.IsDir() {
+			return ""
+		}
+		return "🤗 Hugging Face " + strings.TrimSuffix(kind, "s") + ": " + strings.ReplaceAll(repo, "--", "/")
+	})
+	// The datasets library and Xet storage keep their own caches; the rest
+	// of HF_HOME holds the token and accelerate's config
+	for _, name := range []string{"datasets", "xet", "assets"} {
+		add(filepath.Join(hfHome, name), "🤗 Hugging Face "+name+" cache")
+	}
+
+	torchHome := s.envOrHome("TORCH_HOME", ".cache", "torch")
+	checkpoints := filepath.Join(torchHome, "hub", "checkpoints")
+	each(checkpoints, func(entry os.DirEntry) string {
+		return "🔥 PyTorch checkpoint: " + entry.Name()
+	})
+	each(filepath.Join(torchHome, "hub"), func(entry os.DirEntry) string {
+		if !entry.IsDir() || entry.Name() == "checkpoints" {
+			return ""
+		}
+		return "🔥 PyTorch Hub: " + entry.Name()
+	})
+
+	kerasHome := s.envOrHome("KERAS_HOME", ".keras")
+	each(filepath.Join(kerasHome, "models"), func(entry os.DirEntry) string {
+		return "🧠 Keras model: " + entry.Name()
+	})
+	each(filepath.Join(kerasHome, "datasets"), func(entry os.DirEntry) string {
+		return "🧠 Keras dataset: " + entry.Name()
+	})
+
+	each(filepath.Join(s.HomeDir, ".cache", "whisper"), func(entry os.DirEntry) string {
+		return "🎙️ Whisper model: " + strings.TrimSuffix(entry.Name(), ".pt")
+	})
+
+	return result
+}
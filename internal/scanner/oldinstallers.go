@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "old-installers", Category: "Installers", Risk: types.RiskMedium, Sets: []string{SetFull}, Order: 41, Scan: (*Scanner).ScanOldInstallers,
+		Strategy: func(s *Scanner) strategy.Strategy { return strategy.MoveToTrash(s.HomeDir) }})
+}
+
+// hdiutilTimeout bounds a single hdiutil call
+const hdiutilTimeout = 20 * time.Second
+
+// DefaultInstallerAge is how many days disk images and packages are kept
+// before they are listed, when Scanner.InstallerAge is unset
+const DefaultInstallerAge = 14
+
+// installerExtensions are disk images and installer packages
+var installerExtensions = []string{".dmg", ".pkg", ".mpkg", ".iso"}
+
+// imagePathPattern finds the attached images in hdiutil info -plist
+var imagePathPattern = regexp.MustCompile(`<key>image-path</key>\s*<string>([^<]*)</string>`)
+
+// runHdiutil runs hdiutil and returns its output
+func (s *Scanner) runHdiutil(args ...string) ([]byte, error) {
+	if s.hdiutil != nil {
+		return s.hdiutil(args...)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hdiutilTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "hdiutil", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("hdiutil %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// mountedImages returns the paths of the disk images currently attached
+func (s *Scanner) mountedImages() map[string]bool {
+	mounted := make(map[string]bool)
+	out, err := s.runHdiutil("info", "-plist")
+	if err != nil {
+		return mounted
+	}
+	for _, m := range imagePathPattern.FindAllSubmatch(out, -1) {
+		mounted[filepath.Clean(string(m[1]))] = true
+	}
+	return mounted
+}
+
+// isInstaller reports whether name is a disk image or installer package
+func isInstaller(name string) bool {
+	return contains(installerExtensions, strings.ToLower(filepath.Ext(name)))
+}
+
+// ScanOldInstallers lists the disk images and installer packages in Downloads
+// and on the Desktop not used for InstallerAge days, going by when they
+// were last opened or changed. Images still attached are skipped.
+func (s *Scanner) ScanOldInstallers() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Installers",
+		Items:    []types.FileItem{},
+	}
+
+	days := s.InstallerAge
+	if days <= 0 {
+		days = DefaultInstallerAge
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	mounted := s.mountedImages()
+
+	for _, dir := range []string{"Downloads", "Desktop"} {
+		root := filepath.Join(s.HomeDir, dir)
+		s.walkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if !os.IsNotExist(err) {
+					result.AddError(path, err)
+				}
+				return nil
+			}
+			// Apps and other bundles hold no installers of their own
+			if d.IsDir() && filepath.Ext(path) == ".app" {
+				return filepath.SkipDir
+			}
+			if path == root || !isInstaller(d.Name()) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			lastUsed := info.ModTime()
+			if atime := utils.AccessTime(info); atime.After(lastUsed) && !d.IsDir() {
+				lastUsed = atime
+			}
+			if lastUsed.After(cutoff) || mounted[path] {
+				return installerWalkResult(d)
+			}
+
+			size := s.dirSize(path)
+			if size == 0 {
+				return installerWalkResult(d)
+			}
+			rel, _ := filepath.Rel(s.HomeDir, path)
+			result.Items = append(result.Items, types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  "💿 " + filepath.ToSlash(rel),
+				Age:   int(time.Since(lastUsed).Hours() / 24),
+				IsDir: d.IsDir(),
+			})
+			result.Total += size
+			return installerWalkResult(d)
+		})
+	}
+
+	return result
+}
+
+// installerWalkResult skips the contents of bundle packages once handled;
+// flat packages and images are files
+func installerWalkResult(d fs.DirEntry) error {
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
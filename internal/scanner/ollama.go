@@ -0,0 +1,227 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "ollama", Category: "Ollama Models", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 201, Scan: (*Scanner).ScanOllamaModels})
+}
+
+// ollamaTimeout bounds a single ollama call
+const ollamaTimeout = 2 * time.Minute
+
+// errNoOllama is returned by runOllama when ollama is not installed
+var errNoOllama = errors.New("ollama is not installed")
+
+// ollamaRegistry is the registry models pulled by plain name come from
+const ollamaRegistry = "registry.ollama.ai"
+
+// ollamaModel is the tags of one model, which share all their blobs, and
+// the blobs no other model uses, which removing it frees
+type ollamaModel struct {
+	names     []string
+	manifests []string
+	blobs     []string
+}
+
+// runOllama runs ollama and returns its combined output
+func (s *Scanner) runOllama(args ...string) ([]byte, error) {
+	if s.ollama != nil {
+		return s.ollama(args...)
+	}
+	if _, err := exec.LookPath("ollama"); err != nil {
+		return nil, errNoOllama
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ollamaTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "ollama", args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return nil, fmt.Errorf("ollama %s: %w: %s", strings.Join(args[:min(2, len(args))], " "), err, msg)
+	}
+	return out, nil
+}
+
+// ollamaModelName turns a manifest path such as
+// registry.ollama.ai/library/llama3/latest into the name ollama shows,
+// llama3:latest
+func ollamaModelName(rel string) string {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	name := strings.Join(parts[:len(parts)-1], "/") + ":" + parts[len(parts)-1]
+	name = strings.TrimPrefix(name, ollamaRegistry+"/")
+	return strings.TrimPrefix(name, "library/")
+}
+
+// ScanOllamaModels lists the models in ~/.ollama/models (or OLLAMA_MODELS)
+// from their manifests, each sized by the blobs no other model shares, and
+// blobs no model uses such as unfinished downloads. Models are removed
+// with `ollama rm`, or their files deleted when ollama isn't running.
+func (s *Scanner) ScanOllamaModels() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Ollama Models",
+		Items:    []types.FileItem{},
+		Method:   "ollama rm",
+	}
+
+	modelsDir := s.envOrHome("OLLAMA_MODELS", ".ollama", "models")
+	manifestsDir := filepath.Join(modelsDir, "manifests")
+	blobsDir := filepath.Join(modelsDir, "blobs")
+
+	// Read each manifest's blobs, counting the models using each blob
+	manifests := make(map[string][]string)
+	users := make(map[string]int)
+	s.walkDir(manifestsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(path, err)
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+		var manifest struct {
+			Config struct{ Digest string }
+			Layers []struct{ Digest string }
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil
+		}
+		blobs := []string{manifest.Config.Digest}
+		for _, layer := range manifest.Layers {
+			blobs = append(blobs, layer.Digest)
+		}
+		for _, digest := range blobs {
+			// Blob files are named sha256-<hex> for digest sha256:<hex>
+			blob := filepath.Join(blobsDir, strings.ReplaceAll(digest, ":", "-"))
+			if digest == "" || contains(manifests[path], blob) {
+				continue
+			}
+			manifests[path] = append(manifests[path], blob)
+			users[blob]++
+		}
+		return nil
+	})
+
+	paths := make([]string, 0, len(manifests))
+	for path := range manifests {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	// Tags of the same model, such as llama3:latest and llama3:8b, go in
+	// one item, as removing one alone frees nothing
+	var order []string
+	groups := make(map[string]*ollamaModel)
+	for _, path := range paths {
+		blobs := append([]string(nil), manifests[path]...)
+		sort.Strings(blobs)
+		key := strings.Join(blobs, "\n")
+		group, ok := groups[key]
+		if !ok {
+			group = &ollamaModel{}
+			groups[key] = group
+			order = append(order, key)
+		}
+		rel, _ := filepath.Rel(manifestsDir, path)
+		group.names = append(group.names, ollamaModelName(rel))
+		group.manifests = append(group.manifests, path)
+	}
+
+	models := make(map[string]*ollamaModel)
+	for _, key := range order {
+		model := groups[key]
+		path := model.manifests[0]
+		var size int64
+		for _, blob := range manifests[path] {
+			if users[blob] > len(model.manifests) {
+				continue
+			}
+			if info, err := os.Stat(blob); err == nil {
+				size += info.Size()
+				model.blobs = append(model.blobs, blob)
+			}
+		}
+		if size == 0 {
+			continue
+		}
+		item := types.FileItem{Path: path, Size: size, Name: "🦙 Ollama: " + strings.Join(model.names, ", ")}
+		if info, err := os.Stat(path); err == nil {
+			item.Age = int(time.Since(info.ModTime()).Hours() / 24)
+		}
+		result.Items = append(result.Items, item)
+		result.Total += size
+		models[path] = model
+	}
+
+	entries, err := s.readDir(blobsDir)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(blobsDir, err)
+	}
+	for _, entry := range entries {
+		blob := filepath.Join(blobsDir, entry.Name())
+		if users[blob] > 0 || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() == 0 {
+			continue
+		}
+		name := "🦙 Ollama: unused blob " + shortID(strings.TrimPrefix(entry.Name(), "sha256-"))
+		if strings.Contains(entry.Name(), "-partial") {
+			name = "🦙 Ollama: unfinished download"
+		}
+		result.Items = append(result.Items, types.FileItem{
+			Path: blob,
+			Size: info.Size(),
+			Name: name,
+			Age:  int(time.Since(info.ModTime()).Hours() / 24),
+		})
+		result.Total += info.Size()
+	}
+
+	result.Remover = func(path string) error {
+		model, ok := models[path]
+		if !ok {
+			return s.deleter().Remove(path)
+		}
+		args := append([]string{"rm"}, model.names...)
+		_, err := s.runOllama(args...)
+		if err == nil {
+			return nil
+		}
+		// ollama rm asks the server; with it stopped, the files can go
+		// directly
+		if !errors.Is(err, errNoOllama) && !strings.Contains(err.Error(), "could not connect") {
+			return types.NewPathError("remove", path, err)
+		}
+		for _, file := range append(model.blobs, model.manifests...) {
+			if err := s.deleter().Remove(file); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "photos", Category: "Photos Library Caches", Risk: types.RiskHigh, Sets: []string{SetFull}, Order: 67, Scan: (*Scanner).ScanPhotosCaches})
+}
+
+// photosCaches are the folders Photos generates in a library's resources
+var photosCaches = []struct{ dir, name string }{
+	{"derivatives", "previews and thumbnails"},
+	{"renders", "rendered edits"},
+	{"caches", "caches"},
+	{"cloudsharing", "Shared Albums"},
+}
+
+// ScanPhotosCaches reports the previews, thumbnails and other files Photos
+// generates inside each library in ~/Pictures, so the space they take is
+// accounted for. It is advisory: a library is a database only Photos may
+// change, and the files are never deleted.
+func (s *Scanner) ScanPhotosCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Photos Library Caches",
+		Items:    []types.FileItem{},
+		Advisory: true,
+		Hint:     "Managed by Photos and never deleted by this tool. With iCloud Photos, Optimize Mac Storage in Photos › Settings › iCloud keeps smaller versions on this Mac.",
+	}
+
+	picturesDir := filepath.Join(s.HomeDir, "Pictures")
+	entries, err := s.readDir(picturesDir)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(picturesDir, err)
+	}
+	var libraries []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".photoslibrary") {
+			libraries = append(libraries, entry.Name())
+		}
+	}
+
+	for _, library := range libraries {
+		label := "🖼️ Photos: "
+		if len(libraries) > 1 {
+			label = "🖼️ " + strings.TrimSuffix(library, ".photoslibrary") + ": "
+		}
+		for _, cache := range photosCaches {
+			path := filepath.Join(picturesDir, library, "resources", cache.dir)
+			if size := s.dirSize(path); size > 0 {
+				result.Items = append(result.Items, types.FileItem{
+					Path:  path,
+					Size:  size,
+					Name:  label + cache.name + " (managed by Photos)",
+					IsDir: true,
+				})
+				result.Total += size
+			}
+		}
+	}
+
+	return result
+}
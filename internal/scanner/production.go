@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "render-files", Category: "Production Render Files", Risk: types.RiskHigh, Sets: []string{SetFull}, Order: 69, Scan: (*Scanner).ScanRenderFiles})
+}
+
+// ScanRenderFiles reports the render files and optimized media inside
+// Final Cut Pro libraries, and the freeze files and bounces inside Logic
+// Pro projects, per library or project. It is advisory: the apps track
+// these files and should be the ones to delete them.
+func (s *Scanner) ScanRenderFiles() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Production Render Files",
+		Items:    []types.FileItem{},
+		Advisory: true,
+		Hint:     "In Final Cut Pro, select the library and choose File › Delete Generated Library Files. In Logic Pro, unfreeze tracks, and remove unused bounces in the Project Audio browser.",
+	}
+
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if utils.ShouldSkipDir(path) || d.Name() == "node_modules" {
+			return filepath.SkipDir
+		}
+		if library, ok := strings.CutSuffix(d.Name(), ".fcpbundle"); ok {
+			s.addGenerated(result, path, "🎬 "+library, map[string]string{
+				"Render Files":     "render files",
+				"Transcoded Media": "optimized and proxy media",
+			})
+			return filepath.SkipDir
+		}
+		if project, ok := strings.CutSuffix(d.Name(), ".logicx"); ok {
+			s.addGenerated(result, path, "🎹 "+project, map[string]string{
+				"Freeze Files.nosync": "freeze files",
+				"Freeze Files":        "freeze files",
+				"Bounces":             "bounces",
+			})
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	return result
+}
+
+// addGenerated adds the folders of a package the app generated, summed per
+// kind: kinds maps folder names, found at any depth, to what they hold
+func (s *Scanner) addGenerated(result *types.ScanResult, pkg, label string, kinds map[string]string) {
+	sizes := make(map[string]int64)
+	// Items need paths of their own, so each shows the first folder found
+	first := make(map[string]string)
+	var order []string
+	s.walkDir(pkg, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		kind, ok := kinds[d.Name()]
+		if !ok {
+			return nil
+		}
+		if _, seen := first[kind]; !seen {
+			first[kind] = path
+			order = append(order, kind)
+		}
+		sizes[kind] += s.dirSize(path)
+		return filepath.SkipDir
+	})
+
+	for _, kind := range order {
+		if size := sizes[kind]; size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: first[kind], Size: size, Name: label + ": " + kind, IsDir: true})
+			result.Total += size
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "quicklook", Category: "QuickLook Thumbnails", Risk: types.RiskLow, Sets: []string{SetFull, SetQuick}, Order: 13, Scan: (*Scanner).ScanQuickLookCache,
+		Strategy: func(*Scanner) strategy.Strategy { return strategy.RunCommand("qlmanage", "-r", "cache") }})
+}
+
+// darwinUserCacheDir returns the per-user cache folder macOS keeps in
+// /var/folders, the C next to the T that TMPDIR points at, or "" if TMPDIR
+// isn't one of those
+func darwinUserCacheDir() string {
+	tmp := filepath.Clean(os.TempDir())
+	if filepath.Base(tmp) != "T" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(tmp), "C")
+}
+
+// ScanQuickLookCache scans the thumbnail cache QuickLook and Finder keep in
+// the per-user cache folder. It is reset with `qlmanage -r cache`, as the
+// QuickLook daemon holds it open.
+func (s *Scanner) ScanQuickLookCache() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "QuickLook Thumbnails",
+		Items:    []types.FileItem{},
+	}
+
+	cacheDir := darwinUserCacheDir()
+	if cacheDir == "" {
+		return result
+	}
+	path := filepath.Join(cacheDir, "com.apple.QuickLook.thumbnailcache")
+	if size := s.dirSize(path); size > 0 {
+		result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: "🖼️ QuickLook thumbnail cache", IsDir: true})
+		result.Total += size
+	}
+
+	return result
+}
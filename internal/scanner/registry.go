@@ -0,0 +1,180 @@
+package scanner
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// Scan sets a category can belong to
+const (
+	SetFull  = "full"  // Full System Scan
+	SetDev   = "dev"   // Dev Scan
+	SetQuick = "quick" // Quick Clean: safe categories only, no project walk
+)
+
+// CategoryScanner scans a single category
+type CategoryScanner interface {
+	Name() string     // Stable identifier, e.g. "node-modules"
+	Category() string // Category shown to the user, e.g. "Node Modules"
+	Scan(ctx context.Context) *types.ScanResult
+	RiskLevel() types.RiskLevel // How disruptive deleting the whole category is
+}
+
+// Registration describes a built-in scanner. Scanners register themselves
+// from init functions next to their scan methods.
+type Registration struct {
+	Name     string
+	Category string
+	Risk     types.RiskLevel
+	Sets     []string // Scan sets that include the category
+	Order    int      // Position within a set, lowest first
+	Scan     func(s *Scanner) *types.ScanResult
+	// Strategy returns how the category's items are cleaned; nil deletes them
+	Strategy func(s *Scanner) strategy.Strategy
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Registration)
+)
+
+// Register adds a scanner to the registry, replacing any scanner with the
+// same name
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[r.Name] = r
+}
+
+// Registrations returns every registered scanner, ordered by Order then name
+func Registrations() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	regs := make([]Registration, 0, len(registry))
+	for _, r := range registry {
+		regs = append(regs, r)
+	}
+	sort.Slice(regs, func(i, j int) bool {
+		if regs[i].Order != regs[j].Order {
+			return regs[i].Order < regs[j].Order
+		}
+		return regs[i].Name < regs[j].Name
+	})
+	return regs
+}
+
+// boundScanner is a registered scanner bound to a Scanner's directories
+type boundScanner struct {
+	reg Registration
+	s   *Scanner
+	set string // Scan set the scanner runs in
+}
+
+func (b boundScanner) Name() string               { return b.reg.Name }
+func (b boundScanner) Category() string           { return b.reg.Category }
+func (b boundScanner) RiskLevel() types.RiskLevel { return b.reg.Risk }
+
+// Scan runs the scanner unless ctx is already done
+func (b boundScanner) Scan(ctx context.Context) *types.ScanResult {
+	if ctx.Err() != nil {
+		return &types.ScanResult{Category: b.reg.Category, Items: []types.FileItem{}}
+	}
+	// Scanners run in parallel, so each counts its visits on its own copy
+	counts := &utils.WalkCounts{}
+	s := &Scanner{
+		HomeDir:           b.s.HomeDir,
+		RootDir:           b.s.RootDir,
+		Plugins:           b.s.Plugins,
+		Disabled:          b.s.Disabled,
+		Roots:             b.s.Roots,
+		InstallerAge:      b.s.InstallerAge,
+		ScreenshotAge:     b.s.ScreenshotAge,
+		ScreenshotArchive: b.s.ScreenshotArchive,
+		Sizes:             b.s.Sizes,
+		Remove:            b.s.Remove,
+		Results:           b.s.Results,
+		docker:            b.s.docker,
+		dockerAPI:         b.s.dockerAPI,
+		tmutil:            b.s.tmutil,
+		xcrun:             b.s.xcrun,
+		npm:               b.s.npm,
+		brew:              b.s.brew,
+		vagrant:           b.s.vagrant,
+		rustup:            b.s.rustup,
+		conda:             b.s.conda,
+		ollama:            b.s.ollama,
+		hdiutil:           b.s.hdiutil,
+		purgeable:         b.s.purgeable,
+		mountType:         b.s.mountType,
+		counts:            counts,
+		set:               b.set,
+		ctx:               ctx,
+	}
+	result := b.reg.Scan(s)
+	result.DirsVisited = counts.Dirs.Load()
+	result.FilesVisited = counts.Files.Load()
+	if b.reg.Strategy != nil && result.Remover == nil {
+		st := b.reg.Strategy(b.s)
+		paths := make([]string, len(result.Items))
+		for i, item := range result.Items {
+			paths[i] = item.Path
+		}
+		result.Remover = strategy.Items(st, paths).Remove
+		result.Method = st.Name()
+		if r, ok := st.(strategy.Reporter); ok {
+			result.Report = r.Report
+		}
+	}
+	return result
+}
+
+// Scanners returns the registered scanners in set, leaving out those named
+// in s.Disabled
+func (s *Scanner) Scanners(set string) []CategoryScanner {
+	var scanners []CategoryScanner
+	for _, r := range Registrations() {
+		if s.Disabled[r.Name] || !contains(r.Sets, set) {
+			continue
+		}
+		scanners = append(scanners, boundScanner{reg: r, s: s, set: set})
+	}
+	return scanners
+}
+
+// runs reports whether the scanner called name runs alongside the one
+// scanning, so paths it lists can be left to it. It is false outside a scan
+// set, when a scan method is called directly.
+func (s *Scanner) runs(name string) bool {
+	registryMu.RLock()
+	r, ok := registry[name]
+	registryMu.RUnlock()
+	return ok && s.set != "" && contains(r.Sets, s.set) && !s.Disabled[name]
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryRisk returns the risk of deleting everything in a category,
+// defaulting to medium for unknown categories
+func CategoryRisk(category string) types.RiskLevel {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, r := range registry {
+		if r.Category == category {
+			return r.Risk
+		}
+	}
+	return types.RiskMedium
+}
@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "runtimes", Category: "Runtime Versions", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 140, Scan: (*Scanner).ScanRuntimeVersions})
+}
+
+// versionManager is a tool keeping several versions of a language runtime
+// side by side
+type versionManager struct {
+	tool    string // e.g. "nvm"
+	label   string // e.g. "⬢ Node"
+	rootEnv string // Variable overriding root, e.g. NVM_DIR
+	root    string // Relative to the home directory
+	subdir  string // Where versions are installed, relative to root
+	// current returns the versions in use, given root and the installed ones
+	current func(root string, installed []string) []string
+}
+
+var versionManagers = []versionManager{
+	{tool: "nvm", label: "⬢ Node", rootEnv: "NVM_DIR", root: ".nvm", subdir: filepath.Join("versions", "node"), current: nvmCurrent},
+	{tool: "pyenv", label: "🐍 Python", rootEnv: "PYENV_ROOT", root: ".pyenv", subdir: "versions", current: envOrFileVersions("PYENV_VERSION")},
+	{tool: "rbenv", label: "💎 Ruby", rootEnv: "RBENV_ROOT", root: ".rbenv", subdir: "versions", current: envOrFileVersions("RBENV_VERSION")},
+}
+
+// ScanRuntimeVersions lists the Node, Python and Ruby versions installed
+// with nvm, pyenv and rbenv, leaving out the one each is set to use
+func (s *Scanner) ScanRuntimeVersions() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Runtime Versions",
+		Items:    []types.FileItem{},
+	}
+
+	for _, vm := range versionManagers {
+		root := os.Getenv(vm.rootEnv)
+		if root == "" {
+			root = filepath.Join(s.HomeDir, vm.root)
+		}
+		dir := filepath.Join(root, vm.subdir)
+		entries, err := s.readDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(dir, err)
+			}
+			continue
+		}
+
+		// pyenv-virtualenv links environments in among the versions
+		var installed []string
+		for _, entry := range entries {
+			if entry.IsDir() {
+				installed = append(installed, entry.Name())
+			}
+		}
+		sort.Slice(installed, func(i, j int) bool {
+			return versionLess(strings.TrimPrefix(installed[i], "v"), strings.TrimPrefix(installed[j], "v"))
+		})
+		inUse := vm.current(root, installed)
+
+		for _, version := range installed {
+			if contains(inUse, version) {
+				continue
+			}
+			path := filepath.Join(dir, version)
+			size := s.dirSize(path)
+			if size == 0 {
+				continue
+			}
+			item := types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  vm.label + " " + strings.TrimPrefix(version, "v") + " (" + vm.tool + ")",
+				IsDir: true,
+			}
+			if info, err := os.Stat(path); err == nil {
+				item.Age = int(time.Since(info.ModTime()).Hours() / 24)
+			}
+			result.Items = append(result.Items, item)
+			result.Total += size
+		}
+	}
+
+	return result
+}
+
+// envOrFileVersions returns the current versions of pyenv and rbenv: those
+// named by env, otherwise by the global version file in their root
+func envOrFileVersions(env string) func(root string, installed []string) []string {
+	return func(root string, installed []string) []string {
+		value := os.Getenv(env)
+		if value == "" {
+			data, err := os.ReadFile(filepath.Join(root, "version"))
+			if err != nil {
+				return nil
+			}
+			value = string(data)
+		}
+		// pyenv allows several, separated by colons, spaces or lines
+		return strings.FieldsFunc(value, func(r rune) bool {
+			return r == ':' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+		})
+	}
+}
+
+// nvmCurrent resolves nvm's default alias, following aliases such as
+// lts/iron, to the newest installed version it matches
+func nvmCurrent(root string, installed []string) []string {
+	alias := "default"
+	for hops := 0; hops < 5; hops++ {
+		data, err := os.ReadFile(filepath.Join(root, "alias", filepath.FromSlash(alias)))
+		if err != nil {
+			break
+		}
+		alias = strings.TrimSpace(string(data))
+	}
+	if alias == "default" || alias == "" {
+		return nil
+	}
+
+	if alias == "node" || alias == "stable" {
+		if len(installed) == 0 {
+			return nil
+		}
+		return installed[len(installed)-1:]
+	}
+	// A version prefix such as 18 or v18.17 matches the newest installed
+	// 18.x or 18.17.x
+	prefix := "v" + strings.TrimPrefix(alias, "v")
+	for i := len(installed) - 1; i >= 0; i-- {
+		if installed[i] == prefix || strings.HasPrefix(installed[i], prefix+".") {
+			return []string{installed[i]}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "rustup", Category: "Rust Toolchains", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 125, Scan: (*Scanner).ScanRustToolchains})
+}
+
+// rustupTimeout bounds a single rustup call
+const rustupTimeout = 2 * time.Minute
+
+// errNoRustup is returned by runRustup when rustup is not installed
+var errNoRustup = errors.New("rustup is not installed")
+
+// runRustup runs rustup and returns its combined output
+func (s *Scanner) runRustup(args ...string) ([]byte, error) {
+	if s.rustup != nil {
+		return s.rustup(args...)
+	}
+	if _, err := exec.LookPath("rustup"); err != nil {
+		return nil, errNoRustup
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rustupTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "rustup", args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return nil, fmt.Errorf("rustup %s: %w: %s", strings.Join(args[:min(2, len(args))], " "), err, msg)
+	}
+	return out, nil
+}
+
+// rustupHome returns where rustup keeps its toolchains, from RUSTUP_HOME if
+// set, otherwise ~/.rustup
+func (s *Scanner) rustupHome() string {
+	if dir := os.Getenv("RUSTUP_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(s.HomeDir, ".rustup")
+}
+
+// ScanRustToolchains lists the rustup toolchains other than the default,
+// noting those a directory override still pins, and rustup's leftover
+// downloads. Toolchains are removed with `rustup toolchain uninstall`.
+func (s *Scanner) ScanRustToolchains() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Rust Toolchains",
+		Items:    []types.FileItem{},
+		Method:   "rustup toolchain uninstall",
+	}
+
+	home := s.rustupHome()
+	defaultToolchain, overrides := readRustupSettings(filepath.Join(home, "settings.toml"))
+	toolchainsDir := filepath.Join(home, "toolchains")
+	toolchains := make(map[string]string)
+	overrideDirs := make([]string, 0, len(overrides))
+	for dir := range overrides {
+		overrideDirs = append(overrideDirs, dir)
+	}
+	sort.Strings(overrideDirs)
+
+	entries, err := s.readDir(toolchainsDir)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(toolchainsDir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || isToolchain(name, defaultToolchain) {
+			continue
+		}
+		path := filepath.Join(toolchainsDir, name)
+		size := s.dirSize(path)
+		if size == 0 {
+			continue
+		}
+		label := "🦀 Toolchain: " + name
+		for _, dir := range overrideDirs {
+			if isToolchain(name, overrides[dir]) {
+				label += " (override for " + dir + ")"
+				break
+			}
+		}
+		item := types.FileItem{Path: path, Size: size, Name: label, IsDir: true}
+		if info, err := entry.Info(); err == nil {
+			item.Age = int(time.Since(info.ModTime()).Hours() / 24)
+		}
+		result.Items = append(result.Items, item)
+		result.Total += size
+		toolchains[path] = name
+	}
+
+	for _, dir := range []struct{ name, label string }{
+		{"downloads", "🦀 rustup downloads"},
+		{"tmp", "🦀 rustup temporary files"},
+	} {
+		path := filepath.Join(home, dir.name)
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: dir.label, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	result.Remover = func(path string) error {
+		toolchain, ok := toolchains[path]
+		if !ok {
+			return s.deleter().Remove(path)
+		}
+		if _, err := s.runRustup("toolchain", "uninstall", toolchain); err != nil {
+			if errors.Is(err, errNoRustup) {
+				return s.deleter().Remove(path)
+			}
+			return types.NewPathError("remove", path, err)
+		}
+		return nil
+	}
+	return result
+}
+
+// isToolchain reports whether the installed toolchain dir is the one a
+// setting names; settings may leave out the host triple, e.g. "stable"
+// for stable-aarch64-apple-darwin
+func isToolchain(dir, name string) bool {
+	if name == "" {
+		return false
+	}
+	if dir == name {
+		return true
+	}
+	// The rest must be a host triple such as aarch64-apple-darwin, not
+	// the date of a dated nightly such as nightly-2024-01-01
+	triple, ok := strings.CutPrefix(dir, name+"-")
+	return ok && strings.Count(triple, "-") >= 2 && triple[0] >= 'a' && triple[0] <= 'z'
+}
+
+// readRustupSettings reads the default toolchain and the toolchains pinned
+// to directories with `rustup override` from rustup's settings.toml
+func readRustupSettings(path string) (defaultToolchain string, overrides map[string]string) {
+	overrides = make(map[string]string)
+	f, err := os.Open(path)
+	if err != nil {
+		return "", overrides
+	}
+	defer f.Close()
+
+	var section string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch {
+		case section == "" && key == "default_toolchain":
+			defaultToolchain = value
+		case section == "overrides":
+			overrides[key] = value
+		}
+	}
+	return defaultToolchain, overrides
+}
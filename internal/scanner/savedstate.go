@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "saved-state", Category: "Saved Application State", Risk: types.RiskLow, Sets: []string{SetFull, SetQuick}, Order: 12, Scan: (*Scanner).ScanSavedAppState})
+}
+
+// ScanSavedAppState scans the window state apps save in ~/Library/Saved
+// Application State to reopen their windows, one item per app
+func (s *Scanner) ScanSavedAppState() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Saved Application State",
+		Items:    []types.FileItem{},
+	}
+
+	stateDir := filepath.Join(s.HomeDir, "Library", "Saved Application State")
+	entries, err := s.readDir(stateDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(stateDir, err)
+		}
+		return result
+	}
+	for _, entry := range entries {
+		app, ok := strings.CutSuffix(entry.Name(), ".savedState")
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(stateDir, entry.Name())
+		size := s.dirSize(path)
+		if size == 0 {
+			continue
+		}
+		item := types.FileItem{Path: path, Size: size, Name: "🪟 " + app, IsDir: true}
+		if info, err := entry.Info(); err == nil {
+			item.Age = int(time.Since(info.ModTime()).Hours() / 24)
+		}
+		result.Items = append(result.Items, item)
+		result.Total += size
+	}
+
+	return result
+}
@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -8,15 +9,52 @@ import (
 	"sync"
 	"time"
 
+	"github.com/rahulvramesh/cleanWithCli/internal/plugin"
+	"github.com/rahulvramesh/cleanWithCli/internal/sizecache"
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
 	"github.com/rahulvramesh/cleanWithCli/internal/types"
 	"github.com/rahulvramesh/cleanWithCli/internal/utils"
 )
 
 // Scanner performs the file system scanning
 type Scanner struct {
-	HomeDir string
-	Results map[string]*types.ScanResult
-	mu      sync.Mutex
+	HomeDir           string
+	RootDir           string             // Prefix for system-wide locations such as /Library
+	Plugins           []*plugin.Plugin   // External scanners run alongside the built-in ones
+	Disabled          map[string]bool    // Names of registered scanners to skip
+	Roots             []string           // Folders cleaned of .DS_Store and AppleDouble files, nil for the home folder and mounted volumes
+	InstallerAge      int                // Days before disk images and packages are listed, 0 for DefaultInstallerAge
+	ScreenshotAge     int                // Days before Desktop screenshots are listed, 0 for DefaultScreenshotAge
+	ScreenshotArchive string             // Folder old screenshots are moved into, empty to move them to the Trash
+	Sizes             *sizecache.Cache   // Recently measured directory sizes, nil to measure everything
+	Remove            func(string) error // Deletes items of categories cleaned by deletion, nil for utils.RemovePath
+	Results           map[string]*types.ScanResult
+	mu                sync.Mutex
+	docker            func(args ...string) ([]byte, error)      // Runs the docker CLI; nil runs the real one
+	dockerAPI         func(method, path string) ([]byte, error) // Calls the Docker Engine API; nil uses the daemon's socket
+	tmutil            func(args ...string) ([]byte, error)      // Runs tmutil; nil runs the real one
+	xcrun             func(args ...string) ([]byte, error)      // Runs xcrun; nil runs the real one
+	npm               func(args ...string) ([]byte, error)      // Runs npm; nil runs the real one
+	brew              func(args ...string) ([]byte, error)      // Runs brew; nil runs the real one
+	vagrant           func(args ...string) ([]byte, error)      // Runs vagrant; nil runs the real one
+	rustup            func(args ...string) ([]byte, error)      // Runs rustup; nil runs the real one
+	conda             func(args ...string) ([]byte, error)      // Runs conda, or mamba; nil runs the real one
+	ollama            func(args ...string) ([]byte, error)      // Runs ollama; nil runs the real one
+	hdiutil           func(args ...string) ([]byte, error)      // Runs hdiutil; nil runs the real one
+	purgeable         func() (int64, error)                     // Purgeable space on the home volume; nil asks macOS
+	mountType         func(path string) string                  // File system type of the mount holding path; nil asks the OS
+	counts            *utils.WalkCounts                         // What the running scanner visited, nil to not count
+	set               string                                    // Scan set being run, empty when a scan method is called directly
+	ctx               context.Context                           // Scan whose cancellation cuts walks short, nil for none
+}
+
+func init() {
+	Register(Registration{Name: "caches", Category: "Cache Files", Risk: types.RiskLow, Sets: []string{SetFull, SetQuick}, Order: 10, Scan: (*Scanner).ScanCacheFiles})
+	Register(Registration{Name: "logs", Category: "Log Files", Risk: types.RiskLow, Sets: []string{SetFull}, Order: 20, Scan: (*Scanner).ScanLogFiles})
+	Register(Registration{Name: "old-logs", Category: "Old Log Files", Risk: types.RiskLow, Sets: []string{SetQuick}, Order: 25, Scan: (*Scanner).ScanOldLogFiles})
+	Register(Registration{Name: "trash", Category: "Trash", Risk: types.RiskMedium, Sets: []string{SetFull, SetQuick}, Order: 30, Scan: (*Scanner).ScanTrash})
+	Register(Registration{Name: "downloads", Category: "Old Downloads", Risk: types.RiskHigh, Sets: []string{SetFull}, Order: 40, Scan: (*Scanner).ScanDownloads,
+		Strategy: func(s *Scanner) strategy.Strategy { return strategy.MoveToTrash(s.HomeDir) }})
 }
 
 // NewScanner creates a new scanner instance
@@ -24,10 +62,24 @@ func NewScanner() *Scanner {
 	homeDir, _ := os.UserHomeDir()
 	return &Scanner{
 		HomeDir: homeDir,
+		RootDir: "/",
 		Results: make(map[string]*types.ScanResult),
 	}
 }
 
+// deleter returns the strategy deleting items, rate limited when Remove is
+func (s *Scanner) deleter() strategy.Strategy {
+	if s.Remove == nil {
+		return strategy.RemoveAll
+	}
+	return strategy.Delete(s.Remove)
+}
+
+// systemPath joins elem onto the scanner's root directory
+func (s *Scanner) systemPath(elem ...string) string {
+	return filepath.Join(append([]string{s.RootDir}, elem...)...)
+}
+
 // ScanCacheFiles scans cache files
 func (s *Scanner) ScanCacheFiles() *types.ScanResult {
 	result := &types.ScanResult{
@@ -37,23 +89,33 @@ func (s *Scanner) ScanCacheFiles() *types.ScanResult {
 
 	cacheDirs := []string{
 		filepath.Join(s.HomeDir, "Library", "Caches"),
-		"/Library/Caches",
+		s.systemPath("Library", "Caches"),
 		filepath.Join(s.HomeDir, ".cache"),
 	}
 
+	// The Homebrew Cache category cleans its folder with brew cleanup
+	owned := make(map[string]bool)
+	if s.runs("homebrew") {
+		owned[filepath.Join(s.HomeDir, "Library", "Caches", "Homebrew")] = true
+	}
+
 	for _, dir := range cacheDirs {
 		if _, err := os.Stat(dir); err != nil {
 			continue
 		}
 
-		entries, err := os.ReadDir(dir)
+		entries, err := s.readDir(dir)
 		if err != nil {
+			result.AddError(dir, err)
 			continue
 		}
 
 		for _, entry := range entries {
 			path := filepath.Join(dir, entry.Name())
-			size, _ := utils.GetDirSize(path)
+			if owned[path] {
+				continue
+			}
+			size := s.dirSize(path)
 			if size > 0 {
 				result.Items = append(result.Items, types.FileItem{
 					Path:  path,
@@ -71,15 +133,29 @@ func (s *Scanner) ScanCacheFiles() *types.ScanResult {
 
 // ScanLogFiles scans log files
 func (s *Scanner) ScanLogFiles() *types.ScanResult {
+	return s.scanLogs("Log Files", 0)
+}
+
+// OldLogDays is how old a log must be for Quick Clean to remove it
+const OldLogDays = 7
+
+// ScanOldLogFiles scans log files not modified for OldLogDays
+func (s *Scanner) ScanOldLogFiles() *types.ScanResult {
+	return s.scanLogs("Old Log Files", OldLogDays)
+}
+
+// scanLogs lists log files at least minDays old
+func (s *Scanner) scanLogs(category string, minDays int) *types.ScanResult {
 	result := &types.ScanResult{
-		Category: "Log Files",
+		Category: category,
 		Items:    []types.FileItem{},
 	}
+	cutoff := time.Now().AddDate(0, 0, -minDays)
 
 	logDirs := []string{
 		filepath.Join(s.HomeDir, "Library", "Logs"),
-		"/Library/Logs",
-		"/var/log",
+		s.systemPath("Library", "Logs"),
+		s.systemPath("var", "log"),
 	}
 
 	for _, dir := range logDirs {
@@ -87,17 +163,19 @@ func (s *Scanner) ScanLogFiles() *types.ScanResult {
 			continue
 		}
 
-		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		s.walkDir(dir, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
+				result.AddError(path, err)
 				return nil
 			}
 			if !d.IsDir() && strings.Contains(d.Name(), ".log") {
 				info, err := d.Info()
-				if err == nil {
+				if err == nil && (minDays == 0 || info.ModTime().Before(cutoff)) {
 					result.Items = append(result.Items, types.FileItem{
 						Path: path,
 						Size: info.Size(),
 						Name: d.Name(),
+						Age:  int(time.Since(info.ModTime()).Hours() / 24),
 					})
 					result.Total += info.Size()
 				}
@@ -121,14 +199,15 @@ func (s *Scanner) ScanTrash() *types.ScanResult {
 		return result
 	}
 
-	entries, err := os.ReadDir(trashDir)
+	entries, err := s.readDir(trashDir)
 	if err != nil {
+		result.AddError(trashDir, err)
 		return result
 	}
 
 	for _, entry := range entries {
 		path := filepath.Join(trashDir, entry.Name())
-		size, _ := utils.GetDirSize(path)
+		size := s.dirSize(path)
 		result.Items = append(result.Items, types.FileItem{
 			Path: path,
 			Size: size,
@@ -152,8 +231,9 @@ func (s *Scanner) ScanDownloads() *types.ScanResult {
 		return result
 	}
 
-	entries, err := os.ReadDir(downloadsDir)
+	entries, err := s.readDir(downloadsDir)
 	if err != nil {
+		result.AddError(downloadsDir, err)
 		return result
 	}
 
@@ -167,7 +247,7 @@ func (s *Scanner) ScanDownloads() *types.ScanResult {
 
 		if info.ModTime().Before(cutoff) {
 			path := filepath.Join(downloadsDir, entry.Name())
-			size, _ := utils.GetDirSize(path)
+			size := s.dirSize(path)
 			age := int(time.Since(info.ModTime()).Hours() / 24)
 
 			result.Items = append(result.Items, types.FileItem{
@@ -182,3 +262,47 @@ func (s *Scanner) ScanDownloads() *types.ScanResult {
 
 	return result
 }
+
+// dirSize returns the size of the directory at path, from the size cache
+// when it is recent
+func (s *Scanner) dirSize(path string) int64 {
+	if s.cancelled() {
+		return 0
+	}
+	return s.Sizes.Size(path, s.counts)
+}
+
+// cancelled reports whether the scan was cancelled, so walking on is wasted
+func (s *Scanner) cancelled() bool {
+	return s.ctx != nil && s.ctx.Err() != nil
+}
+
+// walkDir walks root like filepath.WalkDir, counting what it visits
+func (s *Scanner) walkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if s.cancelled() {
+			return filepath.SkipAll
+		}
+		if err == nil {
+			s.counts.Add(d.IsDir())
+		}
+		return fn(path, d, err)
+	})
+}
+
+// readDir lists dir like os.ReadDir, counting it and its files
+func (s *Scanner) readDir(dir string) ([]os.DirEntry, error) {
+	if s.cancelled() {
+		return nil, s.ctx.Err()
+	}
+	entries, err := os.ReadDir(dir)
+	if err == nil {
+		s.counts.Add(true)
+		for _, e := range entries {
+			if !e.IsDir() {
+				s.counts.Add(false)
+			}
+		}
+	}
+	return entries, err
+}
@@ -0,0 +1,2299 @@
+package scanner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// fixture describes a file to create inside the fake home or root
+type fixture struct {
+	path string // Relative to the fake home, or to the fake root when sys is set
+	size int
+	age  int // Days since last modification
+	sys  bool
+}
+
+// homeFixtures is the synthetic home directory shared by scanner tests
+var homeFixtures = []fixture{
+	// JavaScript project with dependencies and a build output
+	{path: "code/web/package.json", size: 10},
+	{path: "code/web/node_modules/left-pad/index.js", size: 1000},
+	{path: "code/web/dist/bundle.js", size: 500},
+	// Python project with a virtualenv and bytecode cache
+	{path: "code/py/.venv/lib/site.py", size: 300},
+	{path: "code/py/__pycache__/mod.pyc", size: 50},
+	// Rust project, plus a target dir that does not belong to Cargo
+	{path: "code/rs/Cargo.toml", size: 20},
+	{path: "code/rs/target/debug/app", size: 2000},
+	{path: "code/notes/target/todo.txt", size: 100},
+	// Library caches and logs
+	{path: "Library/Caches/com.example.app/cache.db", size: 4000},
+	{path: "Library/Caches/Homebrew/wget.tar.gz", size: 700},
+	{path: "Library/Logs/app.log", size: 60},
+	{path: "Library/Logs/notes.txt", size: 10},
+	// Trash, downloads and package manager caches
+	{path: ".Trash/old.txt", size: 80},
+	{path: "Downloads/old.dmg", size: 900, age: 60},
+	{path: "Downloads/new.zip", size: 100, age: 1},
+	{path: ".npm/_cacache/index", size: 250},
+	// System-wide locations
+	{path: "Library/Logs/system.log", size: 30, sys: true},
+	{path: "var/log/install.log", size: 15, sys: true},
+	// OS upgrade leftovers, next to an unrelated app
+	{path: "Applications/Install macOS Sonoma.app/Contents/SharedSupport/SharedSupport.dmg", size: 3000, sys: true},
+	{path: "Applications/Safari.app/Contents/MacOS/Safari", size: 40, sys: true},
+	{path: "Library/Updates/052-12345/update.pkg", size: 1200, sys: true},
+	// Sound library content, reported but never cleaned
+	{path: "Library/Application Support/GarageBand/Instrument Library/piano.exs", size: 600, sys: true},
+	{path: "Library/Audio/Apple Loops/Apple/01 Hip Hop/beat.caf", size: 400, sys: true},
+	// Speech and ML assets, reported but never cleaned
+	{path: "System/Library/Speech/Voices/Samantha.SpeechVoice/data", size: 90, sys: true},
+	{path: "System/Library/AssetsV2/com_apple_MobileAsset_SiriUnderstandingAsset/a.asset", size: 70, sys: true},
+	{path: "System/Library/AssetsV2/com_apple_MobileAsset_Font7/font.asset", size: 500, sys: true},
+	{path: "Library/Trial/Treatments/model.bin", size: 30},
+}
+
+// newFakeHomeScanner builds a synthetic home and root directory in a temp dir
+// and returns a scanner pointed at them
+func newFakeHomeScanner(t *testing.T, fixtures []fixture) *Scanner {
+	t.Helper()
+
+	base := t.TempDir()
+	s := &Scanner{
+		HomeDir: filepath.Join(base, "home"),
+		RootDir: filepath.Join(base, "root"),
+		Results: make(map[string]*types.ScanResult),
+		docker: func(...string) ([]byte, error) {
+			return nil, errors.New("docker is not running")
+		},
+		dockerAPI: func(string, string) ([]byte, error) {
+			return nil, errors.New("no Docker socket found")
+		},
+		tmutil: func(...string) ([]byte, error) {
+			return nil, errors.New("tmutil: not found")
+		},
+		xcrun: func(...string) ([]byte, error) {
+			return nil, errors.New("xcrun: not found")
+		},
+		npm: func(...string) ([]byte, error) {
+			return nil, errors.New("npm is not installed")
+		},
+		brew: func(...string) ([]byte, error) {
+			return nil, errors.New("brew is not installed")
+		},
+		vagrant: func(...string) ([]byte, error) {
+			return nil, errNoVagrant
+		},
+		rustup: func(...string) ([]byte, error) {
+			return nil, errNoRustup
+		},
+		conda: func(...string) ([]byte, error) {
+			return nil, errNoConda
+		},
+		ollama: func(...string) ([]byte, error) {
+			return nil, errNoOllama
+		},
+		hdiutil: func(...string) ([]byte, error) {
+			return nil, errors.New("hdiutil: not found")
+		},
+		purgeable: func() (int64, error) {
+			return 0, errors.New("osascript: not found")
+		},
+	}
+
+	// Keep tool-specific locations from leaking in from the real environment
+	t.Setenv("GOPATH", filepath.Join(s.HomeDir, "go"))
+	t.Setenv("DENO_DIR", "")
+	t.Setenv("ANDROID_HOME", "")
+	t.Setenv("ANDROID_SDK_ROOT", "")
+	t.Setenv("KUBECACHEDIR", "")
+	t.Setenv("HELM_CACHE_HOME", "")
+	t.Setenv("TF_PLUGIN_CACHE_DIR", "")
+	t.Setenv("VAGRANT_HOME", "")
+	t.Setenv("RUSTUP_HOME", "")
+	t.Setenv("GOMODCACHE", "")
+	t.Setenv("GOCACHE", "")
+	t.Setenv("NVM_DIR", "")
+	t.Setenv("PYENV_ROOT", "")
+	t.Setenv("PYENV_VERSION", "")
+	t.Setenv("RBENV_ROOT", "")
+	t.Setenv("RBENV_VERSION", "")
+	t.Setenv("CONDA_PREFIX", "")
+	t.Setenv("HF_HOME", "")
+	t.Setenv("HF_HUB_CACHE", "")
+	t.Setenv("TORCH_HOME", "")
+	t.Setenv("KERAS_HOME", "")
+	t.Setenv("OLLAMA_MODELS", "")
+	t.Setenv("CARGO_HOME", filepath.Join(s.HomeDir, ".cargo"))
+	t.Setenv("GEM_HOME", filepath.Join(s.HomeDir, ".gem"))
+
+	for _, f := range fixtures {
+		dir := s.HomeDir
+		if f.sys {
+			dir = s.RootDir
+		}
+		path := filepath.Join(dir, f.path)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, f.size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if f.age > 0 {
+			mtime := time.Now().AddDate(0, 0, -f.age)
+			if err := os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return s
+}
+
+// assertItemSizes checks result lists exactly the items in want, by name
+// and size
+func assertItemSizes(t *testing.T, result *types.ScanResult, want map[string]int64) {
+	t.Helper()
+	got := make(map[string]int64)
+	for _, item := range result.Items {
+		got[item.Name] = item.Size
+	}
+	if len(result.Items) != len(want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+	for name, size := range want {
+		if got[name] != size {
+			t.Errorf("%s = %d, want %d", name, got[name], size)
+		}
+	}
+}
+
+func TestScannersOnFakeHome(t *testing.T) {
+	s := newFakeHomeScanner(t, homeFixtures)
+
+	tests := []struct {
+		category  string
+		scan      func() *types.ScanResult
+		wantItems int
+		wantTotal int64
+	}{
+		{"Cache Files", s.ScanCacheFiles, 2, 4700},
+		{"Log Files", s.ScanLogFiles, 3, 105},
+		{"Trash", s.ScanTrash, 1, 80},
+		{"Old Downloads", s.ScanDownloads, 1, 900},
+		{"Homebrew Cache", s.ScanBrewCache, 1, 700},
+		{"Node Modules", s.ScanNodeModules, 1, 1000},
+		{"Python Artifacts", s.ScanPythonArtifacts, 2, 350},
+		{"Rust Artifacts", s.ScanRustArtifacts, 1, 2000},
+		{"Build Artifacts", s.ScanBuildArtifacts, 1, 500},
+		{"NPM/Yarn/PNPM Caches", s.ScanNpmYarnCaches, 1, 250},
+		{"Go Artifacts", s.ScanGoArtifacts, 0, 0},
+		{"Xcode Files", s.ScanXcodeFiles, 0, 0},
+		{"macOS Installers", s.ScanInstallers, 2, 4200},
+		{"Sound Libraries", s.ScanSoundLibraries, 2, 1000},
+		{"Speech & ML Assets", s.ScanSpeechAssets, 3, 190},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			result := tt.scan()
+
+			if result.Category != tt.category {
+				t.Errorf("category = %q, want %q", result.Category, tt.category)
+			}
+			if len(result.Items) != tt.wantItems {
+				t.Errorf("items = %d, want %d: %+v", len(result.Items), tt.wantItems, result.Items)
+			}
+			if result.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", result.Total, tt.wantTotal)
+			}
+
+			var sum int64
+			for _, item := range result.Items {
+				sum += item.Size
+			}
+			if sum != result.Total {
+				t.Errorf("sum of item sizes = %d, total = %d", sum, result.Total)
+			}
+		})
+	}
+}
+
+func TestCacheFilesLeavesHomebrewToItsCategory(t *testing.T) {
+	s := newFakeHomeScanner(t, homeFixtures)
+	s.brew = func(...string) ([]byte, error) { return nil, errors.New("brew is not installed") }
+
+	scan := func(set string) *types.ScanResult {
+		for _, sc := range s.Scanners(set) {
+			if sc.Name() == "caches" {
+				return sc.Scan(context.Background())
+			}
+		}
+		t.Fatalf("caches doesn't run in %s", set)
+		return nil
+	}
+
+	// Full scans run the homebrew scanner, which cleans the folder with brew
+	if result := scan(SetFull); len(result.Items) != 1 || result.Total != 4000 {
+		t.Errorf("full scan: %d items, %d bytes, want the Homebrew folder left out", len(result.Items), result.Total)
+	}
+	// Quick Clean doesn't, so Cache Files keeps it
+	if result := scan(SetQuick); len(result.Items) != 2 || result.Total != 4700 {
+		t.Errorf("quick scan: %d items, %d bytes, want the Homebrew folder listed", len(result.Items), result.Total)
+	}
+	s.Disabled = map[string]bool{"homebrew": true}
+	if result := scan(SetFull); len(result.Items) != 2 {
+		t.Errorf("full scan without homebrew: %d items, want the Homebrew folder listed", len(result.Items))
+	}
+}
+
+func TestCancelledScanStopsWalking(t *testing.T) {
+	s := newFakeHomeScanner(t, homeFixtures)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.ctx = ctx
+
+	if result := s.ScanNodeModules(); len(result.Items) != 0 {
+		t.Errorf("cancelled scan found %+v", result.Items)
+	}
+	if result := s.ScanCacheFiles(); result.Total != 0 {
+		t.Errorf("cancelled scan sized %d bytes", result.Total)
+	}
+}
+
+func TestScanDownloadsReportsAge(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Downloads/installer.pkg", size: 10, age: 45},
+	})
+
+	result := s.ScanDownloads()
+	if len(result.Items) != 1 {
+		t.Fatalf("items = %d, want 1", len(result.Items))
+	}
+	if age := result.Items[0].Age; age < 44 || age > 45 {
+		t.Errorf("age = %d days, want 45", age)
+	}
+}
+
+func TestScanDeviceSupport(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Developer/Xcode/iOS DeviceSupport/17.2 (21C62)/Symbols/dyld", size: 700, age: 90},
+		{path: "Library/Developer/Xcode/iOS DeviceSupport/iPhone16,1 18.0 (22A3354)/Symbols/dyld", size: 800},
+		{path: "Library/Developer/Xcode/iOS DeviceSupport/.DS_Store", size: 6},
+		{path: "Library/Developer/Xcode/watchOS DeviceSupport/10.2 (21S364)/Symbols/dyld", size: 300},
+	})
+	os.Chtimes(filepath.Join(s.HomeDir, "Library/Developer/Xcode/iOS DeviceSupport/17.2 (21C62)"), time.Now().AddDate(0, 0, -90), time.Now().AddDate(0, 0, -90))
+
+	result := s.ScanDeviceSupport()
+	want := map[string]int64{
+		"📱 iOS 17.2 (21C62)":              700,
+		"📱 iOS iPhone16,1 18.0 (22A3354)": 800,
+		"📱 watchOS 10.2 (21S364)":         300,
+	}
+	if len(result.Items) != len(want) {
+		t.Fatalf("items = %+v, want %d versions", result.Items, len(want))
+	}
+	for _, item := range result.Items {
+		if size, ok := want[item.Name]; !ok || item.Size != size {
+			t.Errorf("unexpected item %s (%d bytes)", item.Name, item.Size)
+		}
+		if item.Name == "📱 iOS 17.2 (21C62)" && item.Age != 90 {
+			t.Errorf("%s age = %d, want 90", item.Name, item.Age)
+		}
+	}
+	if result.Total != 1800 {
+		t.Errorf("total = %d, want 1800", result.Total)
+	}
+}
+
+func TestScanSimulators(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Developer/Xcode/DerivedData/App-abc/Build/app", size: 100},
+		{path: "Library/Developer/CoreSimulator/Devices/AAAA/data/file", size: 400},
+		{path: "Library/Developer/CoreSimulator/Devices/BBBB/data/file", size: 300},
+		{path: "Library/Developer/CoreSimulator/Devices/CCCC/data/file", size: 200},
+		{path: "Library/Developer/CoreSimulator/Devices/DDDD/data/file", size: 250},
+	})
+	var ran []string
+	s.xcrun = func(args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(args, " "))
+		if args[1] == "list" {
+			return []byte(`{"devices": {
+				"com.apple.CoreSimulator.SimRuntime.iOS-17-2": [
+					{"udid": "AAAA", "name": "iPhone 15", "state": "Shutdown", "isAvailable": true},
+					{"udid": "BBBB", "name": "iPhone 15 Pro", "state": "Booted", "isAvailable": true}
+				],
+				"com.apple.CoreSimulator.SimRuntime.iOS-16-4": [
+					{"udid": "CCCC", "name": "iPhone 14", "state": "Shutdown", "isAvailable": false, "availabilityError": "runtime profile not found"},
+					{"udid": "DDDD", "name": "iPad Air", "state": "Shutdown", "isAvailable": false}
+				]
+			}}`), nil
+		}
+		return nil, nil
+	}
+
+	result := s.ScanXcodeFiles()
+	devices := filepath.Join(s.HomeDir, "Library/Developer/CoreSimulator/Devices")
+	want := map[string]string{
+		filepath.Join(s.HomeDir, "Library/Developer/Xcode/DerivedData/App-abc"): "🔨 App (built " + time.Now().Format("2 Jan 2006") + ")",
+		filepath.Join(devices, "AAAA"):                                          "📱 Simulator: iPhone 15 (iOS 17.2)",
+		filepath.Join(devices, "CCCC"):                                          "📱 Simulator: iPhone 14 (iOS 16.4, unavailable)",
+		filepath.Join(devices, "DDDD"):                                          "📱 Simulator: iPad Air (iOS 16.4, unavailable)",
+	}
+	if len(result.Items) != len(want) {
+		t.Fatalf("items = %+v, want %d", result.Items, len(want))
+	}
+	for _, item := range result.Items {
+		if want[item.Path] != item.Name {
+			t.Errorf("item %s named %q, want %q", item.Path, item.Name, want[item.Path])
+		}
+	}
+
+	ran = nil
+	for _, item := range result.Items {
+		if err := result.Remover(item.Path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantRan := []string{"simctl delete unavailable", "simctl delete AAAA"}
+	if strings.Join(ran, ", ") != strings.Join(wantRan, ", ") {
+		t.Errorf("removing ran xcrun %q, want %q", ran, wantRan)
+	}
+	if _, err := os.Stat(filepath.Join(s.HomeDir, "Library/Developer/Xcode/DerivedData/App-abc")); !os.IsNotExist(err) {
+		t.Errorf("DerivedData item was not deleted: %v", err)
+	}
+
+	// Without Xcode the simulator folders are listed and deleted as they are
+	s.xcrun = func(...string) ([]byte, error) { return nil, errors.New("xcrun: not found") }
+	if result := s.ScanXcodeFiles(); len(result.Items) != 4 || result.Remover != nil {
+		t.Errorf("without simctl = %+v", result)
+	}
+}
+
+func TestScanSimulatorRuntimes(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Developer/CoreSimulator/Profiles/Runtimes/iOS 16.4.simruntime/Contents/Info.plist", size: 10},
+		{path: "Library/Developer/CoreSimulator/Profiles/Runtimes/iOS 16.4.simruntime/Contents/Resources/RuntimeRoot/dyld", size: 900},
+		{path: "Library/Developer/CoreSimulator/Profiles/Runtimes/watchOS 10.2.simruntime/Contents/Resources/dyld", size: 400, sys: true},
+	})
+	os.WriteFile(filepath.Join(s.HomeDir, "Library/Developer/CoreSimulator/Profiles/Runtimes/iOS 16.4.simruntime/Contents/Info.plist"),
+		[]byte("<plist><dict><key>CFBundleIdentifier</key><string>com.apple.CoreSimulator.SimRuntime.iOS-16-4</string></dict></plist>"), 0o644)
+	s.xcrun = func(...string) ([]byte, error) {
+		return []byte(`{"devices": {"com.apple.CoreSimulator.SimRuntime.iOS-16-4": [{"udid": "A"}, {"udid": "B"}]}}`), nil
+	}
+
+	result := s.ScanSimulatorRuntimes()
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	want := []string{"🧩 Runtime: iOS 16.4 (used by 2 simulators)", "🧩 Runtime: watchOS 10.2"}
+	if strings.Join(names, ", ") != strings.Join(want, ", ") {
+		t.Errorf("items = %q, want %q", names, want)
+	}
+	if result.Total <= 1300 {
+		t.Errorf("total = %d, want more than 1300", result.Total)
+	}
+}
+
+func TestScanSimulatorCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Developer/CoreSimulator/Caches/dyld/23C71/com.apple.CoreSimulator.SimRuntime.iOS-17-2/dyld_sim_shared_cache_arm64e", size: 3000},
+		{path: "Library/Developer/CoreSimulator/Caches/dyld/23C71/com.apple.CoreSimulator.SimRuntime.watchOS-10-2/dyld_sim_shared_cache_arm64e", size: 1000},
+		{path: "Library/Developer/CoreSimulator/Caches/com.apple.mobileassetd/asset", size: 50},
+	})
+
+	result := s.ScanSimulatorCaches()
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	want := []string{"🧩 com.apple.mobileassetd", "🧩 dyld cache: iOS 17.2 (macOS 23C71)", "🧩 dyld cache: watchOS 10.2 (macOS 23C71)"}
+	if strings.Join(names, ", ") != strings.Join(want, ", ") {
+		t.Errorf("items = %q, want %q", names, want)
+	}
+	if result.Total != 4050 {
+		t.Errorf("total = %d, want 4050", result.Total)
+	}
+}
+
+func TestScanArchives(t *testing.T) {
+	archive := "Library/Developer/Xcode/Archives/2024-03-09/MyApp 09-03-2024, 14.05.xcarchive"
+	s := newFakeHomeScanner(t, []fixture{
+		{path: archive + "/Products/Applications/MyApp.app/MyApp", size: 800},
+		{path: archive + "/Info.plist", size: 0},
+		{path: "Library/Developer/Xcode/Archives/2024-03-10/Widget.xcarchive/dSYMs/Widget.dSYM", size: 200, age: 45},
+		{path: "Library/Developer/Xcode/Archives/2024-03-10/notes.txt", size: 5},
+	})
+	os.WriteFile(filepath.Join(s.HomeDir, archive, "Info.plist"), []byte(`<plist><dict>
+	<key>ApplicationProperties</key>
+	<dict>
+		<key>CFBundleShortVersionString</key>
+		<string>2.1</string>
+	</dict>
+	<key>CreationDate</key>
+	<date>2024-03-09T14:05:00Z</date>
+	<key>Name</key>
+	<string>MyApp</string>
+</dict></plist>`), 0o644)
+	widget := filepath.Join(s.HomeDir, "Library/Developer/Xcode/Archives/2024-03-10/Widget.xcarchive")
+	os.Chtimes(widget, time.Now().AddDate(0, 0, -45), time.Now().AddDate(0, 0, -45))
+
+	result := s.ScanXcodeFiles()
+	if len(result.Items) != 2 {
+		t.Fatalf("items = %+v, want one per archive", result.Items)
+	}
+	created := time.Date(2024, 3, 9, 14, 5, 0, 0, time.UTC)
+	first := result.Items[0]
+	if want := "📦 Archive: MyApp 2.1 (" + created.Local().Format("2 Jan 2006") + ")"; first.Name != want {
+		t.Errorf("name = %q, want %q", first.Name, want)
+	}
+	if want := int(time.Since(created).Hours() / 24); first.Age != want {
+		t.Errorf("age = %d, want %d", first.Age, want)
+	}
+	// Without an Info.plist the bundle name and modification time are used
+	if second := result.Items[1]; !strings.HasPrefix(second.Name, "📦 Archive: Widget (") || second.Age != 45 || second.Size != 200 {
+		t.Errorf("second = %+v", second)
+	}
+}
+
+func TestScanDerivedData(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "code/ios/App.xcodeproj/project.pbxproj", size: 10},
+		{path: "Library/Developer/Xcode/DerivedData/App-bqxkfmrbvhyu/Build/Products/app", size: 700},
+		{path: "Library/Developer/Xcode/DerivedData/Old-cdyaxgqzlphq/Build/Products/app", size: 300},
+		{path: "Library/Developer/Xcode/DerivedData/Old-cdyaxgqzlphq/info.plist", size: 0},
+	})
+	app := filepath.Join(s.HomeDir, "Library/Developer/Xcode/DerivedData/App-bqxkfmrbvhyu")
+	old := filepath.Join(s.HomeDir, "Library/Developer/Xcode/DerivedData/Old-cdyaxgqzlphq")
+	lastBuild := time.Now().AddDate(0, 0, -40).UTC().Truncate(time.Second)
+	os.WriteFile(filepath.Join(old, "info.plist"), []byte(`<plist><dict>
+	<key>LastAccessedDate</key>
+	<date>`+lastBuild.Format(time.RFC3339)+`</date>
+	<key>WorkspacePath</key>
+	<string>`+filepath.Join(s.HomeDir, "code/old/Old.xcodeproj")+`</string>
+</dict></plist>`), 0o644)
+	os.Chtimes(app, time.Now().AddDate(0, 0, -3), time.Now().AddDate(0, 0, -3))
+
+	result := s.ScanXcodeFiles()
+	if len(result.Items) != 2 {
+		t.Fatalf("items = %+v, want one per project", result.Items)
+	}
+	if got := result.Items[0]; got.Path != app || got.Age != 3 || !strings.HasPrefix(got.Name, "🔨 App (built ") {
+		t.Errorf("first = %+v", got)
+	}
+	want := "🔨 code/old/Old.xcodeproj (built " + lastBuild.Local().Format("2 Jan 2006") + ", project deleted)"
+	if got := result.Items[1]; got.Name != want || got.Age != 40 {
+		t.Errorf("second = %q, age %d, want %q, age 40", got.Name, got.Age, want)
+	}
+}
+
+func TestScanXcodeInstalls(t *testing.T) {
+	plist := func(version string) string {
+		return "<plist><dict><key>CFBundleShortVersionString</key>\n\t<string>" + version + "</string></dict></plist>"
+	}
+
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Applications/Xcode.app/Contents/MacOS/Xcode", size: 100, sys: true},
+		{path: "Applications/Xcode-15.2.app/Contents/MacOS/Xcode", size: 200, sys: true},
+		{path: "Applications/Xcode-beta.app/Contents/MacOS/Xcode", size: 300, sys: true},
+		{path: "code/ios/.xcode-version", size: 0},
+	})
+
+	files := map[string]string{
+		filepath.Join(s.RootDir, "Applications/Xcode.app/Contents/version.plist"):      plist("16.0"),
+		filepath.Join(s.RootDir, "Applications/Xcode-15.2.app/Contents/version.plist"): plist("15.2"),
+		filepath.Join(s.HomeDir, "code/ios/.xcode-version"):                            "15.2\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("DEVELOPER_DIR", "")
+	link := filepath.Join(s.RootDir, "var/db/xcode_select_link")
+	if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/Applications/Xcode.app/Contents/Developer", link); err != nil {
+		t.Fatal(err)
+	}
+
+	result := s.ScanXcodeInstalls()
+
+	names := make(map[string]int64)
+	for _, item := range result.Items {
+		names[item.Name] = item.Size
+	}
+	want := map[string]int64{
+		"🛠 Xcode-15.2.app (15.2, required by code/ios)": 200,
+		"🛠 Xcode-beta.app":                              300,
+	}
+	if len(names) != len(want) {
+		t.Fatalf("items = %v, want %v", names, want)
+	}
+	for name, size := range want {
+		if names[name] < size {
+			t.Errorf("item %q size = %d, want at least %d (items %v)", name, names[name], size, names)
+		}
+	}
+
+	listed := func() []string {
+		var apps []string
+		for _, item := range s.ScanXcodeInstalls().Items {
+			apps = append(apps, filepath.Base(item.Path))
+		}
+		sort.Strings(apps)
+		return apps
+	}
+
+	// With only the Command Line Tools selected, or nothing, the tools fall
+	// back to Xcode.app
+	os.Remove(link)
+	if err := os.Symlink("/Library/Developer/CommandLineTools", link); err != nil {
+		t.Fatal(err)
+	}
+	if got := listed(); strings.Join(got, ",") != "Xcode-15.2.app,Xcode-beta.app" {
+		t.Errorf("with the Command Line Tools selected, listed %v", got)
+	}
+	os.Remove(link)
+	if got := listed(); strings.Join(got, ",") != "Xcode-15.2.app,Xcode-beta.app" {
+		t.Errorf("with nothing selected, listed %v", got)
+	}
+
+	t.Setenv("DEVELOPER_DIR", "/Applications/Xcode-beta.app/Contents/Developer")
+	if got := listed(); strings.Join(got, ",") != "Xcode-15.2.app,Xcode.app" {
+		t.Errorf("with DEVELOPER_DIR set, listed %v", got)
+	}
+}
+
+func TestAdvisoryCategories(t *testing.T) {
+	s := newFakeHomeScanner(t, homeFixtures)
+
+	for _, scan := range []func() *types.ScanResult{
+		s.ScanSoundLibraries,
+		s.ScanSpeechAssets,
+	} {
+		result := scan()
+		if !result.Advisory || result.Hint == "" {
+			t.Errorf("%s: advisory = %v, hint = %q, want a report-only category with a hint", result.Category, result.Advisory, result.Hint)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	s := newFakeHomeScanner(t, homeFixtures)
+
+	// Every registration must report results under its own category
+	for _, r := range Registrations() {
+		if got := r.Scan(s).Category; got != r.Category {
+			t.Errorf("scanner %s: result category = %q, registered as %q", r.Name, got, r.Category)
+		}
+		if _, ok := Info(r.Category); !ok {
+			t.Errorf("scanner %s: no info for category %q", r.Name, r.Category)
+		}
+		if CategoryRisk(r.Category) != r.Risk {
+			t.Errorf("CategoryRisk(%q) = %v, want %v", r.Category, CategoryRisk(r.Category), r.Risk)
+		}
+	}
+
+	// Categories with their own strategy are cleaned through it
+	for _, sc := range s.Scanners(SetFull) {
+		if sc.Name() != "downloads" {
+			continue
+		}
+		result := sc.Scan(context.Background())
+		if result.Remover == nil || result.Method != "move to Trash" {
+			t.Errorf("downloads method = %q, want move to Trash", result.Method)
+		}
+	}
+
+	full := len(s.Scanners(SetFull))
+	s.Disabled = map[string]bool{"downloads": true}
+	for _, sc := range s.Scanners(SetFull) {
+		if sc.Name() == "downloads" {
+			t.Error("disabled scanner is still listed")
+		}
+	}
+	if got := len(s.Scanners(SetFull)); got != full-1 {
+		t.Errorf("scanners after disabling one = %d, want %d", got, full-1)
+	}
+}
+
+func TestScanDockerObjects(t *testing.T) {
+	s := newFakeHomeScanner(t, nil)
+	var removed [][]string
+	s.docker = func(args ...string) ([]byte, error) {
+		if args[0] == "system" {
+			return []byte(`{
+				"Images": [
+					{"ID": "sha256:aaaaaaaaaaaaaaaa", "Repository": "nginx", "Tag": "latest", "Size": "190MB", "UniqueSize": "50MB", "CreatedSince": "3 weeks ago", "Containers": "0"},
+					{"ID": "sha256:bbbbbbbbbbbbbbbb", "Repository": "postgres", "Tag": "16", "Size": "400MB", "Containers": "1"}
+				],
+				"Volumes": [
+					{"Name": "pgdata", "Size": "1.5GB", "Links": "1"},
+					{"Name": "old-cache", "Size": "20MB", "Links": "0"}
+				],
+				"BuildCache": [
+					{"ID": "k2j3h4", "CacheType": "regular", "Size": "5MB", "LastUsedSince": "2 days ago", "InUse": false},
+					{"ID": "z9y8x7", "CacheType": "regular", "Size": "9MB", "InUse": true}
+				]
+			}`), nil
+		}
+		removed = append(removed, args)
+		return nil, nil
+	}
+
+	result := s.ScanDockerArtifacts()
+	want := map[string]int{
+		"docker://image/sha256:aaaaaaaaaaaaaaaa": 21,
+		"docker://volume/old-cache":              0,
+		"docker://build-cache/k2j3h4":            2,
+	}
+	if len(result.Items) != len(want) {
+		t.Fatalf("items = %+v, want %d unused objects", result.Items, len(want))
+	}
+	for _, item := range result.Items {
+		age, ok := want[item.Path]
+		if !ok {
+			t.Errorf("unexpected item %s", item.Path)
+		} else if item.Age != age {
+			t.Errorf("%s age = %d, want %d", item.Path, item.Age, age)
+		}
+	}
+	if result.Total != 75_000_000 {
+		t.Errorf("total = %d, want 75000000", result.Total)
+	}
+
+	for _, item := range result.Items {
+		if err := result.Remover(item.Path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantArgs := []string{"image rm sha256:aaaaaaaaaaaaaaaa", "volume rm old-cache", "builder prune --force --filter id=k2j3h4"}
+	for i, args := range removed {
+		if got := strings.Join(args, " "); got != wantArgs[i] {
+			t.Errorf("remove %d ran docker %s, want %s", i, got, wantArgs[i])
+		}
+	}
+}
+
+func TestScanDockerDesktopDataIsReportOnly(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Containers/com.docker.docker/Data/vms/0/data/Docker.raw", size: 0},
+	})
+	disk := filepath.Join(s.HomeDir, "Library/Containers/com.docker.docker/Data/vms/0/data/Docker.raw")
+	if err := os.Truncate(disk, 200*1024*1024); err != nil {
+		t.Fatal(err)
+	}
+	s.dockerAPI = func(string, string) ([]byte, error) { return nil, errors.New("no daemon") }
+	s.docker = func(...string) ([]byte, error) { return nil, errors.New("no daemon") }
+
+	var result *types.ScanResult
+	for _, sc := range s.Scanners(SetDev) {
+		if sc.Name() == "docker" {
+			result = sc.Scan(context.Background())
+		}
+	}
+	if result == nil || len(result.Items) != 1 {
+		t.Fatalf("result = %+v, want the Desktop data directory", result)
+	}
+	// Nothing in it was listed, so nothing may be pruned for it
+	if !result.Advisory || result.Remover != nil {
+		t.Errorf("advisory = %v, remover set = %v, want report-only", result.Advisory, result.Remover != nil)
+	}
+}
+
+func TestScanDockerAPI(t *testing.T) {
+	s := newFakeHomeScanner(t, nil)
+	var calls []string
+	s.dockerAPI = func(method, path string) ([]byte, error) {
+		if path == "/system/df" {
+			return []byte(`{
+				"Images": [
+					{"Id": "sha256:aaaaaaaaaaaaaaaa", "RepoTags": ["nginx:latest"], "Created": 1, "Size": 190000000, "SharedSize": 140000000, "Containers": 0},
+					{"Id": "sha256:cccccccccccccccc", "RepoTags": ["<none>:<none>"], "Size": 30000000, "SharedSize": -1, "Containers": 0},
+					{"Id": "sha256:bbbbbbbbbbbbbbbb", "RepoTags": ["postgres:16"], "Size": 400000000, "Containers": 1}
+				],
+				"Containers": [
+					{"Id": "0123456789abcdef", "Names": ["/old-web"], "Image": "nginx", "State": "exited", "SizeRw": 2000},
+					{"Id": "fedcba9876543210", "Names": ["/db"], "Image": "postgres:16", "State": "running", "SizeRw": 9000}
+				],
+				"Volumes": [
+					{"Name": "pgdata", "UsageData": {"Size": 1500000000, "RefCount": 1}},
+					{"Name": "old-cache", "UsageData": {"Size": 20000000, "RefCount": 0}}
+				],
+				"BuildCache": [
+					{"ID": "k2j3h4", "Type": "regular", "Size": 5000000, "InUse": false, "LastUsedAt": "2020-01-01T00:00:00Z"},
+					{"ID": "z9y8x7", "Type": "regular", "Size": 9000000, "InUse": true}
+				]
+			}`), nil
+		}
+		calls = append(calls, method+" "+path)
+		return nil, nil
+	}
+
+	result := s.ScanDockerArtifacts()
+	want := map[string]string{
+		"docker://image/sha256:aaaaaaaaaaaaaaaa": "🐳 Image: nginx:latest",
+		"docker://image/sha256:cccccccccccccccc": "🐳 Dangling image: cccccccccccc",
+		"docker://container/0123456789abcdef":    "🐳 Container: old-web (exited, nginx)",
+		"docker://volume/old-cache":              "🐳 Volume: old-cache",
+		"docker://build-cache/k2j3h4":            "🐳 Build cache: regular k2j3h4",
+	}
+	if len(result.Items) != len(want) {
+		t.Fatalf("items = %+v, want %d unused objects", result.Items, len(want))
+	}
+	for _, item := range result.Items {
+		if name, ok := want[item.Path]; !ok || item.Name != name {
+			t.Errorf("item %s named %q, want %q", item.Path, item.Name, name)
+		}
+	}
+	if result.Total != 50_000_000+30_000_000+2000+20_000_000+5_000_000 {
+		t.Errorf("total = %d", result.Total)
+	}
+
+	for _, item := range result.Items {
+		if err := result.Remover(item.Path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantCalls := []string{
+		"DELETE /images/sha256:aaaaaaaaaaaaaaaa",
+		"DELETE /images/sha256:cccccccccccccccc",
+		"DELETE /containers/0123456789abcdef",
+		"DELETE /volumes/old-cache",
+		`POST /build/prune?filters=%7B%22id%22%3A%5B%22k2j3h4%22%5D%7D`,
+	}
+	if got := strings.Join(calls, "\n"); got != strings.Join(wantCalls, "\n") {
+		t.Errorf("removing called\n%s\nwant\n%s", got, strings.Join(wantCalls, "\n"))
+	}
+}
+
+func TestScanLocalKubernetes(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".minikube/machines/minikube/disk.rawdisk", size: 900},
+		{path: ".minikube/machines/busy/disk.qcow2", size: 500},
+		{path: ".minikube/machines/busy/qemu.pid", size: 4},
+		{path: ".minikube/machines/server.pem", size: 10},
+		{path: ".minikube/cache/iso/arm64/minikube-v1.32.1-arm64.iso", size: 300},
+		{path: ".minikube/cache/preloaded-tarball/preloaded-images-k8s-v18-v1.28.3-docker-overlay2-arm64.tar.lz4", size: 400},
+		{path: ".minikube/cache/linux/arm64/v1.28.3/kubectl", size: 50},
+	})
+	var removed []string
+	s.dockerAPI = func(method, path string) ([]byte, error) {
+		if path == "/system/df" {
+			return []byte(`{"Images": [
+				{"Id": "sha256:kkkkkkkkkkkkkkkk", "RepoTags": ["kindest/node:v1.29.2"], "Size": 1000, "Containers": 0},
+				{"Id": "sha256:llllllllllllllll", "RepoTags": ["rancher/k3s:v1.27.4-k3s1"], "Size": 800, "Containers": 1},
+				{"Id": "sha256:aaaaaaaaaaaaaaaa", "RepoTags": ["nginx:latest"], "Size": 190, "Containers": 0}
+			]}`), nil
+		}
+		removed = append(removed, method+" "+path)
+		return nil, nil
+	}
+
+	result := s.ScanLocalKubernetes()
+	want := map[string]int64{
+		"☸️ minikube machine: minikube":                                                                900,
+		"☸️ minikube ISO: minikube-v1.32.1-arm64.iso":                                                  300,
+		"☸️ minikube preloaded images: preloaded-images-k8s-v18-v1.28.3-docker-overlay2-arm64.tar.lz4": 400,
+		"☸️ minikube cache: linux":                                                                     50,
+		"☸️ kind node image: v1.29.2":                                                                  1000,
+	}
+	assertItemSizes(t, result, want)
+
+	if err := result.Remover("docker://image/sha256:kkkkkkkkkkkkkkkk"); err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0] != "DELETE /images/sha256:kkkkkkkkkkkkkkkk" {
+		t.Errorf("removing the node image called %q", removed)
+	}
+	iso := filepath.Join(s.HomeDir, ".minikube", "cache", "iso", "arm64", "minikube-v1.32.1-arm64.iso")
+	if err := result.Remover(iso); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(iso); !os.IsNotExist(err) {
+		t.Errorf("ISO still there: %v", err)
+	}
+
+	// Docker Artifacts leaves node images to this category
+	for _, item := range s.ScanDockerArtifacts().Items {
+		if strings.Contains(item.Name, "node image") {
+			t.Errorf("Docker Artifacts lists %s", item.Name)
+		}
+	}
+}
+
+func TestScanKubernetesTooling(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".kube/config", size: 5},
+		{path: ".kube/cache/discovery/127.0.0.1_6443/v1/serverresources.json", size: 300},
+		{path: ".kube/cache/http/abc", size: 200},
+		{path: ".kube/http-cache/def", size: 100},
+		{path: "Library/Caches/helm/repository/bitnami-index.yaml", size: 900},
+		{path: "Library/Caches/helm/repository/bitnami-charts.txt", size: 40},
+		{path: "Library/Caches/helm/repository/postgresql-13.2.24.tgz", size: 60},
+		{path: "Library/Caches/helm/content/blob", size: 70},
+	})
+
+	result := s.ScanKubernetesTooling()
+	want := map[string]int64{
+		"☸️ kubectl: API discovery cache":        300,
+		"☸️ kubectl: HTTP cache":                 200,
+		"☸️ kubectl: HTTP cache (old kubectl)":   100,
+		"☸️ Helm repository index: bitnami":      900,
+		"☸️ Helm repository chart list: bitnami": 40,
+		"☸️ Helm chart: postgresql-13.2.24":      60,
+		"☸️ Helm cache: content":                 70,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanTerraformArtifacts(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".terraform.d/plugin-cache/registry.terraform.io/hashicorp/aws/5.31.0/darwin_arm64/terraform-provider-aws", size: 900},
+		{path: ".terraform.d/plugin-cache/registry.terraform.io/hashicorp/aws/5.20.0/darwin_arm64/terraform-provider-aws", size: 800},
+		{path: "infra/prod/main.tf", size: 10},
+		{path: "infra/prod/.terraform/providers/registry.terraform.io/hashicorp/aws/5.31.0/darwin_arm64/terraform-provider-aws", size: 700},
+		{path: "infra/prod/.terraform/modules/vpc/main.tf", size: 20},
+		{path: "infra/json/stack.tf.json", size: 10},
+		{path: "infra/json/.terraform/providers/p", size: 300},
+		// Without .tf files next to it, a .terraform folder is left alone
+		{path: "notes/.terraform/keep", size: 50},
+	})
+
+	result := s.ScanTerraformArtifacts()
+	want := map[string]int64{
+		"🟪 Provider cache: hashicorp/aws 5.31.0": 900,
+		"🟪 Provider cache: hashicorp/aws 5.20.0": 800,
+		"🟪 infra/prod (.terraform)":              720,
+		"🟪 infra/json (.terraform)":              300,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanVagrantBoxes(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".vagrant.d/boxes/hashicorp-VAGRANTSLASH-bionic64/1.0.282/virtualbox/metadata.json", size: 10},
+		{path: ".vagrant.d/boxes/hashicorp-VAGRANTSLASH-bionic64/1.0.282/virtualbox/box-disk1.vmdk", size: 900},
+		{path: ".vagrant.d/boxes/hashicorp-VAGRANTSLASH-bionic64/metadata_url", size: 40},
+		{path: ".vagrant.d/boxes/bento-VAGRANTSLASH-ubuntu-22.04/202401.31.0/arm64/parallels/metadata.json", size: 10},
+		{path: ".vagrant.d/boxes/bento-VAGRANTSLASH-ubuntu-22.04/202401.31.0/arm64/parallels/box.pvm/disk.hdd", size: 600},
+		{path: ".vagrant.d/boxes/local/0/vmware_desktop/metadata.json", size: 10},
+		{path: ".vagrant.d/boxes/local/0/vmware_desktop/disk.vmdk", size: 300},
+	})
+	var ran []string
+	s.vagrant = func(args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(args, " "))
+		return nil, nil
+	}
+
+	result := s.ScanVagrantBoxes()
+	got := make(map[string]string)
+	for _, item := range result.Items {
+		got[item.Name] = item.Path
+	}
+	boxes := filepath.Join(s.HomeDir, ".vagrant.d", "boxes")
+	want := map[string]string{
+		"📦 Vagrant box: hashicorp/bionic64 1.0.282 (virtualbox)":           filepath.Join(boxes, "hashicorp-VAGRANTSLASH-bionic64", "1.0.282", "virtualbox"),
+		"📦 Vagrant box: bento/ubuntu-22.04 202401.31.0 (parallels, arm64)": filepath.Join(boxes, "bento-VAGRANTSLASH-ubuntu-22.04", "202401.31.0", "arm64", "parallels"),
+		"📦 Vagrant box: local 0 (vmware_desktop)":                          filepath.Join(boxes, "local", "0", "vmware_desktop"),
+	}
+	if len(result.Items) != len(want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+	for name, path := range want {
+		if got[name] != path {
+			t.Errorf("%s at %s, want %s", name, got[name], path)
+		}
+	}
+	if result.Total != 910+610+310 {
+		t.Errorf("total = %d", result.Total)
+	}
+
+	for _, name := range []string{"📦 Vagrant box: hashicorp/bionic64 1.0.282 (virtualbox)", "📦 Vagrant box: bento/ubuntu-22.04 202401.31.0 (parallels, arm64)"} {
+		if err := result.Remover(want[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantRan := []string{
+		"box remove hashicorp/bionic64 --box-version 1.0.282 --provider virtualbox",
+		"box remove bento/ubuntu-22.04 --box-version 202401.31.0 --provider parallels --architecture arm64",
+	}
+	if strings.Join(ran, "\n") != strings.Join(wantRan, "\n") {
+		t.Errorf("ran vagrant %q, want %q", ran, wantRan)
+	}
+
+	// Without vagrant the box folder is deleted
+	s.vagrant = func(...string) ([]byte, error) { return nil, errNoVagrant }
+	local := want["📦 Vagrant box: local 0 (vmware_desktop)"]
+	if err := result.Remover(local); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(local); !os.IsNotExist(err) {
+		t.Errorf("box still there: %v", err)
+	}
+}
+
+func TestCallDockerAPI(t *testing.T) {
+	dir, err := os.MkdirTemp("", "dock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "docker.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip("unix sockets unavailable:", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"message": "image is being used by stopped container 0123"}`))
+			return
+		}
+		w.Write([]byte(`{"Images": []}`))
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	s := newFakeHomeScanner(t, nil)
+	s.dockerAPI = nil
+	t.Setenv("DOCKER_HOST", "unix://"+sock)
+	if out, err := s.callDockerAPI(http.MethodGet, "/system/df"); err != nil || string(out) != `{"Images": []}` {
+		t.Errorf("GET = %q, %v", out, err)
+	}
+	_, err = s.callDockerAPI(http.MethodDelete, "/images/sha256:aa")
+	if err == nil || !strings.Contains(err.Error(), "being used by stopped container") {
+		t.Errorf("DELETE = %v, want the daemon's message", err)
+	}
+}
+
+func TestScanVirtualMachines(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Containers/com.utmapp.UTM/Data/Documents/Ubuntu.utm/Data/disk.qcow2", size: 900},
+		{path: "Library/Containers/com.utmapp.UTM/Data/Documents/notes.txt", size: 5},
+		{path: "Parallels/Windows 11.pvm/harddisk.hdd/data.hds", size: 700},
+		{path: "Virtual Machines.localized/Debian.vmwarevm/Debian.vmdk", size: 500},
+	})
+
+	result := s.ScanVirtualMachines()
+	if !result.Advisory {
+		t.Error("virtual machines are offered for deletion")
+	}
+	want := map[string]int64{
+		"🖥️ UTM: Ubuntu (manual review required)":           900,
+		"🖥️ Parallels: Windows 11 (manual review required)": 700,
+		"🖥️ VMware: Debian (manual review required)":        500,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanTimeMachineSnapshots(t *testing.T) {
+	s := newFakeHomeScanner(t, nil)
+	var ran []string
+	s.tmutil = func(args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(args, " "))
+		if args[0] == "listlocalsnapshots" {
+			return []byte(`Snapshots for disk /:
+com.apple.TimeMachine.2025-03-11-213000.local
+com.apple.TimeMachine.2025-03-12-093000.local
+com.apple.os.update-ABCDEF
+`), nil
+		}
+		return nil, nil
+	}
+	s.purgeable = func() (int64, error) { return 9_000_000_000, nil }
+
+	result := s.ScanTimeMachineSnapshots()
+	if len(result.Items) != 2 {
+		t.Fatalf("items = %+v, want the 2 Time Machine snapshots", result.Items)
+	}
+	if result.Items[0].Path != "tmsnapshot://2025-03-11-213000" || result.Items[0].Size != 4_500_000_000 {
+		t.Errorf("first item = %+v", result.Items[0])
+	}
+	if result.Total != 9_000_000_000 {
+		t.Errorf("total = %d, want 9000000000", result.Total)
+	}
+
+	if err := result.Remover(result.Items[1].Path); err != nil {
+		t.Fatal(err)
+	}
+	if got := ran[len(ran)-1]; got != "deletelocalsnapshots 2025-03-12-093000" {
+		t.Errorf("remove ran tmutil %s", got)
+	}
+	if err := result.Remover("tmsnapshot://latest"); err == nil {
+		t.Error("Remover accepted a path without a snapshot date")
+	}
+
+	// Without purgeable space the snapshots are still listed
+	s.purgeable = func() (int64, error) { return 0, errors.New("osascript: not found") }
+	if result := s.ScanTimeMachineSnapshots(); len(result.Items) != 2 || result.Total != 0 {
+		t.Errorf("without purgeable space = %+v", result)
+	}
+}
+
+func TestScanAndroidSDK(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Android/sdk/system-images/android-34/google_apis/arm64-v8a/system.img", size: 4000},
+		{path: "Library/Android/sdk/system-images/android-30/default/x86_64/system.img", size: 3000},
+		{path: "Library/Android/sdk/platforms/android-9/android.jar", size: 100},
+		{path: "Library/Android/sdk/platforms/android-33/android.jar", size: 200},
+		{path: "Library/Android/sdk/platforms/android-34/android.jar", size: 300},
+		{path: "Library/Android/sdk/build-tools/9.0.0/aapt", size: 10},
+		{path: "Library/Android/sdk/build-tools/34.0.0/aapt", size: 20},
+		{path: "Library/Android/sdk/build-tools/33.0.2/aapt", size: 30},
+		{path: "Library/Android/sdk/ndk/25.2.9519653/toolchains/clang", size: 700},
+		{path: "Library/Android/sdk/ndk/26.1.10909125/toolchains/clang", size: 800},
+		{path: "Library/Android/sdk/ndk/21.4.7075529/toolchains/clang", size: 600},
+		{path: "Library/Android/sdk/ndk-bundle/source.properties", size: 0},
+		{path: "Library/Android/sdk/ndk-bundle/toolchains/clang", size: 500},
+		{path: ".android/avd/Pixel_7.avd/userdata.img", size: 500},
+		{path: ".android/avd/Pixel_7.avd/config.ini", size: 0},
+		{path: ".android/avd/Pixel_7.ini", size: 5},
+	})
+	os.WriteFile(filepath.Join(s.HomeDir, "Library/Android/sdk/ndk-bundle/source.properties"),
+		[]byte("Pkg.Desc = Android NDK\nPkg.Revision = 20.1.5948944\n"), 0o644)
+	os.WriteFile(filepath.Join(s.HomeDir, ".android/avd/Pixel_7.avd/config.ini"),
+		[]byte("hw.lcd.density=420\nimage.sysdir.1=system-images/android-34/google_apis/arm64-v8a/\n"), 0o644)
+
+	result := s.ScanAndroidSDK()
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	want := []string{
+		"🤖 Emulator: Pixel_7 (API 34)",
+		"🤖 System image: API 30 default x86_64",
+		"🤖 System image: API 34 google_apis arm64-v8a (used by Pixel_7)",
+		"🤖 Platform: API 9",
+		"🤖 Platform: API 33",
+		"🤖 Build tools 9.0.0",
+		"🤖 Build tools 33.0.2",
+		"🤖 NDK 21.4.7075529",
+		"🤖 NDK 25.2.9519653",
+		"🤖 NDK 20.1.5948944 (ndk-bundle)",
+	}
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Errorf("items:\n%s\nwant:\n%s", strings.Join(names, "\n"), strings.Join(want, "\n"))
+	}
+
+	// ANDROID_HOME points elsewhere
+	t.Setenv("ANDROID_HOME", filepath.Join(s.HomeDir, "sdk"))
+	if result := s.ScanAndroidSDK(); len(result.Items) != 1 {
+		t.Errorf("with ANDROID_HOME = %+v, want only the emulator", result.Items)
+	}
+}
+
+func TestScanGradle(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".gradle/caches/modules-2/files-2.1/lib.jar", size: 900},
+		{path: ".gradle/caches/build-cache-1/abc", size: 300},
+		{path: ".gradle/caches/8.5/transforms/x", size: 200},
+		{path: ".gradle/wrapper/dists/gradle-8.5-bin/1a2b3c/gradle-8.5/lib/core.jar", size: 1500},
+		{path: ".gradle/wrapper/dists/gradle-7.6-all/4d5e6f/gradle-7.6.zip", size: 1700},
+		{path: ".gradle/daemon/8.5/daemon-1234.out.log", size: 40, age: 12},
+		{path: ".gradle/daemon/8.5/registry.bin", size: 5},
+	})
+
+	result := s.ScanJavaArtifacts()
+	want := map[string]int64{
+		"Gradle: build cache (build-cache-1)":        300,
+		"Gradle: caches for Gradle 8.5":              200,
+		"Gradle: dependency cache":                   900,
+		"Gradle: wrapper 7.6 (all)":                  1700,
+		"Gradle: wrapper 8.5 (bin)":                  1500,
+		"Gradle: daemon log 8.5/daemon-1234.out.log": 40,
+	}
+	assertItemSizes(t, result, want)
+	if result.Total != 4640 {
+		t.Errorf("total = %d, want 4640", result.Total)
+	}
+}
+
+func TestScanUnityArtifacts(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "games/kart/Assets/Scenes/main.unity", size: 10},
+		{path: "games/kart/ProjectSettings/ProjectVersion.txt", size: 5},
+		{path: "games/kart/Library/ArtifactDB", size: 900},
+		{path: "games/kart/Temp/lock", size: 30},
+		{path: "games/kart/Logs/shadercompiler.log", size: 70},
+		// Not a Unity project: no ProjectSettings
+		{path: "notes/Assets/todo.txt", size: 1},
+		{path: "notes/Library/index", size: 50},
+	})
+
+	result := s.ScanUnityArtifacts()
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	want := []string{"🎮 games/kart (Library)", "🎮 games/kart (Temp)", "🎮 games/kart (Logs)"}
+	if strings.Join(names, ", ") != strings.Join(want, ", ") {
+		t.Errorf("items = %q, want %q", names, want)
+	}
+	if result.Total != 1000 {
+		t.Errorf("total = %d, want 1000", result.Total)
+	}
+}
+
+func TestScanDotnetArtifacts(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".nuget/packages/newtonsoft.json/13.0.3/lib.dll", size: 700},
+		{path: ".local/share/NuGet/http-cache/abc.dat", size: 200},
+		{path: "code/api/Api.csproj", size: 5},
+		{path: "code/api/Api.Tests.csproj", size: 5},
+		{path: "code/api/bin/Debug/net8.0/Api.dll", size: 400},
+		{path: "code/api/obj/project.assets.json", size: 60},
+		// A bin folder without a project is left alone
+		{path: "code/scripts/bin/run.sh", size: 10},
+	})
+
+	result := s.ScanDotnetArtifacts()
+	want := map[string]int64{
+		"NuGet: packages":   700,
+		"NuGet: HTTP cache": 200,
+		"🟣 code/api (bin)":  400,
+		"🟣 code/api (obj)":  60,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanLimaVMs(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".lima/default/diffdisk", size: 900},
+		{path: ".lima/default/lima.yaml", size: 10},
+		{path: ".lima/dev/diffdisk", size: 500},
+		{path: ".lima/dev/ha.pid", size: 4},
+		{path: ".lima/_config/user", size: 50},
+		{path: ".lima/_disks/data/datadisk", size: 300},
+		{path: ".colima/default/colima.yaml", size: 10},
+		{path: ".colima/_lima/colima/diffdisk", size: 800},
+		{path: ".colima/_lima/colima-work/diffdisk", size: 600},
+		{path: ".colima/_lima/_disks/colima/datadisk", size: 400},
+		{path: ".colima/_lima/_disks/colima/in_use_by", size: 1},
+		{path: "Library/Caches/lima/download/by-url-sha256/ab/data", size: 200},
+	})
+
+	result := s.ScanLimaVMs()
+	want := map[string]int64{
+		"🖥️ Lima: default":           910,
+		"🖥️ Lima disk: data":         300,
+		"🖥️ Colima: default":         800,
+		"🖥️ Colima: work":            600,
+		"🖥️ Lima: downloaded images": 200,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanRustToolchains(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".rustup/toolchains/stable-aarch64-apple-darwin/bin/rustc", size: 900},
+		{path: ".rustup/toolchains/nightly-2024-01-01-aarch64-apple-darwin/bin/rustc", size: 800},
+		{path: ".rustup/toolchains/1.70.0-aarch64-apple-darwin/bin/rustc", size: 700},
+		{path: ".rustup/downloads/partial.tar.xz", size: 60},
+	})
+	settings := "default_host_triple = \"aarch64-apple-darwin\"\ndefault_toolchain = \"stable\"\nversion = \"12\"\n\n[overrides]\n\"/Users/dev/legacy\" = \"1.70.0-aarch64-apple-darwin\"\n"
+	if err := os.WriteFile(filepath.Join(s.HomeDir, ".rustup", "settings.toml"), []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var ran []string
+	s.rustup = func(args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(args, " "))
+		return nil, nil
+	}
+
+	result := s.ScanRustToolchains()
+	want := map[string]int64{
+		"🦀 Toolchain: nightly-2024-01-01-aarch64-apple-darwin":                      800,
+		"🦀 Toolchain: 1.70.0-aarch64-apple-darwin (override for /Users/dev/legacy)": 700,
+		"🦀 rustup downloads": 60,
+	}
+	assertItemSizes(t, result, want)
+
+	nightly := filepath.Join(s.HomeDir, ".rustup", "toolchains", "nightly-2024-01-01-aarch64-apple-darwin")
+	if err := result.Remover(nightly); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 1 || ran[0] != "toolchain uninstall nightly-2024-01-01-aarch64-apple-darwin" {
+		t.Errorf("ran rustup %q", ran)
+	}
+	downloads := filepath.Join(s.HomeDir, ".rustup", "downloads")
+	if err := result.Remover(downloads); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(downloads); !os.IsNotExist(err) || len(ran) != 1 {
+		t.Errorf("downloads not deleted directly: %v, ran %q", err, ran)
+	}
+}
+
+func TestScanCondaEnvironments(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "miniconda3/condabin/conda", size: 100},
+		{path: "miniconda3/conda-meta/history", size: 10},
+		{path: "miniconda3/envs/old/conda-meta/history", size: 10, age: 90},
+		{path: "miniconda3/envs/old/bin/python", size: 900, age: 40},
+		{path: "miniconda3/envs/current/conda-meta/history", size: 10},
+		{path: "miniconda3/envs/current/bin/python", size: 800},
+		{path: "code/ml/.env/conda-meta/history", size: 10},
+		{path: "code/ml/.env/lib/libtorch.dylib", size: 700},
+		{path: "envs/listed/conda-meta/history", size: 10, age: 3},
+		{path: "envs/listed/lib/libpython.dylib", size: 600, age: 3},
+		{path: ".conda/environments.txt", size: 0},
+	})
+	listed := filepath.Join(s.HomeDir, "envs", "listed")
+	project := filepath.Join(s.HomeDir, "code", "ml", ".env")
+	if err := os.WriteFile(filepath.Join(s.HomeDir, ".conda", "environments.txt"), []byte(project+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONDA_PREFIX", filepath.Join(s.HomeDir, "miniconda3", "envs", "current"))
+	var ran []string
+	s.conda = func(args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(args, " "))
+		if args[0] == "env" {
+			return []byte(`{"envs": ["` + filepath.Join(s.HomeDir, "miniconda3") + `", "` + listed + `"]}`), nil
+		}
+		return nil, nil
+	}
+
+	result := s.ScanCondaEnvironments()
+	want := map[string]int64{
+		"🐍 Conda env: old (last used 40 days ago)":   910,
+		"🐍 Conda env: .env (last used today)":        710,
+		"🐍 Conda env: listed (last used 3 days ago)": 610,
+	}
+	assertItemSizes(t, result, want)
+
+	if err := result.Remover(listed); err != nil {
+		t.Fatal(err)
+	}
+	if want := "remove --all --prefix " + listed + " --yes"; ran[len(ran)-1] != want {
+		t.Errorf("ran conda %q, want %q", ran, want)
+	}
+}
+
+func TestScanMLModelCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".cache/huggingface/hub/models--openai--whisper-large-v3/blobs/abc", size: 900},
+		{path: ".cache/huggingface/hub/datasets--squad/blobs/def", size: 800},
+		{path: ".cache/huggingface/hub/.locks/models--openai--whisper-large-v3/abc.lock", size: 1},
+		{path: ".cache/huggingface/hub/version.txt", size: 1},
+		{path: ".cache/huggingface/token", size: 30},
+		{path: ".cache/huggingface/accelerate/default_config.yaml", size: 40},
+		{path: ".cache/huggingface/xet/chunk-cache/x", size: 700},
+		{path: ".cache/torch/hub/checkpoints/resnet50-0676ba61.pth", size: 600},
+		{path: ".cache/torch/hub/pytorch_vision_main/hubconf.py", size: 500},
+		{path: ".keras/models/vgg16_weights.h5", size: 400},
+		{path: ".keras/datasets/mnist.npz", size: 300},
+		{path: ".keras/keras.json", size: 20},
+		{path: ".cache/whisper/base.en.pt", size: 200},
+	})
+
+	result := s.ScanMLModelCaches()
+	want := map[string]int64{
+		"🤗 Hugging Face model: openai/whisper-large-v3": 900,
+		"🤗 Hugging Face dataset: squad":                 800,
+		"🤗 Hugging Face xet cache":                      700,
+		"🔥 PyTorch checkpoint: resnet50-0676ba61.pth":   600,
+		"🔥 PyTorch Hub: pytorch_vision_main":            500,
+		"🧠 Keras model: vgg16_weights.h5":               400,
+		"🧠 Keras dataset: mnist.npz":                    300,
+		"🎙️ Whisper model: base.en":                     200,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanOllamaModels(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".ollama/models/blobs/sha256-aaa", size: 900},
+		{path: ".ollama/models/blobs/sha256-bbb", size: 800},
+		{path: ".ollama/models/blobs/sha256-ccc", size: 10},
+		{path: ".ollama/models/blobs/sha256-ddd", size: 700},
+		{path: ".ollama/models/blobs/sha256-eee-partial-0", size: 600},
+		{path: ".ollama/models/manifests/registry.ollama.ai/library/llama3/latest", size: 0},
+		{path: ".ollama/models/manifests/registry.ollama.ai/library/llama3/8b", size: 0},
+		{path: ".ollama/models/manifests/registry.ollama.ai/jmorgan/phi/q4", size: 0},
+	})
+	manifests := filepath.Join(s.HomeDir, ".ollama", "models", "manifests", "registry.ollama.ai")
+	write := func(rel string, digests ...string) {
+		var layers []string
+		for _, d := range digests[1:] {
+			layers = append(layers, `{"digest": "sha256:`+d+`"}`)
+		}
+		data := `{"config": {"digest": "sha256:` + digests[0] + `"}, "layers": [` + strings.Join(layers, ", ") + `]}`
+		if err := os.WriteFile(filepath.Join(manifests, rel), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// llama3:8b is another tag of llama3:latest, and phi shares its config
+	write("library/llama3/latest", "ccc", "aaa")
+	write("library/llama3/8b", "ccc", "aaa")
+	write("jmorgan/phi/q4", "ccc", "ddd", "bbb")
+
+	var ran []string
+	s.ollama = func(args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(args, " "))
+		return nil, errors.New("ollama rm: exit status 1: Error: could not connect to ollama app, is it running?")
+	}
+
+	result := s.ScanOllamaModels()
+	want := map[string]int64{
+		"🦙 Ollama: llama3:8b, llama3:latest": 900,
+		"🦙 Ollama: jmorgan/phi:q4":           1500,
+		"🦙 Ollama: unfinished download":      600,
+	}
+	assertItemSizes(t, result, want)
+
+	// With the server stopped, the model's files are deleted directly
+	phi := filepath.Join(manifests, "jmorgan", "phi", "q4")
+	if err := result.Remover(phi); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 1 || ran[0] != "rm jmorgan/phi:q4" {
+		t.Errorf("ran ollama %q", ran)
+	}
+	for _, path := range []string{phi, filepath.Join(s.HomeDir, ".ollama", "models", "blobs", "sha256-ddd")} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s not deleted: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(s.HomeDir, ".ollama", "models", "blobs", "sha256-ccc")); err != nil {
+		t.Errorf("blob shared with another model deleted: %v", err)
+	}
+}
+
+func TestScanCocoaPodsProjects(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Caches/CocoaPods/Pods/Release/Alamofire/a.swift", size: 100},
+		{path: "code/app/Podfile", size: 1},
+		{path: "code/app/Pods/Alamofire/Source/Session.swift", size: 900},
+		{path: "code/rn/ios/Podfile", size: 1},
+		{path: "code/rn/ios/Pods/React-Core/React.m", size: 800},
+		{path: "code/rn/node_modules/dep/ios/Pods/x.m", size: 50},
+		{path: "code/notes/Pods/readme.txt", size: 700},
+	})
+
+	result := s.ScanCocoaPods()
+	want := map[string]int64{
+		"CocoaPods cache":      100,
+		"📱 code/app (Pods)":    900,
+		"📱 code/rn/ios (Pods)": 800,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanCarthageArtifacts(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Caches/org.carthage.CarthageKit/dependencies/Alamofire/objects/pack", size: 100},
+		{path: "code/app/Cartfile", size: 1},
+		{path: "code/app/Carthage/Build/iOS/Alamofire.framework/Alamofire", size: 900},
+		{path: "code/app/Carthage/Checkouts/Alamofire/Source/Session.swift", size: 800},
+		{path: "code/old/Cartfile.resolved", size: 1},
+		{path: "code/old/Carthage/Build/Mac/Nimble.framework/Nimble", size: 700},
+		{path: "code/notes/Carthage/Build/readme.txt", size: 600},
+	})
+
+	result := s.ScanCarthageArtifacts()
+	want := map[string]int64{
+		"Carthage cache":                  100,
+		"📱 code/app (Carthage/Build)":     900,
+		"📱 code/app (Carthage/Checkouts)": 800,
+		"📱 code/old (Carthage/Build)":     700,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanMailAttachments(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Containers/com.apple.mail/Data/Library/Mail Downloads/5A1B/report.pdf", size: 100},
+		{path: "Library/Mail/V10/1A2B3C4D-0000-1111-2222-333344445555/INBOX.mbox/9F8E/Attachments/1201/2/photo.jpg", size: 900},
+		{path: "Library/Mail/V10/1A2B3C4D-0000-1111-2222-333344445555/INBOX.mbox/9F8E/Attachments/1202/2/deck.key", size: 800},
+		{path: "Library/Mail/V10/1A2B3C4D-0000-1111-2222-333344445555/INBOX.mbox/9F8E/Data/1/Messages/1201.emlx", size: 50},
+		{path: "Library/Mail/V10/1A2B3C4D-0000-1111-2222-333344445555/[Gmail].mbox/All Mail.mbox/9F8E/Attachments/1300/2/video.mov", size: 700},
+		{path: "Library/Mail/V10/MailData/Envelope Index", size: 60},
+	})
+
+	result := s.ScanMailAttachments()
+	if !result.Advisory {
+		t.Error("Mail Attachments is not advisory")
+	}
+	want := map[string]int64{
+		"📧 Mail Downloads (copies of opened attachments)":   100,
+		"📧 INBOX attachments (account 1A2B3C4D)":            1700,
+		"📧 [Gmail]/All Mail attachments (account 1A2B3C4D)": 700,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanAppleMediaDownloads(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Group Containers/243LU875E5.groups.com.apple.podcasts/Library/Cache/1F2E.mp3", size: 900},
+		{path: "Library/Group Containers/243LU875E5.groups.com.apple.podcasts/Library/Cache/3A4B.m4a", size: 800},
+		{path: "Library/Group Containers/243LU875E5.groups.com.apple.podcasts/Library/Cache/artwork.db", size: 10},
+		{path: "Movies/TV/Media/Movies/Film (2020)/Film.m4v", size: 700},
+		{path: "Movies/TV/Media/TV Shows/Show/Season 1/episode.movpkg/data", size: 600},
+		{path: "Library/Containers/com.apple.TV/Data/Library/Caches/com.apple.TV/Cache.db", size: 50},
+		{path: "Music/Music/Media.localized/Apple Music/Artist/Album/01 Song.m4p", size: 500},
+	})
+
+	result := s.ScanAppleMediaDownloads()
+	if !result.Advisory {
+		t.Error("Apple Media Downloads is not advisory")
+	}
+	want := map[string]int64{
+		"🎙️ Podcasts: downloaded episodes (2 episodes)": 1710,
+		"📺 TV: Movies (1 video)":                        700,
+		"📺 TV: TV Shows (1 video)":                      600,
+		"📺 TV: cache":                                   50,
+		"🎵 Music: Apple Music downloads (1 song)":       500,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanPhotosCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Pictures/Photos Library.photoslibrary/resources/derivatives/0/0A1B_1_105_c.jpeg", size: 900},
+		{path: "Pictures/Photos Library.photoslibrary/resources/renders/0/0A1B_1_201_a.heic", size: 800},
+		{path: "Pictures/Photos Library.photoslibrary/originals/0/0A1B.heic", size: 5000},
+		{path: "Pictures/Photos Library.photoslibrary/database/Photos.sqlite", size: 100},
+	})
+
+	result := s.ScanPhotosCaches()
+	if !result.Advisory {
+		t.Error("Photos Library Caches is not advisory")
+	}
+	want := map[string]int64{
+		"🖼️ Photos: previews and thumbnails (managed by Photos)": 900,
+		"🖼️ Photos: rendered edits (managed by Photos)":          800,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanAdobeCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Application Support/Adobe/Common/Media Cache Files/clip.mp4 48000.cfa", size: 900},
+		{path: "Library/Caches/Adobe/After Effects/2024/Disk Cache/cache.aec", size: 800},
+		{path: "Pictures/Lightroom/Lightroom Catalog Previews.lrdata/0/0A1B.lrprev", size: 700},
+		{path: "Pictures/Lightroom/Lightroom Catalog Smart Previews.lrdata/0/0A1B.dng", size: 600},
+		{path: "Pictures/Lightroom/Lightroom Catalog.lrcat", size: 500},
+	})
+
+	result := s.ScanAdobeCaches()
+	want := map[string]int64{
+		"🅰️ Media Cache Files":                     900,
+		"🅰️ After Effects disk cache":              800,
+		"🅰️ Lightroom previews: Lightroom Catalog": 700,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanRenderFiles(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Movies/Trip.fcpbundle/Day 1/Render Files/High Quality Media/a.mov", size: 900},
+		{path: "Movies/Trip.fcpbundle/Day 2/Render Files/Peaks Data/b.peak", size: 100},
+		{path: "Movies/Trip.fcpbundle/Day 1/Transcoded Media/Proxy Media/c.mov", size: 800},
+		{path: "Movies/Trip.fcpbundle/Day 1/Original Media/d.mov", size: 5000},
+		{path: "Music/Logic/Song.logicx/Alternatives/000/Freeze Files.nosync/Freeze 1.wav", size: 700},
+		{path: "Music/Logic/Song.logicx/Media/Audio Files/take.wav", size: 4000},
+	})
+
+	result := s.ScanRenderFiles()
+	if !result.Advisory {
+		t.Error("Production Render Files is not advisory")
+	}
+	want := map[string]int64{
+		"🎬 Trip: render files":              1000,
+		"🎬 Trip: optimized and proxy media": 800,
+		"🎹 Song: freeze files":              700,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanGameCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Application Support/Steam/steamapps/shadercache/620/fozpipelinesv6/steam_pipeline_cache.foz", size: 900},
+		{path: "Library/Application Support/Steam/steamapps/shadercache/999/DXVK/cache", size: 100},
+		{path: "Library/Application Support/Steam/steamapps/appmanifest_620.acf", size: 0},
+		{path: "Library/Application Support/Steam/steamapps/downloading/570/game.vpk", size: 800},
+		{path: "Library/Application Support/Steam/steamapps/common/Portal 2/portal2.app/binary", size: 5000},
+		{path: "Library/Application Support/Epic/EpicGamesLauncher/Saved/webcache_4430/Cache/data_1", size: 700},
+		{path: "Library/Application Support/Epic/EpicGamesLauncher/Saved/Config/Mac/GameUserSettings.ini", size: 10},
+	})
+	manifest := "\"AppState\"\n{\n\t\"appid\"\t\t\"620\"\n\t\"name\"\t\t\"Portal 2\"\n}\n"
+	if err := os.WriteFile(filepath.Join(s.HomeDir, "Library", "Application Support", "Steam", "steamapps", "appmanifest_620.acf"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := s.ScanGameCaches()
+	want := map[string]int64{
+		"🎮 Steam shader cache: Portal 2":   900,
+		"🎮 Steam shader cache: app 999":    100,
+		"🎮 Steam: unfinished downloads":    800,
+		"🎮 Epic Games Launcher: web cache": 700,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanExtractedArchives(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Downloads/project/README.md", size: 300},
+		{path: "Downloads/project/src/main.go", size: 500},
+		{path: "Downloads/photos/a.jpg", size: 700},
+		{path: "Downloads/other/file.bin", size: 100},
+		{path: "Desktop/site/index.html", size: 200},
+	})
+	downloads := filepath.Join(s.HomeDir, "Downloads")
+
+	// A zip holding the folder itself, as Archive Utility extracts it
+	writeZip := func(path string, files map[string]int) {
+		t.Helper()
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := zip.NewWriter(f)
+		for name, size := range files {
+			fw, err := w.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fw.Write(make([]byte, size))
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+	writeZip(filepath.Join(downloads, "project.zip"), map[string]int{
+		"project/README.md":          300,
+		"project/src/main.go":        500,
+		"__MACOSX/project/._main.go": 80,
+	})
+	// Photos were edited after extracting, so sizes differ
+	writeZip(filepath.Join(downloads, "photos.zip"), map[string]int{"a.jpg": 650})
+	writeZip(filepath.Join(downloads, "unrelated.zip"), map[string]int{"file.bin": 100})
+
+	f, err := os.Create(filepath.Join(s.HomeDir, "Desktop", "site.tar.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "./index.html", Size: 200, Mode: 0o644, Typeflag: tar.TypeReg})
+	tw.Write(make([]byte, 200))
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	result := s.ScanExtractedArchives()
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	want := []string{
+		"🗜️ Downloads/project.zip (extracted to project/, 100% of files match)",
+		"🗜️ Desktop/site.tar.gz (extracted to site/, 100% of files match)",
+	}
+	sort.Strings(names)
+	sort.Strings(want)
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Errorf("items = %q, want %q", names, want)
+	}
+}
+
+func TestScanOldInstallers(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Downloads/Tool-1.2.dmg", size: 900, age: 30},
+		{path: "Downloads/Fresh.dmg", size: 800, age: 2},
+		{path: "Downloads/Mounted.dmg", size: 700, age: 30},
+		{path: "Downloads/drivers/Driver.pkg", size: 600, age: 30},
+		{path: "Downloads/Legacy.mpkg/Contents/Archive.pax.gz", size: 500, age: 30},
+		{path: "Downloads/Some.app/Contents/Resources/Payload.dmg", size: 400, age: 30},
+		{path: "Desktop/ubuntu.ISO", size: 300, age: 30},
+		{path: "Desktop/notes.txt", size: 200, age: 30},
+	})
+	// Bundle packages go by the folder's own times
+	old := time.Now().AddDate(0, 0, -30)
+	if err := os.Chtimes(filepath.Join(s.HomeDir, "Downloads", "Legacy.mpkg"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	mounted := filepath.Join(s.HomeDir, "Downloads", "Mounted.dmg")
+	s.hdiutil = func(args ...string) ([]byte, error) {
+		return []byte("<plist><dict><key>images</key><array><dict>\n\t<key>image-path</key>\n\t<string>" + mounted + "</string>\n</dict></array></dict></plist>"), nil
+	}
+
+	result := s.ScanOldInstallers()
+	want := map[string]int64{
+		"💿 Downloads/Tool-1.2.dmg":       900,
+		"💿 Downloads/drivers/Driver.pkg": 600,
+		"💿 Downloads/Legacy.mpkg":        500,
+		"💿 Desktop/ubuntu.ISO":           300,
+	}
+	assertItemSizes(t, result, want)
+
+	s.InstallerAge = 60
+	if result := s.ScanOldInstallers(); len(result.Items) != 0 {
+		t.Errorf("items older than 60 days = %+v, want none", result.Items)
+	}
+}
+
+func TestScanScreenshots(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Desktop/Screenshot 2026-08-01 at 10.12.44.png", size: 900, age: 60},
+		{path: "Desktop/Screen Shot 2018-03-02 at 09.00.00.jpg", size: 800, age: 40},
+		{path: "Desktop/CleanShot 2026-08-03 at 11.00.00.mp4", size: 700, age: 35},
+		{path: "Desktop/Screenshot 2026-10-10 at 08.00.00.png", size: 600, age: 2},
+		{path: "Desktop/Screenshot notes.txt", size: 500, age: 60},
+		{path: "Desktop/holiday.png", size: 400, age: 60},
+		{path: "Desktop/old/Screenshot 2025-01-01 at 12.00.00.png", size: 300, age: 60},
+	})
+
+	result := s.ScanScreenshots()
+	want := map[string]int64{
+		"📸 Screenshot 2026-08-01 at 10.12.44.png":  900,
+		"📸 Screen Shot 2018-03-02 at 09.00.00.jpg": 800,
+		"📸 CleanShot 2026-08-03 at 11.00.00.mp4":   700,
+	}
+	assertItemSizes(t, result, want)
+	if result.Total != 2400 {
+		t.Errorf("total = %d, want 2400", result.Total)
+	}
+
+	archive := filepath.Join(s.HomeDir, "Pictures", "Screenshots")
+	s.ScreenshotArchive = archive
+	st := s.screenshotStrategy()
+	if st.Name() != "move to "+archive {
+		t.Errorf("strategy = %q, want moving to %s", st.Name(), archive)
+	}
+	for _, item := range result.Items {
+		if err := st.Remove(item.Path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries, err := os.ReadDir(archive); err != nil || len(entries) != 3 {
+		t.Errorf("archive holds %d files (%v), want 3", len(entries), err)
+	}
+
+	s.ScreenshotAge = 1
+	if result := s.ScanScreenshots(); len(result.Items) != 1 {
+		t.Errorf("items older than a day = %+v, want the recent screenshot", result.Items)
+	}
+}
+
+func TestLargestFiles(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Movies/project.dmg", size: 9000},
+		{path: "Documents/report.pdf", size: 500},
+		{path: "Downloads/installer.pkg", size: 7000},
+		{path: "code/app/notes.txt", size: 10},
+		{path: "empty.txt", size: 0},
+	})
+
+	result := s.LargestFiles(s.HomeDir, 2)
+	if len(result.Items) != 2 {
+		t.Fatalf("items = %+v, want 2", result.Items)
+	}
+	if result.Items[0].Name != "📄 project.dmg" || result.Items[1].Name != "📄 installer.pkg" {
+		t.Errorf("items = %+v, want the two largest, largest first", result.Items)
+	}
+	if result.Total != result.Items[0].Size+result.Items[1].Size || result.Method != "move to Trash" {
+		t.Errorf("total = %d, method %q", result.Total, result.Method)
+	}
+}
+
+func TestScanDSStore(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".DS_Store", size: 100},
+		{path: "Documents/.DS_Store", size: 200},
+		{path: "Documents/notes.txt", size: 500},
+		{path: ".Trash/old/.DS_Store", size: 300},
+		{path: "Volumes/USB/.DS_Store", size: 400, sys: true},
+		{path: "Volumes/USB/photos/.DS_Store", size: 50, sys: true},
+		{path: "Volumes/Backup/Backups.backupdb/.DS_Store", size: 60, sys: true},
+	})
+
+	result := s.ScanDSStore()
+	want := map[string]int64{
+		"🗂️ ~/.DS_Store":           100,
+		"🗂️ ~/Documents/.DS_Store": 200,
+		"🗂️ USB/.DS_Store":         400,
+		"🗂️ USB/photos/.DS_Store":  50,
+	}
+	assertItemSizes(t, result, want)
+	// Items are the files themselves, so no folder can be deleted through them
+	if result.Remover != nil {
+		t.Error("category has its own remover")
+	}
+	for _, item := range result.Items {
+		if filepath.Base(item.Path) != ".DS_Store" {
+			t.Errorf("item %s is not a .DS_Store file", item.Path)
+		}
+	}
+
+	s.Roots = []string{filepath.Join(s.RootDir, "Volumes", "USB", "photos")}
+	result = s.ScanDSStore()
+	if len(result.Items) != 1 || result.Items[0].Size != 50 {
+		t.Errorf("items with roots = %+v", result.Items)
+	}
+}
+
+func TestScanAppleDouble(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "._home.txt", size: 0},
+		{path: "Volumes/USB/._photo.jpg", size: 0, sys: true},
+		{path: "Volumes/USB/photo.jpg", size: 900, sys: true},
+		{path: "Volumes/USB/docs/._report.pdf", size: 0, sys: true},
+		{path: "Volumes/USB/docs/._notes", size: 100, sys: true},
+		{path: "Volumes/Data/._file", size: 0, sys: true},
+	})
+	s.mountType = func(path string) string {
+		if filepath.Base(path) == "Data" {
+			return "apfs"
+		}
+		return "msdos"
+	}
+	header := append([]byte{0x00, 0x05, 0x16, 0x07}, make([]byte, 78)...)
+	for _, path := range []string{
+		filepath.Join(s.HomeDir, "._home.txt"),
+		filepath.Join(s.RootDir, "Volumes/USB/._photo.jpg"),
+		filepath.Join(s.RootDir, "Volumes/USB/docs/._report.pdf"),
+		filepath.Join(s.RootDir, "Volumes/Data/._file"),
+	} {
+		if err := os.WriteFile(path, header, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := s.ScanAppleDouble()
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	sort.Strings(names)
+	want := []string{"🍏 USB/._photo.jpg", "🍏 USB/docs/._report.pdf"}
+	if strings.Join(names, "\n") != strings.Join(want, "\n") || result.Total != 164 {
+		t.Errorf("items = %q, total %d, want %q, total 164", names, result.Total, want)
+	}
+}
+
+func TestScanAppLeftovers(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Applications/Bar.app/Contents/Info.plist", size: 0, sys: true},
+		{path: "Applications/Utilities/Tool.app/Contents/Info.plist", size: 0, sys: true},
+		{path: "Library/Application Support/com.example.Bar/data.db", size: 900},
+		{path: "Library/Application Support/com.example.BarHelper/data.db", size: 800},
+		{path: "Library/Application Support/com.gone.Foo/data.db", size: 700},
+		{path: "Library/Caches/com.gone.Foo/Cache.db", size: 600},
+		{path: "Library/Containers/io.removed.Baz/Data/state", size: 500},
+		{path: "Library/Preferences/com.gone.Foo.plist", size: 400},
+		{path: "Library/Saved Application State/com.gone.Foo.savedState/windows.plist", size: 50},
+		{path: "Library/Preferences/com.apple.finder.plist", size: 300},
+		{path: "Library/Preferences/org.tools.Tool.plist", size: 200},
+		{path: "Library/Preferences/loginwindow.plist", size: 100},
+		{path: "Library/Application Support/Google/Chrome/Local State", size: 100},
+	})
+
+	plist := func(id string) string {
+		return "<plist><dict><key>CFBundleIdentifier</key>\n\t<string>" + id + "</string></dict></plist>"
+	}
+	files := map[string]string{
+		filepath.Join(s.RootDir, "Applications/Bar.app/Contents/Info.plist"):            plist("com.example.Bar"),
+		filepath.Join(s.RootDir, "Applications/Utilities/Tool.app/Contents/Info.plist"): plist("org.tools.Tool"),
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := s.ScanAppLeftovers()
+	want := map[string]int64{
+		"📦 com.gone.Foo (Application Support)":     700,
+		"📦 com.gone.Foo (Caches)":                  600,
+		"📦 io.removed.Baz (Containers)":            500,
+		"📦 com.gone.Foo (Preferences)":             400,
+		"📦 com.gone.Foo (Saved Application State)": 50,
+	}
+	assertItemSizes(t, result, want)
+
+	// In a full scan Cache Files and Saved Application State list com.gone.Foo's
+	// cache and window state, so each is counted once
+	owners := make(map[string][]string)
+	for _, sc := range s.Scanners(SetFull) {
+		switch sc.Name() {
+		case "app-leftovers", "caches", "saved-state":
+			for _, item := range sc.Scan(context.Background()).Items {
+				owners[item.Path] = append(owners[item.Path], sc.Category())
+			}
+		}
+	}
+	for path, categories := range owners {
+		if len(categories) > 1 {
+			t.Errorf("%s is listed by %v", path, categories)
+		}
+	}
+	if got := owners[filepath.Join(s.HomeDir, "Library/Caches/com.gone.Foo")]; len(got) != 1 || got[0] != "Cache Files" {
+		t.Errorf("com.gone.Foo cache listed by %v, want Cache Files", got)
+	}
+}
+
+func TestScanAppLeftoversNoApps(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Application Support/com.gone.Foo/data.db", size: 700},
+	})
+
+	if result := s.ScanAppLeftovers(); len(result.Items) != 0 {
+		t.Errorf("items = %+v, want none without installed apps", result.Items)
+	}
+}
+
+func TestScanSavedAppState(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Saved Application State/com.apple.Safari.savedState/windows.plist", size: 900},
+		{path: "Library/Saved Application State/com.example.Leaky.savedState/data.data", size: 800},
+		{path: "Library/Saved Application State/.DS_Store", size: 10},
+	})
+
+	result := s.ScanSavedAppState()
+	want := map[string]int64{
+		"🪟 com.apple.Safari":  900,
+		"🪟 com.example.Leaky": 800,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanQuickLookCache(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "var/folders/ab/xyz/C/com.apple.QuickLook.thumbnailcache/thumbnails.data", size: 900},
+		{path: "var/folders/ab/xyz/T/scratch", size: 10},
+	})
+	t.Setenv("TMPDIR", filepath.Join(s.HomeDir, "var", "folders", "ab", "xyz", "T")+"/")
+
+	result := s.ScanQuickLookCache()
+	if len(result.Items) != 1 || result.Items[0].Name != "🖼️ QuickLook thumbnail cache" || result.Total != 900 {
+		t.Errorf("items = %+v, total %d", result.Items, result.Total)
+	}
+}
+
+func TestScanFontIconCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "var/folders/ab/xyz/C/com.apple.FontRegistry/annex_aux", size: 900},
+		{path: "var/folders/ab/xyz/C/com.apple.iconservices/store.index", size: 800},
+		{path: "Library/Caches/com.apple.iconservices.store/A1B2/icon.isdata", size: 700, sys: true},
+	})
+	t.Setenv("TMPDIR", filepath.Join(s.HomeDir, "var", "folders", "ab", "xyz", "T"))
+
+	result := s.ScanFontIconCaches()
+	want := map[string]int64{
+		"🔤 Font cache (com.apple.FontRegistry)": 900,
+		"🧩 Icon Services cache":                 800,
+		"🧩 Icon Services store (shared)":        700,
+	}
+	assertItemSizes(t, result, want)
+	if name := s.fontIconStrategy().Name(); name != "run atsutil databases -removeUser or delete" {
+		t.Errorf("strategy = %q", name)
+	}
+}
+
+func TestScanBrowserSiteData(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Application Support/Google/Chrome/Default/Preferences", size: 1},
+		{path: "Library/Application Support/Google/Chrome/Default/IndexedDB/https_mail.google.com_0.indexeddb.leveldb/000003.log", size: 600},
+		{path: "Library/Application Support/Google/Chrome/Default/IndexedDB/https_mail.google.com_0.indexeddb.blob/1/00/2", size: 300},
+		{path: "Library/Application Support/Google/Chrome/Default/IndexedDB/https_small.example_0.indexeddb.leveldb/000003.log", size: 50},
+		{path: "Library/Application Support/Google/Chrome/Profile 1/Preferences", size: 1},
+		{path: "Library/Application Support/Google/Chrome/Profile 1/Service Worker/CacheStorage/4f1a9c2e/index.txt", size: 0},
+		{path: "Library/Application Support/Google/Chrome/Profile 1/Service Worker/CacheStorage/4f1a9c2e/0a1b/data", size: 800},
+		{path: "Library/Application Support/Google/Chrome/System Profile/Local State", size: 1},
+		{path: "Library/Application Support/com.operasoftware.Opera/Preferences", size: 1},
+		{path: "Library/Application Support/com.operasoftware.Opera/IndexedDB/http_localhost_8080.indexeddb.leveldb/000003.log", size: 400},
+		{path: "Library/Application Support/Firefox/Profiles/x1y2z3.default-release/storage/default/https+++www.figma.com/idb/1.sqlite", size: 700},
+		{path: "Library/Application Support/Firefox/Profiles/a1b2c3.work/storage/default/moz-extension+++0d1e-2f3a^userContextId=1/idb/1.sqlite", size: 200},
+	})
+	index := filepath.Join(s.HomeDir, "Library", "Application Support", "Google", "Chrome", "Profile 1", "Service Worker", "CacheStorage", "4f1a9c2e", "index.txt")
+	// The index is a protocol buffer holding the origin
+	if err := os.WriteFile(index, []byte("\x08\x01\x12\x1bhttps://app.example.com/\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(size int64) { siteDataMinSize = size }(siteDataMinSize)
+	siteDataMinSize = 100
+
+	result := s.ScanBrowserSiteData()
+	want := map[string]int64{
+		"🌐 Chrome: mail.google.com IndexedDB":                        900,
+		"🌐 Chrome (Profile 1): app.example.com Service Worker cache": 829,
+		"🌐 Opera: localhost:8080 IndexedDB":                          400,
+		"🌐 Firefox: www.figma.com site data":                         700,
+		"🌐 Firefox (work): moz-extension://0d1e-2f3a site data":      200,
+	}
+	assertItemSizes(t, result, want)
+
+	// The database goes with its blobs
+	db := filepath.Join(s.HomeDir, "Library", "Application Support", "Google", "Chrome", "Default", "IndexedDB", "https_mail.google.com_0.indexeddb.leveldb")
+	if err := result.Remover(db); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{db, strings.TrimSuffix(db, ".leveldb") + ".blob"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s not deleted: %v", path, err)
+		}
+	}
+}
+
+func TestScanMediaAppCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Application Support/Spotify/PersistentCache/Storage/ab/abcdef", size: 900},
+		{path: "Library/Application Support/Spotify/prefs", size: 5},
+		{path: "Library/Application Support/Plex Media Server/Cache/PhotoTranscoder/1.jpg", size: 800},
+		{path: "Library/Application Support/Plex Media Server/Plug-in Support/Databases/library.db", size: 700},
+	})
+
+	result := s.ScanMediaAppCaches()
+	want := map[string]int64{
+		"🎵 Spotify: streaming cache (includes songs downloaded for offline listening)": 900,
+		"🎵 Plex Media Server: cache": 800,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanChatAppCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Application Support/Slack/Service Worker/CacheStorage/abc/0", size: 900},
+		{path: "Library/Application Support/Slack/Cache/Cache_Data/data_1", size: 800},
+		{path: "Library/Application Support/Slack/storage/root-state.json", size: 5},
+		{path: "Library/Application Support/discord/Code Cache/js/index", size: 700},
+		{path: "Library/Application Support/discord/0.0.300/modules/voice.node", size: 5},
+		{path: "Library/Containers/com.microsoft.teams2/Data/Library/Application Support/Microsoft/MSTeams/EBWebView/WV2Profile_tfw/Cache/data", size: 600},
+		{path: "Library/Containers/com.microsoft.teams2/Data/Library/Caches/com.microsoft.teams2/Cache.db", size: 500},
+		{path: "Library/Application Support/Microsoft/Teams/GPUCache/data_0", size: 400},
+		{path: "Library/Application Support/zoom.us/data/zoomus.enc.db", size: 300},
+	})
+
+	result := s.ScanChatAppCaches()
+	want := map[string]int64{
+		"💬 Slack: " + filepath.Join("Service Worker", "CacheStorage"): 900,
+		"💬 Slack: Cache":                                             800,
+		"💬 Discord: Code Cache":                                      700,
+		"💬 Teams WV2Profile_tfw: Cache":                              600,
+		"💬 Teams: Caches":                                            500,
+		"💬 Teams classic: GPUCache":                                  400,
+		"💬 Zoom: data (signs you out and clears local chat history)": 300,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanElectronCaches(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Application Support/Obsidian/Cache/Cache_Data/data_1", size: 900},
+		{path: "Library/Application Support/Obsidian/Code Cache/js/index", size: 800},
+		{path: "Library/Application Support/Obsidian/GPUCache/data_0", size: 70},
+		{path: "Library/Application Support/Obsidian/Service Worker/CacheStorage/abc/0", size: 60},
+		{path: "Library/Application Support/Obsidian/IndexedDB/db", size: 500},
+		{path: "Library/Application Support/Code/Cache/Cache_Data/data_1", size: 400},
+		{path: "Library/Application Support/Code/Code Cache/js/index", size: 300},
+		{path: "Library/Application Support/MyApp/Cache/data", size: 200},
+		{path: "Library/Application Support/Slack/Cache/data", size: 100},
+		{path: "Library/Application Support/Slack/Code Cache/js/index", size: 100},
+	})
+
+	result := s.ScanElectronCaches()
+	want := map[string]int64{
+		"⚛️ Obsidian: Cache":      900,
+		"⚛️ Obsidian: Code Cache": 800,
+		"⚛️ Obsidian: GPUCache":   70,
+		"⚛️ Obsidian: " + filepath.Join("Service Worker", "CacheStorage"): 60,
+		"⚛️ Code: Code Cache": 300,
+		// Slack is listed under Chat App Caches
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanRuntimeVersions(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".nvm/versions/node/v18.17.0/bin/node", size: 900},
+		{path: ".nvm/versions/node/v20.9.0/bin/node", size: 800},
+		{path: ".nvm/versions/node/v20.11.1/bin/node", size: 700},
+		{path: ".pyenv/versions/3.10.13/bin/python", size: 600},
+		{path: ".pyenv/versions/3.12.1/bin/python", size: 500},
+		{path: ".pyenv/versions/3.11.7/bin/python", size: 400},
+		{path: ".rbenv/versions/3.1.4/bin/ruby", size: 300},
+		{path: ".rbenv/versions/3.3.0/bin/ruby", size: 200},
+	})
+	write := func(path, content string) {
+		path = filepath.Join(s.HomeDir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// default points at lts/iron, which resolves to the newest 20.x
+	write(".nvm/alias/default", "lts/iron\n")
+	write(".nvm/alias/lts/iron", "v20\n")
+	write(".pyenv/version", "3.12.1\n3.11.7\n")
+	t.Setenv("RBENV_VERSION", "3.3.0")
+
+	result := s.ScanRuntimeVersions()
+	want := map[string]int64{
+		"⬢ Node 18.17.0 (nvm)":     900,
+		"⬢ Node 20.9.0 (nvm)":      800,
+		"🐍 Python 3.10.13 (pyenv)": 600,
+		"💎 Ruby 3.1.4 (rbenv)":     300,
+	}
+	assertItemSizes(t, result, want)
+}
+
+func TestScanGoArtifacts(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "go/pkg/mod/github.com/pkg/errors@v0.9.1/errors.go", size: 900},
+		{path: "Library/Caches/go-build/ab/abcdef-d", size: 700},
+		{path: ".cache/go-build/cd/cdef-d", size: 50},
+		{path: "sdk/go1.21.5/bin/go", size: 400},
+		{path: "sdk/go1.9.7/bin/go", size: 300},
+	})
+
+	var goScanner boundScanner
+	for _, sc := range s.Scanners(SetDev) {
+		if sc.Name() == "go" {
+			goScanner = sc.(boundScanner)
+		}
+	}
+	result := goScanner.Scan(context.Background())
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	want := "Go: module cache, Go: build cache, Go: build cache (~/.cache), 🐹 Go SDK 1.9.7, 🐹 Go SDK 1.21.5"
+	if got := strings.Join(names, ", "); got != want {
+		t.Errorf("items = %s, want %s", got, want)
+	}
+	if result.Total != 2350 {
+		t.Errorf("total = %d", result.Total)
+	}
+	if result.Method != "run go clean -cache or run go clean -modcache or delete" {
+		t.Errorf("method = %q", result.Method)
+	}
+}
+
+func TestScanBun(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".bun/install/cache/react@18.2.0/index.js", size: 300},
+		{path: ".bun/install/global/node_modules/typescript/lib.js", size: 200},
+		{path: "code/app/bun.lockb", size: 10},
+		{path: "code/app/node_modules/react/index.js", size: 100},
+		{path: "code/web/package-lock.json", size: 10},
+		{path: "code/web/node_modules/react/index.js", size: 100},
+	})
+
+	caches := s.ScanNpmYarnCaches()
+	if caches.Total != 500 || len(caches.Items) != 2 {
+		t.Errorf("caches = %+v, want the Bun cache and global packages", caches.Items)
+	}
+
+	names := make(map[string]bool)
+	for _, item := range s.ScanNodeModules().Items {
+		names[item.Name] = true
+	}
+	if !names["📦 code/app (Bun)"] || !names["📦 code/web"] {
+		t.Errorf("node_modules = %v", names)
+	}
+}
+
+func TestScanDenoCache(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Caches/deno/deps/https/deno.land/abc", size: 400},
+		{path: "Library/Caches/deno/gen/file/x.js", size: 100},
+		{path: "Library/Caches/deno/dep_analysis_cache_v1", size: 20},
+	})
+
+	result := s.ScanDenoCache()
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	want := []string{"Deno: dep_analysis_cache_v1", "Deno: deps (remote modules)", "Deno: gen (transpiled and type-checked code)"}
+	if strings.Join(names, ", ") != strings.Join(want, ", ") {
+		t.Errorf("items = %q, want %q", names, want)
+	}
+
+	t.Setenv("DENO_DIR", filepath.Join(s.HomeDir, "elsewhere"))
+	if result := s.ScanDenoCache(); len(result.Items) != 0 {
+		t.Errorf("with DENO_DIR = %+v", result.Items)
+	}
+}
+
+func TestPnpmStorePruned(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/pnpm/store/v3/files/00/pkg", size: 500},
+		{path: ".yarn/cache/pkg.zip", size: 100},
+	})
+	var pnpm boundScanner
+	for _, sc := range s.Scanners(SetDev) {
+		if sc.Name() == "js-caches" {
+			pnpm = sc.(boundScanner)
+		}
+	}
+	result := pnpm.Scan(context.Background())
+	if len(result.Items) != 2 || result.Method != "run npm cache clean or run pnpm store prune or delete" {
+		t.Errorf("result = %+v, method %q", result.Items, result.Method)
+	}
+}
+
+func TestScanBrewCleanupDryRun(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Caches/Homebrew/wget.tar.gz", size: 700},
+	})
+	cache := filepath.Join(s.HomeDir, "Library", "Caches", "Homebrew")
+	hash := strings.Repeat("a", 64)
+	dryRun := "Would remove: " + cache + "/downloads/" + hash + "--wget-1.21.4.bottle.tar.gz (1.5MB)\n" +
+		"Would remove: /opt/homebrew/Cellar/openssl@3/3.1.0 (6,987 files, 28KB)\n" +
+		"Would remove: " + cache + "/Cask/empty.dmg (0B)\n" +
+		"==> This operation would free approximately 1.5MB of disk space.\n"
+	var ran string
+	s.brew = func(args ...string) ([]byte, error) {
+		ran = strings.Join(args, " ")
+		return []byte(dryRun), nil
+	}
+
+	result := s.ScanBrewCache()
+	if ran != "cleanup -s --prune=all -n" {
+		t.Errorf("ran brew %q", ran)
+	}
+	// The cleanup removes everything at once, so nothing can be left unmarked
+	if len(result.Items) != 1 || result.Items[0].Name != "Brew: everything brew cleanup would remove (2 entries)" {
+		t.Fatalf("items = %+v, want one", result.Items)
+	}
+	if result.Total != 1572864+28672 || result.Items[0].Size != result.Total {
+		t.Errorf("total = %d, item %d", result.Total, result.Items[0].Size)
+	}
+	if result.Remover != nil {
+		t.Error("cleanup by brew has its own remover")
+	}
+
+	want := map[string]int64{
+		"Brew: wget-1.21.4.bottle.tar.gz":     1572864,
+		"Brew: openssl@3 3.1.0 (old version)": 28672,
+	}
+	items := parseBrewDryRun(dryRun, cache)
+	if len(items) != len(want) {
+		t.Fatalf("parsed %+v, want %v", items, want)
+	}
+	for _, item := range items {
+		if size, ok := want[item.Name]; !ok || item.Size != size {
+			t.Errorf("item %q is %d bytes, want %v", item.Name, item.Size, want)
+		}
+	}
+
+	// Without brew the cache entries are deleted directly
+	s.brew = func(...string) ([]byte, error) { return nil, errors.New("brew is not installed") }
+	result = s.ScanBrewCache()
+	if len(result.Items) != 1 || result.Method != "delete" {
+		t.Fatalf("items = %+v, method %q", result.Items, result.Method)
+	}
+	if err := result.Remover(result.Items[0].Path); err != nil {
+		t.Errorf("deleting without brew: %v", err)
+	}
+
+	// Deleting goes through Remove when set, so its rate limits hold
+	var removed []string
+	s.Remove = func(path string) error {
+		removed = append(removed, path)
+		return nil
+	}
+	if err := s.ScanBrewCache().Remover("/some/cache"); err != nil || len(removed) != 1 {
+		t.Errorf("Remove got %v (%v), want the deleted path", removed, err)
+	}
+
+	if got := brewFreed("==> This operation has freed approximately 1.2GB of disk space.\n"); got != "brew freed approximately 1.2GB" {
+		t.Errorf("brewFreed() = %q", got)
+	}
+	if got := brewFreed("Pruned 0 symbolic links\n"); got != "" {
+		t.Errorf("brewFreed() = %q for a cleanup that freed nothing", got)
+	}
+}
+
+func TestNpmCacheCleanedByNpm(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: ".npm/_cacache/content-v2/sha512/ab/pkg", size: 700},
+		{path: "Library/Caches/Yarn/pkg.zip", size: 100},
+	})
+	var ran []string
+	s.npm = func(args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(args, " "))
+		if args[1] == "verify" {
+			return []byte("Cache verified and compressed (~/.npm/_cacache)\n" +
+				"Content verified: 40 (600000 bytes)\n" +
+				"Content garbage-collected: 3 (100000 bytes)\n" +
+				"Index entries: 40\n"), nil
+		}
+		return nil, nil
+	}
+	var js boundScanner
+	for _, sc := range s.Scanners(SetDev) {
+		if sc.Name() == "js-caches" {
+			js = sc.(boundScanner)
+		}
+	}
+	result := js.Scan(context.Background())
+	npmDir := filepath.Join(s.HomeDir, ".npm")
+	for _, path := range []string{npmDir, npmDir} {
+		if err := result.Remover(path); err != nil {
+			t.Fatalf("Remover(%s) = %v", path, err)
+		}
+	}
+	if got, want := strings.Join(ran, ", "), "cache verify, cache clean --force"; got != want {
+		t.Errorf("ran npm %q, want %q once", got, want)
+	}
+	if _, err := os.Stat(npmDir); err != nil {
+		t.Errorf("npm cache removed directly: %v", err)
+	}
+	want := "npm reclaimed 700 kB (40 entries cleared, 3 unused garbage-collected)"
+	if got := result.Report(npmDir); got != want {
+		t.Errorf("Report() = %q, want %q", got, want)
+	}
+	if got := result.Report(filepath.Join(s.HomeDir, "Library", "Caches", "Yarn")); got != "" {
+		t.Errorf("Report(yarn) = %q, want nothing", got)
+	}
+
+	// Without npm the cache is left alone rather than deleted
+	s.npm = func(...string) ([]byte, error) { return nil, errors.New("npm is not installed") }
+	result = js.Scan(context.Background())
+	if err := result.Remover(npmDir); err == nil {
+		t.Error("Remover succeeded without npm")
+	}
+}
+
+func TestScanOldLogFiles(t *testing.T) {
+	s := newFakeHomeScanner(t, []fixture{
+		{path: "Library/Logs/old.log", size: 40, age: OldLogDays + 1},
+		{path: "Library/Logs/today.log", size: 10},
+		{path: "var/log/install.log", size: 5, age: 30, sys: true},
+	})
+
+	result := s.ScanOldLogFiles()
+	if len(result.Items) != 2 || result.Total != 45 {
+		t.Errorf("items = %+v, want the two logs older than %d days", result.Items, OldLogDays)
+	}
+	for _, sc := range s.Scanners(SetQuick) {
+		if sc.Name() == "node-modules" || sc.Name() == "logs" {
+			t.Errorf("quick scan includes %s", sc.Name())
+		}
+	}
+}
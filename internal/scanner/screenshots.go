@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "screenshots", Category: "Screenshots", Risk: types.RiskLow, Sets: []string{SetFull}, Order: 43, Scan: (*Scanner).ScanScreenshots,
+		Strategy: (*Scanner).screenshotStrategy})
+}
+
+// DefaultScreenshotAge is how many days screenshots stay on the Desktop
+// before they are listed, when Scanner.ScreenshotAge is unset
+const DefaultScreenshotAge = 30
+
+// screenshotExtensions are the formats screencapture can save in
+var screenshotExtensions = []string{".png", ".jpg", ".jpeg", ".heic", ".tiff", ".gif", ".pdf"}
+
+// isScreenshot reports whether name is a file saved by screencapture, under
+// its current or pre-Mojave name, or by CleanShot, which also saves
+// recordings
+func isScreenshot(name string) bool {
+	if strings.HasPrefix(name, "CleanShot ") {
+		return true
+	}
+	if !strings.HasPrefix(name, "Screenshot ") && !strings.HasPrefix(name, "Screen Shot ") {
+		return false
+	}
+	return contains(screenshotExtensions, strings.ToLower(filepath.Ext(name)))
+}
+
+// screenshotStrategy moves screenshots into ScreenshotArchive when set,
+// otherwise to the Trash
+func (s *Scanner) screenshotStrategy() strategy.Strategy {
+	if s.ScreenshotArchive != "" {
+		return strategy.Archive{Dir: s.ScreenshotArchive}
+	}
+	return strategy.MoveToTrash(s.HomeDir)
+}
+
+// ScanScreenshots lists the screenshots on the Desktop not changed for
+// ScreenshotAge days, one item per file, for the category to be cleared
+// in one batch
+func (s *Scanner) ScanScreenshots() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Screenshots",
+		Items:    []types.FileItem{},
+	}
+
+	days := s.ScreenshotAge
+	if days <= 0 {
+		days = DefaultScreenshotAge
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	desktop := filepath.Join(s.HomeDir, "Desktop")
+	entries, err := s.readDir(desktop)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(desktop, err)
+		}
+		return result
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !isScreenshot(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		result.Items = append(result.Items, types.FileItem{
+			Path: filepath.Join(desktop, entry.Name()),
+			Size: info.Size(),
+			Name: "📸 " + entry.Name(),
+			Age:  int(time.Since(info.ModTime()).Hours() / 24),
+		})
+		result.Total += info.Size()
+	}
+
+	return result
+}
@@ -0,0 +1,266 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "sim-caches", Category: "Simulator Caches", Risk: types.RiskLow, Sets: []string{SetFull, SetDev}, Order: 81, Scan: (*Scanner).ScanSimulatorCaches})
+	Register(Registration{Name: "sim-runtimes", Category: "Simulator Runtimes", Risk: types.RiskMedium, Sets: []string{SetFull, SetDev}, Order: 82, Scan: (*Scanner).ScanSimulatorRuntimes})
+}
+
+// xcrunTimeout bounds a single xcrun call
+const xcrunTimeout = 60 * time.Second
+
+// simDevice is the part of a device in `xcrun simctl list devices --json`
+// used here
+type simDevice struct {
+	UDID              string `json:"udid"`
+	Name              string `json:"name"`
+	State             string `json:"state"`
+	IsAvailable       bool   `json:"isAvailable"`
+	AvailabilityError string `json:"availabilityError"`
+	LastBootedAt      string `json:"lastBootedAt"`
+}
+
+// runXcrun runs xcrun and returns its standard output
+func (s *Scanner) runXcrun(args ...string) ([]byte, error) {
+	if s.xcrun != nil {
+		return s.xcrun(args...)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), xcrunTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "xcrun", args...).Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			if msg := strings.TrimSpace(string(exit.Stderr)); msg != "" {
+				return nil, fmt.Errorf("xcrun %s: %w: %s", strings.Join(args[:min(2, len(args))], " "), err, msg)
+			}
+		}
+		return nil, fmt.Errorf("xcrun %s: %w", strings.Join(args[:min(2, len(args))], " "), err)
+	}
+	return out, nil
+}
+
+// simDevices lists the simulators simctl knows about, keyed by runtime
+// identifier
+func (s *Scanner) simDevices() (map[string][]simDevice, error) {
+	out, err := s.runXcrun("simctl", "list", "devices", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Devices map[string][]simDevice `json:"devices"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("simctl list devices: %w", err)
+	}
+	return list.Devices, nil
+}
+
+// scanSimulators adds the simulators simctl knows about in devicesDir to
+// result, one item per device named after its model and runtime. Booted
+// simulators are left out. It returns the remover for those items, which
+// deletes them through simctl rather than removing their folders.
+func (s *Scanner) scanSimulators(result *types.ScanResult, devicesDir string) (func(path string) error, error) {
+	devices, err := s.simDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	runtimes := make([]string, 0, len(devices))
+	for runtime := range devices {
+		runtimes = append(runtimes, runtime)
+	}
+	sort.Strings(runtimes)
+
+	unavailable := make(map[string]bool)
+	for _, runtime := range runtimes {
+		for _, dev := range devices[runtime] {
+			if dev.UDID == "" || dev.State == "Booted" {
+				continue
+			}
+			path := filepath.Join(devicesDir, dev.UDID)
+			size := s.dirSize(path)
+			if size == 0 {
+				continue
+			}
+
+			label := fmt.Sprintf("📱 Simulator: %s (%s", dev.Name, runtimeName(runtime))
+			if !dev.IsAvailable {
+				label += ", unavailable"
+				unavailable[path] = true
+			}
+			label += ")"
+
+			item := types.FileItem{Path: path, Size: size, Name: label, IsDir: true}
+			if booted, err := time.Parse(time.RFC3339, dev.LastBootedAt); err == nil {
+				item.Age = int(time.Since(booted).Hours() / 24)
+			}
+			result.Items = append(result.Items, item)
+			result.Total += size
+		}
+	}
+
+	// Unavailable simulators, whose runtime is gone, go in one call
+	var once sync.Once
+	var onceErr error
+	return func(path string) error {
+		if filepath.Dir(path) != devicesDir {
+			return s.deleter().Remove(path)
+		}
+		if unavailable[path] {
+			once.Do(func() { _, onceErr = s.runXcrun("simctl", "delete", "unavailable") })
+			if onceErr != nil {
+				return types.NewPathError("remove", path, onceErr)
+			}
+			return nil
+		}
+		if _, err := s.runXcrun("simctl", "delete", filepath.Base(path)); err != nil {
+			return types.NewPathError("remove", path, err)
+		}
+		return nil
+	}, nil
+}
+
+// runtimeName turns a simulator runtime identifier such as
+// com.apple.CoreSimulator.SimRuntime.iOS-17-2 into "iOS 17.2"
+func runtimeName(id string) string {
+	name := id[strings.LastIndexByte(id, '.')+1:]
+	platform, version, ok := strings.Cut(name, "-")
+	if !ok {
+		return name
+	}
+	return platform + " " + strings.ReplaceAll(version, "-", ".")
+}
+
+// ScanSimulatorRuntimes lists the downloaded simulator runtimes, both the
+// user's and those installed for all users, noting how many simulators
+// still use each
+func (s *Scanner) ScanSimulatorRuntimes() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Simulator Runtimes",
+		Items:    []types.FileItem{},
+	}
+
+	// Without simctl, runtimes are listed without their simulators
+	used := make(map[string]int)
+	if devices, err := s.simDevices(); err == nil {
+		for runtime, devs := range devices {
+			used[runtime] = len(devs)
+		}
+	}
+
+	runtimeDirs := []string{
+		filepath.Join(s.HomeDir, "Library", "Developer", "CoreSimulator", "Profiles", "Runtimes"),
+		s.systemPath("Library", "Developer", "CoreSimulator", "Profiles", "Runtimes"),
+	}
+	for _, dir := range runtimeDirs {
+		entries, err := s.readDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(dir, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), ".simruntime")
+			if !ok || !entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			size := s.dirSize(path)
+			if size == 0 {
+				continue
+			}
+
+			label := "🧩 Runtime: " + name
+			id := readPlistString(filepath.Join(path, "Contents", "Info.plist"), "CFBundleIdentifier")
+			if n := used[id]; n == 1 {
+				label += " (used by 1 simulator)"
+			} else if n > 1 {
+				label += fmt.Sprintf(" (used by %d simulators)", n)
+			}
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: label, IsDir: true})
+			result.Total += size
+		}
+	}
+
+	return result
+}
+
+// ScanSimulatorCaches lists what CoreSimulator caches for all simulators,
+// one item per dyld shared cache, which it builds for each runtime, and
+// one per other cache
+func (s *Scanner) ScanSimulatorCaches() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Simulator Caches",
+		Items:    []types.FileItem{},
+	}
+
+	cachesDir := filepath.Join(s.HomeDir, "Library", "Developer", "CoreSimulator", "Caches")
+	entries, err := s.readDir(cachesDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(cachesDir, err)
+		}
+		return result
+	}
+	for _, entry := range entries {
+		path := filepath.Join(cachesDir, entry.Name())
+		if entry.Name() == "dyld" && entry.IsDir() {
+			s.addDyldCaches(result, path)
+			continue
+		}
+		size := s.dirSize(path)
+		if size == 0 {
+			continue
+		}
+		result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: "🧩 " + entry.Name(), IsDir: entry.IsDir()})
+		result.Total += size
+	}
+	return result
+}
+
+// addDyldCaches adds the dyld shared caches in dyldDir, kept per macOS
+// build and runtime, e.g. dyld/23C71/com.apple.CoreSimulator.SimRuntime.iOS-17-2
+func (s *Scanner) addDyldCaches(result *types.ScanResult, dyldDir string) {
+	builds, err := s.readDir(dyldDir)
+	if err != nil {
+		result.AddError(dyldDir, err)
+		return
+	}
+	for _, build := range builds {
+		if !build.IsDir() {
+			continue
+		}
+		buildDir := filepath.Join(dyldDir, build.Name())
+		runtimes, err := s.readDir(buildDir)
+		if err != nil {
+			result.AddError(buildDir, err)
+			continue
+		}
+		for _, runtime := range runtimes {
+			path := filepath.Join(buildDir, runtime.Name())
+			size := s.dirSize(path)
+			if size == 0 {
+				continue
+			}
+			label := fmt.Sprintf("🧩 dyld cache: %s (macOS %s)", runtimeName(runtime.Name()), build.Name())
+			result.Items = append(result.Items, types.FileItem{Path: path, Size: size, Name: label, IsDir: runtime.IsDir()})
+			result.Total += size
+		}
+	}
+}
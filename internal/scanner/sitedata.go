@@ -0,0 +1,201 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "site-data", Category: "Browser Site Data", Risk: types.RiskMedium, Sets: []string{SetFull}, Order: 18, Scan: (*Scanner).ScanBrowserSiteData})
+}
+
+// siteDataMinSize is the size from which a site's stored data is listed
+var siteDataMinSize int64 = 50 << 20
+
+// chromiumBrowsers are Chromium-based browsers and their data folders in
+// ~/Library/Application Support
+var chromiumBrowsers = []struct {
+	name string
+	dir  []string
+}{
+	{"Chrome", []string{"Google", "Chrome"}},
+	{"Chrome Beta", []string{"Google", "Chrome Beta"}},
+	{"Chromium", []string{"Chromium"}},
+	{"Brave", []string{"BraveSoftware", "Brave-Browser"}},
+	{"Edge", []string{"Microsoft Edge"}},
+	{"Vivaldi", []string{"Vivaldi"}},
+	{"Arc", []string{"Arc", "User Data"}},
+	{"Opera", []string{"com.operasoftware.Opera"}},
+}
+
+// cacheStorageOrigin finds the origin in a Service Worker cache's index
+var cacheStorageOrigin = regexp.MustCompile(`[a-z-]+://[A-Za-z0-9.\-\[\]:]+`)
+
+// siteName shows an origin given as its scheme, host and port, leaving out
+// the scheme and default port of web sites
+func siteName(scheme, host, port string) string {
+	if port != "" && port != "0" {
+		host += ":" + port
+	}
+	if scheme == "http" || scheme == "https" {
+		return host
+	}
+	return scheme + "://" + host
+}
+
+// chromiumOrigin parses the origin Chromium names IndexedDB folders by,
+// e.g. https_mail.google.com_0 for https://mail.google.com
+func chromiumOrigin(name string) string {
+	scheme, rest, ok := strings.Cut(name, "_")
+	if !ok {
+		return name
+	}
+	host, port := rest, ""
+	if i := strings.LastIndexByte(rest, '_'); i >= 0 {
+		host, port = rest[:i], rest[i+1:]
+	}
+	return siteName(scheme, host, port)
+}
+
+// firefoxOrigin parses the origin Firefox names site storage folders by,
+// e.g. https+++example.com+8080^userContextId=1 for https://example.com:8080
+func firefoxOrigin(name string) string {
+	name, _, _ = strings.Cut(name, "^")
+	scheme, rest, ok := strings.Cut(name, "+++")
+	if !ok {
+		return name
+	}
+	host, port := rest, ""
+	if i := strings.LastIndexByte(rest, '+'); i >= 0 && strings.Trim(rest[i+1:], "0123456789") == "" {
+		host, port = rest[:i], rest[i+1:]
+	}
+	return siteName(scheme, host, port)
+}
+
+// ScanBrowserSiteData lists the sites storing the most data in each
+// browser profile, through IndexedDB or Service Worker caches, so single
+// web apps hoarding gigabytes show up by name. Chromium-based browsers and
+// Firefox are covered; Safari's data is out of reach of other apps.
+func (s *Scanner) ScanBrowserSiteData() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Browser Site Data",
+		Items:    []types.FileItem{},
+	}
+	// IndexedDB databases keep large values in a .blob folder next to them
+	extra := make(map[string]string)
+	add := func(label string, paths ...string) {
+		var size int64
+		for _, path := range paths {
+			size += s.dirSize(path)
+		}
+		if size == 0 || size < siteDataMinSize {
+			return
+		}
+		result.Items = append(result.Items, types.FileItem{Path: paths[0], Size: size, Name: "🌐 " + label, IsDir: true})
+		result.Total += size
+		if len(paths) > 1 {
+			extra[paths[0]] = paths[1]
+		}
+	}
+
+	supportDir := filepath.Join(s.HomeDir, "Library", "Application Support")
+	for _, browser := range chromiumBrowsers {
+		root := filepath.Join(append([]string{supportDir}, browser.dir...)...)
+		entries, err := s.readDir(root)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(root, err)
+			}
+			continue
+		}
+		// Profiles are folders with a Preferences file; Opera keeps its one
+		// profile in the data folder itself
+		profiles := []string{root}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				profiles = append(profiles, filepath.Join(root, entry.Name()))
+			}
+		}
+		for _, profile := range profiles {
+			if _, err := os.Stat(filepath.Join(profile, "Preferences")); err != nil {
+				continue
+			}
+			label := browser.name
+			if profile != root && filepath.Base(profile) != "Default" {
+				label += " (" + filepath.Base(profile) + ")"
+			}
+			s.addChromiumSiteData(profile, label, add)
+		}
+	}
+
+	firefoxDir := filepath.Join(supportDir, "Firefox", "Profiles")
+	profiles, err := s.readDir(firefoxDir)
+	if err != nil && !os.IsNotExist(err) {
+		result.AddError(firefoxDir, err)
+	}
+	for _, profile := range profiles {
+		storage := filepath.Join(firefoxDir, profile.Name(), "storage", "default")
+		sites, _ := s.readDir(storage)
+		label := "Firefox"
+		if _, name, ok := strings.Cut(profile.Name(), "."); ok && name != "default-release" {
+			label += " (" + name + ")"
+		}
+		for _, site := range sites {
+			if site.IsDir() {
+				add(label+": "+firefoxOrigin(site.Name())+" site data", filepath.Join(storage, site.Name()))
+			}
+		}
+	}
+
+	result.Remover = func(path string) error {
+		if err := s.deleter().Remove(path); err != nil {
+			return err
+		}
+		if blob, ok := extra[path]; ok {
+			return s.deleter().Remove(blob)
+		}
+		return nil
+	}
+	return result
+}
+
+// addChromiumSiteData adds the IndexedDB databases and Service Worker
+// caches of a Chromium profile, one item per site
+func (s *Scanner) addChromiumSiteData(profile, label string, add func(label string, paths ...string)) {
+	idbDir := filepath.Join(profile, "IndexedDB")
+	entries, _ := s.readDir(idbDir)
+	for _, entry := range entries {
+		origin, ok := strings.CutSuffix(entry.Name(), ".indexeddb.leveldb")
+		if !ok {
+			continue
+		}
+		db := filepath.Join(idbDir, entry.Name())
+		paths := []string{db}
+		blob := filepath.Join(idbDir, origin+".indexeddb.blob")
+		if _, err := os.Stat(blob); err == nil {
+			paths = append(paths, blob)
+		}
+		add(label+": "+chromiumOrigin(origin)+" IndexedDB", paths...)
+	}
+
+	cacheDir := filepath.Join(profile, "Service Worker", "CacheStorage")
+	caches, _ := s.readDir(cacheDir)
+	for _, cache := range caches {
+		if !cache.IsDir() {
+			continue
+		}
+		path := filepath.Join(cacheDir, cache.Name())
+		origin := "site " + shortID(cache.Name())
+		if index, err := os.ReadFile(filepath.Join(path, "index.txt")); err == nil {
+			if m := cacheStorageOrigin.Find(index); m != nil {
+				scheme, host, _ := strings.Cut(string(m), "://")
+				origin = siteName(scheme, host, "")
+			}
+		}
+		add(label+": "+origin+" Service Worker cache", path)
+	}
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "terraform", Category: "Terraform Artifacts", Risk: types.RiskLow, Sets: []string{SetDev}, Order: 197, Scan: (*Scanner).ScanTerraformArtifacts})
+}
+
+// terraformPluginCache returns the shared provider cache, from
+// TF_PLUGIN_CACHE_DIR if set, otherwise the usual ~/.terraform.d/plugin-cache
+func (s *Scanner) terraformPluginCache() string {
+	if dir := os.Getenv("TF_PLUGIN_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(s.HomeDir, ".terraform.d", "plugin-cache")
+}
+
+// ScanTerraformArtifacts lists the providers in the shared plugin cache, one
+// item per provider version, and the .terraform folders of projects, which
+// hold each project's own copy of its providers and modules. A .terraform
+// folder only counts next to .tf files.
+func (s *Scanner) ScanTerraformArtifacts() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Terraform Artifacts",
+		Items:    []types.FileItem{},
+	}
+
+	// Providers are cached as host/namespace/type/version, e.g.
+	// registry.terraform.io/hashicorp/aws/5.31.0
+	pluginCache := s.terraformPluginCache()
+	s.walkSDKLevels(pluginCache, 4, func(path string, parts []string) {
+		if size := s.dirSize(path); size > 0 {
+			result.Items = append(result.Items, types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  "🟪 Provider cache: " + parts[1] + "/" + parts[2] + " " + parts[3],
+				IsDir: true,
+			})
+			result.Total += size
+		}
+	})
+
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if utils.ShouldSkipDir(path) || d.Name() == "node_modules" || path == filepath.Join(s.HomeDir, ".terraform.d") || path == pluginCache {
+			return filepath.SkipDir
+		}
+		if d.Name() != ".terraform" {
+			return nil
+		}
+
+		projectDir := filepath.Dir(path)
+		if !hasTerraformFiles(projectDir) {
+			return filepath.SkipDir
+		}
+		if size := s.dirSize(path); size > 0 {
+			relPath, _ := filepath.Rel(s.HomeDir, projectDir)
+			result.Items = append(result.Items, types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  "🟪 " + relPath + " (.terraform)",
+				IsDir: true,
+			})
+			result.Total += size
+		}
+		return filepath.SkipDir
+	})
+
+	return result
+}
+
+// hasTerraformFiles reports whether dir holds Terraform configuration
+func hasTerraformFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && (strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")) {
+			return true
+		}
+	}
+	return false
+}
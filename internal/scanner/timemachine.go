@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/space"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "time-machine", Category: "Time Machine Snapshots", Risk: types.RiskMedium, Sets: []string{SetFull}, Order: 75, Scan: (*Scanner).ScanTimeMachineSnapshots})
+}
+
+// tmutilTimeout bounds a single tmutil call
+const tmutilTimeout = 30 * time.Second
+
+// Snapshot items are not files; their paths name the snapshot's date, as
+// `tmutil deletelocalsnapshots` expects it
+const snapshotScheme = "tmsnapshot://"
+
+const snapshotDateLayout = "2006-01-02-150405"
+
+// ScanTimeMachineSnapshots lists the local Time Machine snapshots on the
+// home volume. APFS doesn't report how much each snapshot holds, so the
+// purgeable space on the volume is shared evenly between them as an
+// estimate.
+func (s *Scanner) ScanTimeMachineSnapshots() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Time Machine Snapshots",
+		Items:    []types.FileItem{},
+		Remover:  s.removeSnapshot,
+		Method:   "tmutil deletelocalsnapshots",
+	}
+
+	out, err := s.runTmutil("listlocalsnapshots", s.HomeDir)
+	if err != nil {
+		return result
+	}
+	var snapshots []space.Snapshot
+	for _, snap := range space.ParseSnapshots(string(out)) {
+		if strings.HasPrefix(snap.Name, "com.apple.TimeMachine.") && !snap.Time.IsZero() {
+			snapshots = append(snapshots, snap)
+		}
+	}
+	if len(snapshots) == 0 {
+		return result
+	}
+
+	var each int64
+	if purgeable, err := s.snapshotPurgeable(); err == nil {
+		each = purgeable / int64(len(snapshots))
+	}
+	for _, snap := range snapshots {
+		result.Items = append(result.Items, types.FileItem{
+			Path: snapshotScheme + snap.Time.Format(snapshotDateLayout),
+			Size: each,
+			Name: "🕘 Snapshot: " + snap.Time.Format("2 Jan 2006 15:04"),
+			Age:  int(time.Since(snap.Time).Hours() / 24),
+		})
+		result.Total += each
+	}
+	return result
+}
+
+// runTmutil runs tmutil and returns its standard output
+func (s *Scanner) runTmutil(args ...string) ([]byte, error) {
+	if s.tmutil != nil {
+		return s.tmutil(args...)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tmutilTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "tmutil", args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return nil, fmt.Errorf("tmutil %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("tmutil %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// snapshotPurgeable returns the purgeable space on the home volume
+func (s *Scanner) snapshotPurgeable() (int64, error) {
+	if s.purgeable != nil {
+		return s.purgeable()
+	}
+	return space.Purgeable(s.HomeDir)
+}
+
+// removeSnapshot deletes the local snapshot an item path refers to
+func (s *Scanner) removeSnapshot(path string) error {
+	date := strings.TrimPrefix(path, snapshotScheme)
+	if !strings.HasPrefix(path, snapshotScheme) {
+		return types.NewPathError("remove", path, fmt.Errorf("not a local snapshot"))
+	}
+	if _, err := time.Parse(snapshotDateLayout, date); err != nil {
+		return types.NewPathError("remove", path, fmt.Errorf("invalid snapshot date %q", date))
+	}
+	if _, err := s.runTmutil("deletelocalsnapshots", date); err != nil {
+		return types.NewPathError("remove", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "vagrant", Category: "Vagrant Boxes", Risk: types.RiskMedium, Sets: []string{SetDev}, Order: 198, Scan: (*Scanner).ScanVagrantBoxes})
+}
+
+// vagrantTimeout bounds a single vagrant call; vagrant is slow to start
+const vagrantTimeout = 2 * time.Minute
+
+// errNoVagrant is returned by runVagrant when vagrant is not installed
+var errNoVagrant = errors.New("vagrant is not installed")
+
+// vagrantBox is one provider's copy of a box version, as vagrant box
+// remove takes it
+type vagrantBox struct {
+	name, version, provider, arch string
+}
+
+// runVagrant runs vagrant and returns its combined output
+func (s *Scanner) runVagrant(args ...string) ([]byte, error) {
+	if s.vagrant != nil {
+		return s.vagrant(args...)
+	}
+	if _, err := exec.LookPath("vagrant"); err != nil {
+		return nil, errNoVagrant
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), vagrantTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "vagrant", args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return nil, fmt.Errorf("vagrant %s: %w: %s", strings.Join(args[:min(2, len(args))], " "), err, msg)
+	}
+	return out, nil
+}
+
+// ScanVagrantBoxes lists the boxes in VAGRANT_HOME, ~/.vagrant.d by
+// default, one item per box version and provider. They are removed with
+// `vagrant box remove`, which refuses boxes a machine still uses, or
+// deleted when vagrant is gone.
+func (s *Scanner) ScanVagrantBoxes() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Vagrant Boxes",
+		Items:    []types.FileItem{},
+		Method:   "vagrant box remove",
+	}
+
+	home := os.Getenv("VAGRANT_HOME")
+	if home == "" {
+		home = filepath.Join(s.HomeDir, ".vagrant.d")
+	}
+	boxesDir := filepath.Join(home, "boxes")
+	boxes := make(map[string]vagrantBox)
+	add := func(path string, box vagrantBox) {
+		size := s.dirSize(path)
+		if size == 0 {
+			return
+		}
+		label := fmt.Sprintf("📦 Vagrant box: %s %s (%s", box.name, box.version, box.provider)
+		if box.arch != "" {
+			label += ", " + box.arch
+		}
+		label += ")"
+		item := types.FileItem{Path: path, Size: size, Name: label, IsDir: true}
+		if info, err := os.Stat(path); err == nil {
+			item.Age = int(time.Since(info.ModTime()).Hours() / 24)
+		}
+		result.Items = append(result.Items, item)
+		result.Total += size
+		boxes[path] = box
+	}
+
+	// Boxes are kept as name/version/provider, or name/version/arch/provider
+	// since boxes can be built for several architectures
+	s.walkSDKLevels(boxesDir, 3, func(path string, parts []string) {
+		box := vagrantBox{name: vagrantBoxName(parts[0]), version: parts[1], provider: parts[2]}
+		if _, err := os.Stat(filepath.Join(path, "metadata.json")); err == nil {
+			add(path, box)
+			return
+		}
+		entries, err := s.readDir(path)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				add(filepath.Join(path, entry.Name()), vagrantBox{name: box.name, version: box.version, provider: entry.Name(), arch: parts[2]})
+			}
+		}
+	})
+
+	result.Remover = func(path string) error {
+		box, ok := boxes[path]
+		if !ok {
+			return s.deleter().Remove(path)
+		}
+		args := []string{"box", "remove", box.name, "--box-version", box.version, "--provider", box.provider}
+		if box.arch != "" {
+			args = append(args, "--architecture", box.arch)
+		}
+		if _, err := s.runVagrant(args...); err != nil {
+			if errors.Is(err, errNoVagrant) {
+				return s.deleter().Remove(path)
+			}
+			return types.NewPathError("remove", path, err)
+		}
+		return nil
+	}
+	return result
+}
+
+// vagrantBoxName undoes the escaping of box directory names, e.g.
+// hashicorp-VAGRANTSLASH-bionic64 for hashicorp/bionic64
+func vagrantBoxName(dir string) string {
+	return strings.NewReplacer("-VAGRANTSLASH-", "/", "-VAGRANTCOLON-", ":").Replace(dir)
+}
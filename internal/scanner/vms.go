@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func init() {
+	Register(Registration{Name: "vms", Category: "Virtual Machines", Risk: types.RiskHigh, Sets: []string{SetFull}, Order: 77, Scan: (*Scanner).ScanVirtualMachines})
+}
+
+// ScanVirtualMachines reports the virtual machines of UTM, Parallels
+// Desktop and VMware Fusion. It is advisory: a VM holds a whole system
+// whose contents only its owner can judge.
+func (s *Scanner) ScanVirtualMachines() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Virtual Machines",
+		Items:    []types.FileItem{},
+		Advisory: true,
+		Hint:     "Check what each machine holds before deleting it from UTM, Parallels Desktop or VMware Fusion; removing old snapshots in the app also frees space.",
+	}
+
+	locations := []struct {
+		dir  string
+		ext  string
+		tool string
+	}{
+		{filepath.Join(s.HomeDir, "Library", "Containers", "com.utmapp.UTM", "Data", "Documents"), ".utm", "UTM"},
+		{filepath.Join(s.HomeDir, "Parallels"), ".pvm", "Parallels"},
+		{filepath.Join(s.HomeDir, "Virtual Machines.localized"), ".vmwarevm", "VMware"},
+		{filepath.Join(s.HomeDir, "Documents", "Virtual Machines.localized"), ".vmwarevm", "VMware"},
+	}
+	for _, loc := range locations {
+		entries, err := s.readDir(loc.dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(loc.dir, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), loc.ext)
+			if !ok || !entry.IsDir() {
+				continue
+			}
+			s.addVMDisk(result, filepath.Join(loc.dir, entry.Name()), "🖥️ "+loc.tool+": "+name+" (manual review required)")
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,327 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+func init() {
+	Register(Registration{Name: "device-support", Category: "Xcode Device Support", Risk: types.RiskLow, Sets: []string{SetFull, SetDev}, Order: 85, Scan: (*Scanner).ScanDeviceSupport})
+	Register(Registration{Name: "xcode-installs", Category: "Xcode Installations", Risk: types.RiskHigh, Sets: []string{SetDev}, Order: 210, Scan: (*Scanner).ScanXcodeInstalls})
+}
+
+// ScanXcodeInstalls finds extra copies of Xcode in /Applications, such as
+// Xcode-15.2.app or Xcode-beta.app. The copy selected with xcode-select is
+// never listed, and nothing is reported when only one copy is installed.
+func (s *Scanner) ScanXcodeInstalls() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Xcode Installations",
+		Items:    []types.FileItem{},
+	}
+
+	appsDir := s.systemPath("Applications")
+	entries, err := s.readDir(appsDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.AddError(appsDir, err)
+		}
+		return result
+	}
+
+	var apps []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && strings.HasPrefix(name, "Xcode") && strings.HasSuffix(name, ".app") {
+			apps = append(apps, name)
+		}
+	}
+	if len(apps) < 2 {
+		return result
+	}
+
+	active := s.activeXcode()
+	required := s.requiredXcodeVersions(result)
+
+	for _, name := range apps {
+		if name == active {
+			continue
+		}
+
+		path := filepath.Join(appsDir, name)
+		version := readPlistString(filepath.Join(path, "Contents", "version.plist"), "CFBundleShortVersionString")
+
+		label := fmt.Sprintf("🛠 %s", name)
+		if version != "" {
+			label += " (" + version
+			if projects := required[version]; len(projects) > 0 {
+				label += ", required by " + strings.Join(projects, ", ")
+			}
+			label += ")"
+		}
+
+		size := s.dirSize(path)
+		if size > 0 {
+			result.Items = append(result.Items, types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  label,
+				IsDir: true,
+			})
+			result.Total += size
+		}
+	}
+
+	return result
+}
+
+// deviceSupportPlatforms are the platforms Xcode copies debug symbols for,
+// each into ~/Library/Developer/Xcode/<platform> DeviceSupport
+var deviceSupportPlatforms = []string{"iOS", "watchOS", "tvOS", "visionOS"}
+
+// ScanDeviceSupport lists the debug symbols Xcode copied from each OS
+// version of the devices it connected to, one folder per version, e.g.
+// "17.2 (21C62)"
+func (s *Scanner) ScanDeviceSupport() *types.ScanResult {
+	result := &types.ScanResult{
+		Category: "Xcode Device Support",
+		Items:    []types.FileItem{},
+	}
+
+	for _, platform := range deviceSupportPlatforms {
+		dir := filepath.Join(s.HomeDir, "Library", "Developer", "Xcode", platform+" DeviceSupport")
+		entries, err := s.readDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				result.AddError(dir, err)
+			}
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			size := s.dirSize(path)
+			if size == 0 {
+				continue
+			}
+			item := types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  fmt.Sprintf("📱 %s %s", platform, entry.Name()),
+				IsDir: true,
+			}
+			if info, err := entry.Info(); err == nil {
+				item.Age = int(time.Since(info.ModTime()).Hours() / 24)
+			}
+			result.Items = append(result.Items, item)
+			result.Total += size
+		}
+	}
+	return result
+}
+
+// scanDerivedData adds each project's folder in derivedDir to result, named
+// after the project or workspace it was built from, with the last build
+// time as its age
+func (s *Scanner) scanDerivedData(result *types.ScanResult, derivedDir string) {
+	entries, err := s.readDir(derivedDir)
+	if err != nil {
+		result.AddError(derivedDir, err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(derivedDir, entry.Name())
+		size := s.dirSize(path)
+		if size == 0 {
+			continue
+		}
+
+		// Folders are named <project>-<hash>
+		project := entry.Name()
+		if i := strings.LastIndexByte(project, '-'); i > 0 {
+			project = project[:i]
+		}
+		var gone bool
+		info := filepath.Join(path, "info.plist")
+		if workspace := readPlistString(info, "WorkspacePath"); workspace != "" {
+			project = workspace
+			if rel, err := filepath.Rel(s.HomeDir, workspace); err == nil && !strings.HasPrefix(rel, "..") {
+				project = rel
+			}
+			_, err := os.Stat(workspace)
+			gone = os.IsNotExist(err)
+		}
+
+		built, err := time.Parse(time.RFC3339, readPlistValue(info, "LastAccessedDate", "date"))
+		if err != nil {
+			if fi, err := entry.Info(); err == nil {
+				built = fi.ModTime()
+			}
+		}
+
+		item := types.FileItem{Path: path, Size: size, Name: "🔨 " + project, IsDir: true}
+		var notes []string
+		if !built.IsZero() {
+			item.Age = int(time.Since(built).Hours() / 24)
+			notes = append(notes, "built "+built.Local().Format("2 Jan 2006"))
+		}
+		if gone {
+			notes = append(notes, "project deleted")
+		}
+		if len(notes) > 0 {
+			item.Name += " (" + strings.Join(notes, ", ") + ")"
+		}
+		result.Items = append(result.Items, item)
+		result.Total += size
+	}
+}
+
+// scanArchives adds each .xcarchive bundle in archivesDir to result. Xcode
+// keeps them in one folder per day, e.g. Archives/2024-03-09.
+func (s *Scanner) scanArchives(result *types.ScanResult, archivesDir string) {
+	days, err := s.readDir(archivesDir)
+	if err != nil {
+		result.AddError(archivesDir, err)
+		return
+	}
+	for _, day := range days {
+		if !day.IsDir() {
+			continue
+		}
+		dayDir := filepath.Join(archivesDir, day.Name())
+		entries, err := s.readDir(dayDir)
+		if err != nil {
+			result.AddError(dayDir, err)
+			continue
+		}
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".xcarchive") {
+				continue
+			}
+			path := filepath.Join(dayDir, entry.Name())
+			size := s.dirSize(path)
+			if size == 0 {
+				continue
+			}
+			result.Items = append(result.Items, s.archiveItem(path, entry, size))
+			result.Total += size
+		}
+	}
+}
+
+// archiveItem describes an archive by the app it holds and when it was
+// created, from its Info.plist, falling back to the bundle's name and
+// modification time
+func (s *Scanner) archiveItem(path string, entry os.DirEntry, size int64) types.FileItem {
+	info := filepath.Join(path, "Info.plist")
+	name := readPlistString(info, "Name")
+	if name == "" {
+		name = strings.TrimSuffix(entry.Name(), ".xcarchive")
+	}
+	if version := readPlistString(info, "CFBundleShortVersionString"); version != "" {
+		name += " " + version
+	}
+
+	created, err := time.Parse(time.RFC3339, readPlistValue(info, "CreationDate", "date"))
+	if err != nil {
+		if fi, err := entry.Info(); err == nil {
+			created = fi.ModTime()
+		}
+	}
+	item := types.FileItem{Path: path, Size: size, Name: "📦 Archive: " + name, IsDir: true}
+	if !created.IsZero() {
+		item.Name += " (" + created.Local().Format("2 Jan 2006") + ")"
+		item.Age = int(time.Since(created).Hours() / 24)
+	}
+	return item
+}
+
+// activeXcode returns the name of the Xcode bundle in use: the one
+// DEVELOPER_DIR or xcode-select points at, or Xcode.app, which the tools
+// fall back to when neither names an app, e.g. when only the Command Line
+// Tools are selected
+func (s *Scanner) activeXcode() string {
+	targets := []string{os.Getenv("DEVELOPER_DIR")}
+	if target, err := os.Readlink(s.systemPath("var", "db", "xcode_select_link")); err == nil {
+		targets = append(targets, target)
+	}
+	for _, target := range targets {
+		// Both point at <app>/Contents/Developer
+		for dir := filepath.Clean(target); dir != "/" && dir != "."; dir = filepath.Dir(dir) {
+			if strings.HasSuffix(dir, ".app") {
+				return filepath.Base(dir)
+			}
+		}
+	}
+	return "Xcode.app"
+}
+
+// requiredXcodeVersions maps Xcode versions pinned in .xcode-version files
+// to the projects that pin them, relative to the home directory
+func (s *Scanner) requiredXcodeVersions(result *types.ScanResult) map[string][]string {
+	required := make(map[string][]string)
+
+	s.walkDir(s.HomeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.AddError(path, err)
+			return nil
+		}
+
+		if d.IsDir() {
+			if utils.ShouldSkipDir(path) || d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() == ".xcode-version" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			version := strings.TrimSpace(string(data))
+			project, _ := filepath.Rel(s.HomeDir, filepath.Dir(path))
+			required[version] = append(required[version], project)
+		}
+		return nil
+	})
+
+	for _, projects := range required {
+		sort.Strings(projects)
+	}
+	return required
+}
+
+// readPlistString returns the string value of key in an XML property list,
+// or "" if it can't be read
+func readPlistString(path, key string) string {
+	return readPlistValue(path, key, "string")
+}
+
+// readPlistValue returns the first value of key in an XML property list
+// with the given type, such as "string" or "date", or "" if there is none
+func readPlistValue(path, key, kind string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	re := regexp.MustCompile(`<key>` + regexp.QuoteMeta(key) + `</key>\s*<` + kind + `>([^<]*)</` + kind + `>`)
+	if m := re.FindSubmatch(data); m != nil {
+		return strings.TrimSpace(string(m[1]))
+	}
+	return ""
+}
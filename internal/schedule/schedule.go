@@ -0,0 +1,233 @@
+// Package schedule installs a launchd agent that runs a headless clean on
+// a calendar schedule, e.g. a weekly Quick Clean
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Label identifies the launchd agent
+const Label = "com.github.rahulvramesh.cleanwithcli"
+
+// Intervals between runs
+const (
+	Daily  = "daily"
+	Weekly = "weekly"
+)
+
+// modes are the scans the clean subcommand runs
+var modes = map[string]bool{"quick": true, "full": true, "dev": true}
+
+// Config describes when the scheduled clean runs and what it cleans
+type Config struct {
+	Mode     string       `json:"mode"`     // Scan mode of the clean, e.g. "quick"
+	Interval string       `json:"interval"` // Daily or Weekly
+	Weekday  time.Weekday `json:"weekday"`  // Day of weekly runs
+	Hour     int          `json:"hour"`
+	Minute   int          `json:"minute"`
+	Include  []string     `json:"include,omitempty"` // Medium-risk scanners also cleaned, see the clean subcommand
+}
+
+// Validate checks the config describes a possible schedule
+func (c Config) Validate() error {
+	switch {
+	case c.Interval != Daily && c.Interval != Weekly:
+		return fmt.Errorf("interval must be %s or %s, not %q", Daily, Weekly, c.Interval)
+	case c.Weekday < time.Sunday || c.Weekday > time.Saturday:
+		return fmt.Errorf("invalid weekday %d", c.Weekday)
+	case c.Hour < 0 || c.Hour > 23 || c.Minute < 0 || c.Minute > 59:
+		return fmt.Errorf("invalid time %02d:%02d", c.Hour, c.Minute)
+	case !modes[c.Mode]:
+		return fmt.Errorf("mode must be quick, full or dev, not %q", c.Mode)
+	}
+	return nil
+}
+
+// String describes the schedule, e.g. "quick clean weekly on Monday at 10:00"
+func (c Config) String() string {
+	when := fmt.Sprintf("daily at %02d:%02d", c.Hour, c.Minute)
+	if c.Interval == Weekly {
+		when = fmt.Sprintf("weekly on %s at %02d:%02d", c.Weekday, c.Hour, c.Minute)
+	}
+	return c.Mode + " clean " + when
+}
+
+// Next returns the first run after now
+func (c Config) Next(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), c.Hour, c.Minute, 0, 0, now.Location())
+	if c.Interval == Weekly {
+		next = next.AddDate(0, 0, (int(c.Weekday)-int(now.Weekday())+7)%7)
+	}
+	for !next.After(now) {
+		if c.Interval == Weekly {
+			next = next.AddDate(0, 0, 7)
+		} else {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+// DefaultPath returns where the installed schedule is recorded
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "cleanwithcli", "schedule.json")
+}
+
+// AgentPath returns the launchd agent's plist path
+func AgentPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", Label+".plist")
+}
+
+// LogPath returns where scheduled runs write their output
+func LogPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "Library", "Logs", "cleanwithcli", "schedule.log")
+}
+
+// Load reads the installed schedule at path. Nothing is installed when the
+// file is missing.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Plist returns the launchd agent definition running exe's clean
+// subcommand on c's schedule
+func Plist(c Config, exe, logPath string) []byte {
+	var b bytes.Buffer
+	str := func(s string) string {
+		var e bytes.Buffer
+		xml.EscapeText(&e, []byte(s))
+		return "<string>" + e.String() + "</string>"
+	}
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t%s\n", str(Label))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	args := []string{exe, "clean", "-mode", c.Mode}
+	if len(c.Include) > 0 {
+		args = append(args, "-include", strings.Join(c.Include, ","))
+	}
+	for _, arg := range args {
+		fmt.Fprintf(&b, "\t\t%s\n", str(arg))
+	}
+	b.WriteString("\t</array>\n")
+	b.WriteString("\t<key>StartCalendarInterval</key>\n\t<dict>\n")
+	if c.Interval == Weekly {
+		fmt.Fprintf(&b, "\t\t<key>Weekday</key>\n\t\t<integer>%d</integer>\n", c.Weekday)
+	}
+	fmt.Fprintf(&b, "\t\t<key>Hour</key>\n\t\t<integer>%d</integer>\n", c.Hour)
+	fmt.Fprintf(&b, "\t\t<key>Minute</key>\n\t\t<integer>%d</integer>\n", c.Minute)
+	b.WriteString("\t</dict>\n")
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t%s\n", str(logPath))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t%s\n", str(logPath))
+	b.WriteString("\t<key>LowPriorityIO</key>\n\t<true/>\n")
+	b.WriteString("\t<key>ProcessType</key>\n\t<string>Background</string>\n")
+	b.WriteString("</dict>\n</plist>\n")
+	return b.Bytes()
+}
+
+// launchctl runs launchctl with args
+func launchctl(args ...string) error {
+	out, err := exec.Command("launchctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("launchctl %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func domain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+// Install writes the agent for c, replacing any installed schedule, and
+// loads it into launchd
+func Install(c Config, exe string) error {
+	if runtime.GOOS != "darwin" {
+		return fmt.Errorf("scheduling needs launchd, which is only available on macOS")
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	logPath := LogPath()
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return err
+	}
+	agent := AgentPath()
+	if err := os.MkdirAll(filepath.Dir(agent), 0o755); err != nil {
+		return err
+	}
+
+	// Unload the previous agent first; it is fine if there was none
+	launchctl("bootout", domain(), agent)
+	if err := os.WriteFile(agent, Plist(c, exe, logPath), 0o644); err != nil {
+		return err
+	}
+	if err := launchctl("bootstrap", domain(), agent); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := DefaultPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Uninstall unloads the agent and removes its files
+func Uninstall() error {
+	agent := AgentPath()
+	if _, err := os.Stat(agent); os.IsNotExist(err) {
+		os.Remove(DefaultPath())
+		return fmt.Errorf("no scheduled clean is installed")
+	}
+	if runtime.GOOS == "darwin" {
+		launchctl("bootout", domain(), agent)
+	}
+	if err := os.Remove(agent); err != nil {
+		return err
+	}
+	if err := os.Remove(DefaultPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
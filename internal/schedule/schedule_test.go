@@ -0,0 +1,90 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2025, 3, 12, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		c    Config
+		want time.Time
+	}{
+		{"daily later today", Config{Interval: Daily, Hour: 10}, time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC)},
+		{"daily tomorrow", Config{Interval: Daily, Hour: 9, Minute: 30}, time.Date(2025, 3, 13, 9, 30, 0, 0, time.UTC)},
+		{"weekly this week", Config{Interval: Weekly, Weekday: time.Friday, Hour: 8}, time.Date(2025, 3, 14, 8, 0, 0, 0, time.UTC)},
+		{"weekly next week", Config{Interval: Weekly, Weekday: time.Monday, Hour: 10}, time.Date(2025, 3, 17, 10, 0, 0, 0, time.UTC)},
+		{"weekly today already run", Config{Interval: Weekly, Weekday: time.Wednesday, Hour: 8}, time.Date(2025, 3, 19, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Next(now); !got.Equal(tt.want) {
+			t.Errorf("%s: Next = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlist(t *testing.T) {
+	c := Config{Mode: "quick", Interval: Weekly, Weekday: time.Monday, Hour: 10, Minute: 5}
+	plist := string(Plist(c, "/opt/mac cleaner & co/mac-cleaner", "/tmp/schedule.log"))
+
+	for _, want := range []string{
+		"<string>" + Label + "</string>",
+		"<string>/opt/mac cleaner &amp; co/mac-cleaner</string>\n\t\t<string>clean</string>\n\t\t<string>-mode</string>\n\t\t<string>quick</string>",
+		"<key>Weekday</key>\n\t\t<integer>1</integer>",
+		"<key>Hour</key>\n\t\t<integer>10</integer>",
+		"<key>Minute</key>\n\t\t<integer>5</integer>",
+		"<key>StandardOutPath</key>\n\t<string>/tmp/schedule.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist missing %q:\n%s", want, plist)
+		}
+	}
+
+	if strings.Contains(plist, "-include") {
+		t.Errorf("plist includes medium-risk scanners nobody chose:\n%s", plist)
+	}
+	c.Include = []string{"node-modules", "rust"}
+	if plist := string(Plist(c, "mac-cleaner", "log")); !strings.Contains(plist, "<string>-include</string>\n\t\t<string>node-modules,rust</string>") {
+		t.Errorf("plist missing the included scanners:\n%s", plist)
+	}
+
+	c.Interval = Daily
+	if plist := string(Plist(c, "mac-cleaner", "log")); strings.Contains(plist, "Weekday") {
+		t.Errorf("daily plist has a weekday:\n%s", plist)
+	}
+}
+
+func TestValidateAndLoad(t *testing.T) {
+	if err := (Config{Mode: "quick", Interval: "hourly"}).Validate(); err == nil {
+		t.Error("hourly interval accepted")
+	}
+	if err := (Config{Mode: "quick", Interval: Daily, Hour: 24}).Validate(); err == nil {
+		t.Error("hour 24 accepted")
+	}
+	if err := (Config{Mode: "bogus", Interval: Daily}).Validate(); err == nil {
+		t.Error("unknown mode accepted")
+	}
+	if err := (Config{Mode: "dev", Interval: Daily}).Validate(); err != nil {
+		t.Errorf("dev mode rejected: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	if c, err := Load(path); c != nil || err != nil {
+		t.Fatalf("Load(missing) = %v, %v", c, err)
+	}
+	os.WriteFile(path, []byte(`{"mode":"quick","interval":"weekly","weekday":0,"hour":22,"minute":0}`), 0o644)
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.String(); got != "quick clean weekly on Sunday at 22:00" {
+		t.Errorf("String = %q", got)
+	}
+}
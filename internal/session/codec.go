@@ -0,0 +1,226 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// Event is one recorded message and when it arrived, relative to the start
+// of the session
+type Event struct {
+	At   time.Duration   `json:"at"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// recordedError is the serialized form of an error, keeping its kind so the
+// UI can still show the matching recovery hint on replay
+type recordedError struct {
+	Op      string `json:"op,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Message string `json:"message"`
+}
+
+// Wire forms of messages that carry errors
+type scanResultData struct {
+	Category string           `json:"category"`
+	Items    []types.FileItem `json:"items"`
+	Total    int64            `json:"total"`
+	Errors   []recordedError  `json:"errors,omitempty"`
+	Advisory bool             `json:"advisory,omitempty"`
+	Hint     string           `json:"hint,omitempty"`
+	Method   string           `json:"method,omitempty"`
+}
+
+type scanCompleteData struct {
+	Results   map[string]scanResultData `json:"results"`
+	TotalSize int64                     `json:"total_size"`
+	Stats     *types.ScanStats          `json:"stats,omitempty"`
+}
+
+type cleanCompleteData struct {
+	Freed       int64          `json:"freed"`
+	Path        string         `json:"path"`
+	Err         *recordedError `json:"err,omitempty"`
+	VolumeFreed int64          `json:"volume_freed,omitempty"`
+	Measured    bool           `json:"measured,omitempty"`
+	Report      string         `json:"report,omitempty"`
+}
+
+type batchCleanCompleteData struct {
+	Freed       int64           `json:"freed"`
+	Paths       []string        `json:"paths"`
+	Errors      []recordedError `json:"errors,omitempty"`
+	VolumeFreed int64           `json:"volume_freed,omitempty"`
+	Measured    bool            `json:"measured,omitempty"`
+}
+
+var errorKinds = map[string]error{
+	"permission": types.ErrPermission,
+	"not_found":  types.ErrNotFound,
+	"in_use":     types.ErrInUse,
+	"protected":  types.ErrProtectedPath,
+}
+
+func encodeError(err error) recordedError {
+	rec := recordedError{Message: err.Error()}
+	var pathErr *types.PathError
+	if errors.As(err, &pathErr) {
+		rec.Op = pathErr.Op
+		rec.Path = pathErr.Path
+		rec.Message = pathErr.Err.Error()
+		for name, kind := range errorKinds {
+			if pathErr.Kind == kind {
+				rec.Kind = name
+			}
+		}
+	}
+	return rec
+}
+
+func decodeError(rec recordedError) error {
+	if rec.Op == "" {
+		return errors.New(rec.Message)
+	}
+	return &types.PathError{
+		Op:   rec.Op,
+		Path: rec.Path,
+		Kind: errorKinds[rec.Kind],
+		Err:  errors.New(rec.Message),
+	}
+}
+
+// encode converts a message into its recorded type and payload, reporting
+// false for messages that are not part of a session recording
+func encode(msg tea.Msg) (string, any, bool) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return "key", tea.Key(msg), true
+	case types.ScanProgressMsg:
+		return "scan_progress", msg, true
+	case types.ScanCompleteMsg:
+		data := scanCompleteData{
+			Results:   make(map[string]scanResultData, len(msg.Results)),
+			TotalSize: msg.TotalSize,
+			Stats:     msg.Stats,
+		}
+		for name, result := range msg.Results {
+			rd := scanResultData{
+				Category: result.Category,
+				Items:    result.Items,
+				Total:    result.Total,
+				Advisory: result.Advisory,
+				Hint:     result.Hint,
+				Method:   result.Method,
+			}
+			for _, err := range result.Errors {
+				rd.Errors = append(rd.Errors, encodeError(err))
+			}
+			data.Results[name] = rd
+		}
+		return "scan_complete", data, true
+	case types.CleanCompleteMsg:
+		data := cleanCompleteData{Freed: msg.Freed, Path: msg.Path, VolumeFreed: msg.VolumeFreed, Measured: msg.Measured, Report: msg.Report}
+		if msg.Err != nil {
+			rec := encodeError(msg.Err)
+			data.Err = &rec
+		}
+		return "clean_complete", data, true
+	case types.BatchCleanCompleteMsg:
+		data := batchCleanCompleteData{Freed: msg.Freed, Paths: msg.Paths, VolumeFreed: msg.VolumeFreed, Measured: msg.Measured}
+		for _, err := range msg.Errors {
+			data.Errors = append(data.Errors, encodeError(err))
+		}
+		return "batch_clean_complete", data, true
+	case types.DirectoryListingMsg:
+		return "directory_listing", msg, true
+	case types.PreviewMsg:
+		return "preview", msg, true
+	case types.ErrMsg:
+		return "error", encodeError(msg.Err), true
+	}
+	return "", nil, false
+}
+
+// decode rebuilds a message from a recorded event
+func decode(ev Event) (tea.Msg, error) {
+	switch ev.Type {
+	case "key":
+		var key tea.Key
+		err := json.Unmarshal(ev.Data, &key)
+		return tea.KeyMsg(key), err
+	case "scan_progress":
+		var msg types.ScanProgressMsg
+		err := json.Unmarshal(ev.Data, &msg)
+		return msg, err
+	case "scan_complete":
+		var data scanCompleteData
+		if err := json.Unmarshal(ev.Data, &data); err != nil {
+			return nil, err
+		}
+		msg := types.ScanCompleteMsg{
+			Results:   make(map[string]*types.ScanResult, len(data.Results)),
+			TotalSize: data.TotalSize,
+			Stats:     data.Stats,
+		}
+		for name, rd := range data.Results {
+			result := &types.ScanResult{
+				Category: rd.Category,
+				Items:    rd.Items,
+				Total:    rd.Total,
+				Advisory: rd.Advisory,
+				Hint:     rd.Hint,
+				Method:   rd.Method,
+			}
+			for _, rec := range rd.Errors {
+				if pathErr, ok := decodeError(rec).(*types.PathError); ok {
+					result.Errors = append(result.Errors, pathErr)
+				}
+			}
+			msg.Results[name] = result
+		}
+		return msg, nil
+	case "clean_complete":
+		var data cleanCompleteData
+		if err := json.Unmarshal(ev.Data, &data); err != nil {
+			return nil, err
+		}
+		msg := types.CleanCompleteMsg{Freed: data.Freed, Path: data.Path, VolumeFreed: data.VolumeFreed, Measured: data.Measured, Report: data.Report}
+		if data.Err != nil {
+			msg.Err = decodeError(*data.Err)
+		}
+		return msg, nil
+	case "batch_clean_complete":
+		var data batchCleanCompleteData
+		if err := json.Unmarshal(ev.Data, &data); err != nil {
+			return nil, err
+		}
+		msg := types.BatchCleanCompleteMsg{Freed: data.Freed, Paths: data.Paths, VolumeFreed: data.VolumeFreed, Measured: data.Measured}
+		for _, rec := range data.Errors {
+			msg.Errors = append(msg.Errors, decodeError(rec))
+		}
+		return msg, nil
+	case "directory_listing":
+		var msg types.DirectoryListingMsg
+		err := json.Unmarshal(ev.Data, &msg)
+		return msg, err
+	case "preview":
+		var msg types.PreviewMsg
+		err := json.Unmarshal(ev.Data, &msg)
+		return msg, err
+	case "error":
+		var rec recordedError
+		if err := json.Unmarshal(ev.Data, &rec); err != nil {
+			return nil, err
+		}
+		return types.ErrMsg{Err: decodeError(rec)}, nil
+	}
+	return nil, fmt.Errorf("unknown event type %q", ev.Type)
+}
@@ -0,0 +1,96 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// replayMsg triggers delivery of the next recorded event
+type replayMsg struct{ index int }
+
+// Player replays a recorded session into a model. Commands returned by the
+// model are discarded, so nothing is scanned or deleted during a replay.
+type Player struct {
+	model  tea.Model
+	events []tea.Msg
+	delays []time.Duration
+	done   bool
+}
+
+// Load reads a session file written by a Recorder and prepares it for replay
+// into m
+func Load(m tea.Model, r io.Reader) (*Player, error) {
+	p := &Player{model: m}
+
+	var last time.Duration
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("session line %d: %w", line, err)
+		}
+		msg, err := decode(ev)
+		if err != nil {
+			return nil, fmt.Errorf("session line %d: %w", line, err)
+		}
+		p.events = append(p.events, msg)
+		p.delays = append(p.delays, max(0, ev.At-last))
+		last = ev.At
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Init schedules the first recorded event
+func (p *Player) Init() tea.Cmd {
+	p.model.Init()
+	return p.next(0)
+}
+
+// next schedules delivery of event i after its recorded delay
+func (p *Player) next(i int) tea.Cmd {
+	if i >= len(p.events) {
+		p.done = true
+		return nil
+	}
+	return tea.Tick(p.delays[i], func(time.Time) tea.Msg {
+		return replayMsg{index: i}
+	})
+}
+
+// Update feeds recorded events to the model; live keys only quit the replay
+func (p *Player) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case replayMsg:
+		p.model, _ = p.model.Update(p.events[msg.index])
+		return p, p.next(msg.index + 1)
+
+	case tea.WindowSizeMsg:
+		p.model, _ = p.model.Update(msg)
+		return p, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return p, tea.Quit
+		}
+	}
+	return p, nil
+}
+
+// View renders the model with a replay banner
+func (p *Player) View() string {
+	banner := "▶ Replaying session • q to quit"
+	if p.done {
+		banner = "■ Replay finished • q to quit"
+	}
+	return p.model.View() + banner + "\n"
+}
@@ -0,0 +1,50 @@
+package session
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Recorder wraps a model and writes every recordable message it receives to
+// a JSON-lines session file
+type Recorder struct {
+	model tea.Model
+	enc   *json.Encoder
+	start time.Time
+}
+
+// NewRecorder returns a model that records the session of m to w
+func NewRecorder(m tea.Model, w io.Writer) *Recorder {
+	return &Recorder{
+		model: m,
+		enc:   json.NewEncoder(w),
+		start: time.Now(),
+	}
+}
+
+// Init starts the wrapped model
+func (r *Recorder) Init() tea.Cmd {
+	return r.model.Init()
+}
+
+// Update records msg and passes it on to the wrapped model
+func (r *Recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if typ, payload, ok := encode(msg); ok {
+		if data, err := json.Marshal(payload); err == nil {
+			// A failed write must not interrupt the session itself
+			r.enc.Encode(Event{At: time.Since(r.start), Type: typ, Data: data})
+		}
+	}
+
+	var cmd tea.Cmd
+	r.model, cmd = r.model.Update(msg)
+	return r, cmd
+}
+
+// View renders the wrapped model
+func (r *Recorder) View() string {
+	return r.model.View()
+}
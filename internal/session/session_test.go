@@ -0,0 +1,81 @@
+package session
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// stubModel collects the messages it receives
+type stubModel struct {
+	msgs []tea.Msg
+}
+
+func (s *stubModel) Init() tea.Cmd { return nil }
+
+func (s *stubModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	s.msgs = append(s.msgs, msg)
+	return s, nil
+}
+
+func (s *stubModel) View() string { return "" }
+
+func TestRecordAndLoadRoundTrip(t *testing.T) {
+	denied := types.NewPathError("remove", "/Users/dev/locked", errors.New("operation not permitted"))
+	denied.Kind = types.ErrPermission
+
+	msgs := []tea.Msg{
+		tea.KeyMsg{Type: tea.KeyEnter},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")},
+		types.ScanCompleteMsg{
+			Results: map[string]*types.ScanResult{
+				"Trash": {
+					Category: "Trash",
+					Items:    []types.FileItem{{Path: "/Users/dev/.Trash/a", Name: "a", Size: 10}},
+					Total:    10,
+				},
+			},
+			TotalSize: 10,
+		},
+		types.CleanCompleteMsg{Path: "/Users/dev/locked", Err: denied},
+		types.CleanCompleteMsg{Path: "/Users/dev/.npm", Freed: 700, Report: "npm reclaimed 700 B (4 entries cleared, 0 unused garbage-collected)"},
+	}
+
+	var buf bytes.Buffer
+	rec := NewRecorder(&stubModel{}, &buf)
+	for _, msg := range msgs {
+		rec.Update(msg)
+	}
+	// Messages outside the session format are not recorded
+	rec.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	player, err := Load(&stubModel{}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(player.events) != len(msgs) {
+		t.Fatalf("loaded %d events, want %d", len(player.events), len(msgs))
+	}
+
+	for i, want := range msgs[:3] {
+		if got := player.events[i]; !reflect.DeepEqual(got, want) {
+			t.Errorf("event %d = %#v, want %#v", i, got, want)
+		}
+	}
+
+	clean, ok := player.events[3].(types.CleanCompleteMsg)
+	if !ok {
+		t.Fatalf("event 3 = %T, want CleanCompleteMsg", player.events[3])
+	}
+	if !errors.Is(clean.Err, types.ErrPermission) {
+		t.Errorf("replayed error %v lost its kind", clean.Err)
+	}
+	if got, want := player.events[4], msgs[4]; !reflect.DeepEqual(got, want) {
+		t.Errorf("event 4 = %#v, want %#v", got, want)
+	}
+}
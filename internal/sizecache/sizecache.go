@@ -0,0 +1,175 @@
+// Package sizecache remembers directory sizes between runs, so a scan can
+// reuse sizes measured recently, e.g. by the daemon while the machine was
+// idle, instead of walking every directory again
+package sizecache
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// MaxAge is how long a measured size is trusted
+const MaxAge = time.Hour
+
+// forgetAfter drops directories that haven't been measured for a week
+const forgetAfter = 7 * 24 * time.Hour
+
+type entry struct {
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"` // Latest of the directories in the tree when measured
+	Measured time.Time `json:"measured"`
+}
+
+// Cache maps directories to their measured sizes. A nil Cache measures
+// every time.
+type Cache struct {
+	path    string
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// DefaultPath returns the default location of the size cache
+func DefaultPath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "cleanwithcli", "sizes.json")
+}
+
+// Open loads the cache at path. A missing or unreadable file starts empty.
+func Open(path string) *Cache {
+	c := &Cache{path: path, now: time.Now, entries: make(map[string]entry)}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &c.entries)
+	}
+	return c
+}
+
+// treeModTime returns the latest modification time of the directories in
+// the tree at path. Files added, removed or renamed anywhere below change
+// it; only reading directories, it costs far less than measuring.
+func treeModTime(path string) (time.Time, error) {
+	var latest time.Time
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == path {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest, err
+}
+
+// Size returns the size of the directory at path, measuring it unless a
+// recent measurement exists and nothing in the tree has been added or
+// removed since. Measuring counts what it visits in counts.
+func (c *Cache) Size(path string, counts *utils.WalkCounts) int64 {
+	if c == nil {
+		size, _ := utils.DirSize(path, counts)
+		return size
+	}
+	modTime, err := treeModTime(path)
+	if err != nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && c.now().Sub(e.Measured) < MaxAge && e.ModTime.Equal(modTime) {
+		return e.Size
+	}
+	return c.measure(path, modTime, counts)
+}
+
+// Refresh measures the directory at path and stores its size
+func (c *Cache) Refresh(path string) int64 {
+	modTime, err := treeModTime(path)
+	if err != nil {
+		c.mu.Lock()
+		delete(c.entries, path)
+		c.mu.Unlock()
+		return 0
+	}
+	return c.measure(path, modTime, nil)
+}
+
+func (c *Cache) measure(path string, modTime time.Time, counts *utils.WalkCounts) int64 {
+	size, _ := utils.DirSize(path, counts)
+	c.mu.Lock()
+	c.entries[path] = entry{Size: size, ModTime: modTime, Measured: c.now()}
+	c.mu.Unlock()
+	return size
+}
+
+// Forget drops the sizes of path, the directories below it and those
+// holding it, which cleaning path changed
+func (c *Cache) Forget(path string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for p := range c.entries {
+		if p == path || utils.HasPathPrefix(p, []string{path}) || utils.HasPathPrefix(path, []string{p}) {
+			delete(c.entries, p)
+		}
+	}
+}
+
+// Stale returns the cached directories measured more than age ago
+func (c *Cache) Stale(age time.Duration) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var paths []string
+	for path, e := range c.entries {
+		if c.now().Sub(e.Measured) > age {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Save writes the cache, forgetting directories not measured for a week
+func (c *Cache) Save() error {
+	if c == nil || c.path == "" {
+		return nil
+	}
+	c.mu.Lock()
+	for path, e := range c.entries {
+		if c.now().Sub(e.Measured) > forgetAfter {
+			delete(c.entries, path)
+		}
+	}
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	// Write then rename, so the TUI and daemon never read a partial file
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
@@ -0,0 +1,104 @@
+package sizecache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSizeReusesRecentMeasurements(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "DerivedData")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(target, "a"), make([]byte, 1000), 0o644)
+
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)
+	c := Open(filepath.Join(dir, "sizes.json"))
+	c.now = func() time.Time { return now }
+
+	if got := c.Size(target, nil); got != 1000 {
+		t.Fatalf("Size = %d, want 1000", got)
+	}
+
+	// Files appearing in a subdirectory are seen though the directory's own
+	// mtime is unchanged
+	sub := filepath.Join(target, "sub")
+	os.Mkdir(sub, 0o755)
+	past := now.Add(-time.Hour)
+	os.Chtimes(sub, past, past)
+	os.Chtimes(target, past, past)
+	c.Refresh(target)
+	os.WriteFile(filepath.Join(sub, "b"), make([]byte, 500), 0o644)
+	os.Chtimes(target, past, past)
+	if got := c.Size(target, nil); got != 1500 {
+		t.Errorf("Size after growth below = %d, want 1500", got)
+	}
+
+	// An unchanged tree is served from the cache until it expires
+	os.WriteFile(filepath.Join(target, "a"), make([]byte, 2000), 0o644)
+	if got := c.Size(target, nil); got != 1500 {
+		t.Errorf("cached Size = %d, want 1500", got)
+	}
+	now = now.Add(MaxAge + time.Minute)
+	if got := c.Size(target, nil); got != 2500 {
+		t.Errorf("Size after expiry = %d, want 2500", got)
+	}
+
+	// A changed directory is measured again right away
+	os.WriteFile(filepath.Join(target, "c"), make([]byte, 10), 0o644)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(target, later, later)
+	if got := c.Size(target, nil); got != 2510 {
+		t.Errorf("Size after change = %d, want 2510", got)
+	}
+
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reopened := Open(filepath.Join(dir, "sizes.json"))
+	reopened.now = func() time.Time { return now }
+	if stale := reopened.Stale(time.Minute); len(stale) != 0 {
+		t.Errorf("Stale = %v right after measuring", stale)
+	}
+	now = now.Add(2 * time.Minute)
+	if stale := reopened.Stale(time.Minute); len(stale) != 1 || stale[0] != target {
+		t.Errorf("Stale = %v, want [%s]", stale, target)
+	}
+}
+
+func TestForgetDropsCleanedTrees(t *testing.T) {
+	dir := t.TempDir()
+	c := Open(filepath.Join(dir, "sizes.json"))
+	paths := []string{"/home/Library", "/home/Library/Caches", "/home/Library/Caches/app", "/home/Library/Logs", "/home/LibraryOld"}
+	for _, path := range paths {
+		c.entries[path] = entry{Size: 1, Measured: time.Now()}
+	}
+
+	c.Forget("/home/Library/Caches")
+	for path, kept := range map[string]bool{
+		"/home/Library":            false,
+		"/home/Library/Caches":     false,
+		"/home/Library/Caches/app": false,
+		"/home/Library/Logs":       true,
+		"/home/LibraryOld":         true,
+	} {
+		if _, ok := c.entries[path]; ok != kept {
+			t.Errorf("%s kept = %v, want %v", path, ok, kept)
+		}
+	}
+}
+
+func TestNilCacheMeasures(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a"), make([]byte, 42), 0o644)
+	var c *Cache
+	if got := c.Size(dir, nil); got != 42 {
+		t.Errorf("Size = %d, want 42", got)
+	}
+	if err := c.Save(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,121 @@
+package space
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Container holds the figures of the APFS container behind a volume. Every
+// volume in a container shares its free space, so df shows the container's
+// free space for each of them.
+type Container struct {
+	Reference  string // e.g. disk3
+	Size       int64
+	Free       int64
+	VolumeUsed int64 // Used by the volume itself, including its snapshots
+}
+
+// Other returns the bytes used by the container's other volumes, e.g.
+// System, Preboot and VM
+func (c *Container) Other() int64 {
+	other := c.Size - c.Free - c.VolumeUsed
+	if other < 0 {
+		return 0
+	}
+	return other
+}
+
+// containerInfo reads the APFS container figures of the volume holding path
+// from `diskutil info -plist`
+func containerInfo(path string) (*Container, error) {
+	out, err := run("diskutil", "info", "-plist", path)
+	if err != nil {
+		return nil, err
+	}
+	return parseContainer(out)
+}
+
+// parseContainer reads the container figures from `diskutil info -plist`
+// output. Volumes outside APFS have none.
+func parseContainer(data []byte) (*Container, error) {
+	values, err := parsePlistDict(data)
+	if err != nil {
+		return nil, err
+	}
+	if values["APFSContainerSize"] == "" {
+		return nil, errors.New("not an APFS volume")
+	}
+
+	c := &Container{Reference: values["APFSContainerReference"]}
+	for key, dst := range map[string]*int64{
+		"APFSContainerSize": &c.Size,
+		"APFSContainerFree": &c.Free,
+		"CapacityInUse":     &c.VolumeUsed,
+	} {
+		if values[key] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(values[key], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s %q: %w", key, values[key], err)
+		}
+		*dst = n
+	}
+	return c, nil
+}
+
+// parsePlistDict returns the scalar values of a property list's top-level
+// dictionary as strings. Nested arrays and dictionaries are skipped.
+func parsePlistDict(data []byte) (map[string]string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	values := make(map[string]string)
+	depth := 0
+	var key string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if depth == 0 {
+				return nil, errors.New("plist has no dictionary")
+			}
+			return values, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("plist: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "dict" && depth == 0:
+				depth = 1
+			case depth == 0:
+				// plist element
+			case t.Name.Local == "key":
+				var k string
+				if err := dec.DecodeElement(&k, &t); err != nil {
+					return nil, fmt.Errorf("plist: %w", err)
+				}
+				key = k
+			case t.Name.Local == "true" || t.Name.Local == "false":
+				values[key] = t.Name.Local
+				dec.Skip()
+			case t.Name.Local == "dict" || t.Name.Local == "array":
+				dec.Skip()
+			default:
+				var v string
+				if err := dec.DecodeElement(&v, &t); err != nil {
+					return nil, fmt.Errorf("plist: %w", err)
+				}
+				values[key] = v
+			}
+		case xml.EndElement:
+			if t.Name.Local == "dict" && depth == 1 {
+				return values, nil
+			}
+		}
+	}
+}
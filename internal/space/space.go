@@ -0,0 +1,152 @@
+// Package space explains a volume's free space: what df reports, how the
+// APFS container it shares is used, what macOS can purge on demand and
+// which APFS local snapshots still hold deleted files
+package space
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// commandTimeout bounds each tmutil, diskutil or osascript call
+const commandTimeout = 20 * time.Second
+
+// run runs a command and returns its standard output. Tests replace it.
+var run = func(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// Snapshot is an APFS local snapshot, e.g. one taken by Time Machine
+type Snapshot struct {
+	Name string
+	Time time.Time // Zero if the name has no date
+}
+
+// Report accounts for a volume's free space
+type Report struct {
+	Free         int64 // Free bytes as df reports them
+	Total        int64
+	Purgeable    int64 // Bytes macOS frees on demand, counted as available by Finder
+	PurgeableErr error
+	Snapshots    []Snapshot
+	SnapshotErr  error
+	Container    *Container // Nil outside APFS or when diskutil fails
+	ContainerErr error
+}
+
+// Explain builds the report for the volume holding path. Purgeable space
+// and snapshots are best effort; their errors are kept in the report.
+func Explain(path string) (*Report, error) {
+	free, total, err := utils.DiskSpace(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{Free: free, Total: total}
+
+	r.Purgeable, r.PurgeableErr = purgeable(path, free)
+
+	r.Container, r.ContainerErr = containerInfo(path)
+
+	out, err := run("tmutil", "listlocalsnapshots", path)
+	if err != nil {
+		r.SnapshotErr = err
+	} else {
+		r.Snapshots = ParseSnapshots(string(out))
+	}
+	return r, nil
+}
+
+// Purgeable returns the bytes macOS frees on demand on the volume holding
+// path, such as local snapshots and cached iCloud files
+func Purgeable(path string) (int64, error) {
+	free, _, err := utils.DiskSpace(path)
+	if err != nil {
+		return 0, err
+	}
+	return purgeable(path, free)
+}
+
+func purgeable(path string, free int64) (int64, error) {
+	available, err := importantAvailable(path)
+	if err != nil {
+		return 0, err
+	}
+	if available > free {
+		return available - free, nil
+	}
+	return 0, nil
+}
+
+// importantAvailable asks Foundation for the space available to important
+// files, which includes purgeable space, through JavaScript for Automation
+func importantAvailable(path string) (int64, error) {
+	script := `ObjC.import('Foundation');
+var url = $.NSURL.fileURLWithPath(` + strconv.Quote(path) + `);
+var values = url.resourceValuesForKeysError(['NSURLVolumeAvailableCapacityForImportantUsageKey'], null);
+ObjC.unwrap(values.objectForKey('NSURLVolumeAvailableCapacityForImportantUsageKey')).toString()`
+	out, err := run("osascript", "-l", "JavaScript", "-e", script)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("available capacity %q: %w", strings.TrimSpace(string(out)), err)
+	}
+	return n, nil
+}
+
+var snapshotDate = regexp.MustCompile(`\d{4}-\d{2}-\d{2}-\d{6}`)
+
+// ParseSnapshots reads `tmutil listlocalsnapshots` output, which lists one
+// snapshot per line, after a "Snapshots for disk /:" header on newer
+// systems
+func ParseSnapshots(out string) []Snapshot {
+	var snapshots []Snapshot
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "com.apple.") {
+			continue
+		}
+		s := Snapshot{Name: line}
+		if date := snapshotDate.FindString(line); date != "" {
+			s.Time, _ = time.ParseInLocation("2006-01-02-150405", date, time.Local)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots
+}
+
+// Oldest returns the earliest dated snapshot, or a zero time if none is dated
+func (r *Report) Oldest() time.Time {
+	var oldest time.Time
+	for _, s := range r.Snapshots {
+		if !s.Time.IsZero() && (oldest.IsZero() || s.Time.Before(oldest)) {
+			oldest = s.Time
+		}
+	}
+	return oldest
+}
+
+// ThinSnapshots asks Time Machine to delete as many local snapshots on the
+// volume holding path as it can, at the highest urgency
+func ThinSnapshots(path string) error {
+	_, total, err := utils.DiskSpace(path)
+	if err != nil {
+		return err
+	}
+	_, err = run("tmutil", "thinlocalsnapshots", path, strconv.FormatInt(total, 10), "4")
+	return err
+}
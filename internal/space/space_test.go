@@ -0,0 +1,114 @@
+package space
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSnapshots(t *testing.T) {
+	out := `Snapshots for disk /:
+com.apple.TimeMachine.2025-03-12-093000.local
+com.apple.TimeMachine.2025-03-11-213000.local
+com.apple.os.update-ABCDEF
+`
+	got := ParseSnapshots(out)
+	if len(got) != 3 {
+		t.Fatalf("ParseSnapshots = %+v, want 3 snapshots", got)
+	}
+	if got[2].Name != "com.apple.os.update-ABCDEF" || !got[2].Time.IsZero() {
+		t.Errorf("undated snapshot = %+v", got[2])
+	}
+
+	r := &Report{Snapshots: got}
+	if want := time.Date(2025, 3, 11, 21, 30, 0, 0, time.Local); !r.Oldest().Equal(want) {
+		t.Errorf("Oldest = %v, want %v", r.Oldest(), want)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	defer func(orig func(string, ...string) ([]byte, error)) { run = orig }(run)
+	run = func(name string, args ...string) ([]byte, error) {
+		switch name {
+		case "osascript":
+			// More than any real volume has free, so all of it is purgeable
+			return []byte("9223372036854775807\n"), nil
+		case "tmutil":
+			return []byte("com.apple.TimeMachine.2025-03-12-093000.local\n"), nil
+		case "diskutil":
+			return []byte(`<plist><dict><key>APFSContainerSize</key><integer>100</integer><key>APFSContainerFree</key><integer>40</integer></dict></plist>`), nil
+		}
+		return nil, errors.New("unexpected command " + name)
+	}
+
+	r, err := Explain(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Total <= 0 || r.Purgeable <= 0 || r.PurgeableErr != nil {
+		t.Errorf("Explain = %+v", r)
+	}
+	if r.Container == nil || r.Container.Size != 100 || r.ContainerErr != nil {
+		t.Errorf("container = %+v, %v", r.Container, r.ContainerErr)
+	}
+	if len(r.Snapshots) != 1 || r.SnapshotErr != nil {
+		t.Errorf("snapshots = %+v, %v", r.Snapshots, r.SnapshotErr)
+	}
+
+	run = func(name string, args ...string) ([]byte, error) {
+		return nil, errors.New(name + ": not found")
+	}
+	r, err = Explain(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Purgeable != 0 || r.PurgeableErr == nil || r.SnapshotErr == nil || r.Container != nil || r.ContainerErr == nil || !strings.Contains(r.SnapshotErr.Error(), "tmutil") {
+		t.Errorf("Explain without tools = %+v", r)
+	}
+}
+
+func TestParseContainer(t *testing.T) {
+	out := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>APFSContainerFree</key>
+	<integer>21000000000</integer>
+	<key>APFSContainerReference</key>
+	<string>disk3</string>
+	<key>APFSContainerSize</key>
+	<integer>494000000000</integer>
+	<key>APFSPhysicalStores</key>
+	<array>
+		<dict>
+			<key>APFSPhysicalStore</key>
+			<string>disk0s2</string>
+		</dict>
+	</array>
+	<key>CapacityInUse</key>
+	<integer>431000000000</integer>
+	<key>Encryption</key>
+	<true/>
+</dict>
+</plist>`
+	c, err := parseContainer([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Container{Reference: "disk3", Size: 494_000_000_000, Free: 21_000_000_000, VolumeUsed: 431_000_000_000}
+	if *c != want {
+		t.Errorf("parseContainer = %+v, want %+v", *c, want)
+	}
+	if c.Other() != 42_000_000_000 {
+		t.Errorf("Other = %d, want 42000000000", c.Other())
+	}
+
+	hfs := `<plist version="1.0"><dict><key>FilesystemType</key><string>hfs</string></dict></plist>`
+	if _, err := parseContainer([]byte(hfs)); err == nil {
+		t.Error("parseContainer accepted a volume outside APFS")
+	}
+	if _, err := parseContainer([]byte("not a plist")); err == nil {
+		t.Error("parseContainer accepted garbage")
+	}
+}
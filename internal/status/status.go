@@ -0,0 +1,83 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Scan states reported in the status file
+const (
+	StateIdle     = "idle"
+	StateScanning = "scanning"
+	StateCleaning = "cleaning"
+)
+
+// Status is the machine-readable snapshot shared with other processes
+type Status struct {
+	State       string    `json:"state"`
+	Reclaimable int64     `json:"reclaimable"`
+	Items       int       `json:"items"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DefaultPath returns the default location of the status file
+func DefaultPath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "cleanwithcli", "status.json")
+}
+
+// Write atomically replaces the status file at path
+func Write(path string, st Status) error {
+	if st.UpdatedAt.IsZero() {
+		st.UpdatedAt = time.Now()
+	}
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so readers never see a partial line
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Read loads the status file at path
+func Read(path string) (Status, error) {
+	var st Status
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return st, err
+	}
+	err = json.Unmarshal(data, &st)
+	return st, err
+}
+
+// FormatTmux renders the status as a short line suitable for tmux status-right
+func FormatTmux(st Status) string {
+	switch st.State {
+	case StateScanning:
+		return "🧹 scanning…"
+	case StateCleaning:
+		return "🧹 cleaning…"
+	}
+	if st.Reclaimable <= 0 {
+		return "🧹 clean"
+	}
+	return fmt.Sprintf("🧹 %s reclaimable", humanize.Bytes(uint64(st.Reclaimable)))
+}
@@ -0,0 +1,235 @@
+// Package strategy provides the ways a category's items can be cleaned.
+// Deleting with os.RemoveAll is the default, but some things should rather
+// go to the Trash or be cleaned by the tool that owns them.
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// Strategy cleans a single item
+type Strategy interface {
+	Name() string // Short description shown to the user, e.g. "move to Trash"
+	Remove(path string) error
+}
+
+// Reporter is implemented by strategies whose tool says what cleaning an
+// item freed, such as npm
+type Reporter interface {
+	Report(path string) string // Empty when the tool said nothing
+}
+
+// Delete deletes items with a function such as utils.RemovePath, or a
+// rate limited variant of it
+type Delete func(path string) error
+
+func (d Delete) Name() string             { return "delete" }
+func (d Delete) Remove(path string) error { return d(path) }
+
+// RemoveAll deletes items from disk
+var RemoveAll Strategy = Delete(utils.RemovePath)
+
+// Trash moves items into a Trash directory so they can be restored
+type Trash struct {
+	Dir string // Trash directory, usually ~/.Trash
+}
+
+// MoveToTrash returns a strategy moving items into the user's Trash
+func MoveToTrash(home string) Strategy {
+	return Trash{Dir: filepath.Join(home, ".Trash")}
+}
+
+func (t Trash) Name() string { return "move to Trash" }
+
+// Remove moves path into the Trash, adding a timestamp to the name if an
+// item with the same name is already there
+func (t Trash) Remove(path string) error {
+	return moveInto("trash", t.Dir, path)
+}
+
+// Archive moves items into a folder of the user's choosing, out of the way
+// but kept
+type Archive struct {
+	Dir string
+}
+
+func (a Archive) Name() string { return "move to " + a.Dir }
+
+// Remove moves path into the archive folder, adding a timestamp to the name
+// if an item with the same name is already there
+func (a Archive) Remove(path string) error {
+	return moveInto("archive", a.Dir, path)
+}
+
+// moveInto moves path into dir, creating it, without overwriting anything
+func moveInto(op, dir, path string) error {
+	if utils.IsProtectedPath(path) {
+		return types.NewPathError(op, path, types.ErrProtectedPath)
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return types.NewPathError(op, path, err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return types.NewPathError(op, path, err)
+	}
+
+	dest := filepath.Join(dir, filepath.Base(path))
+	if _, err := os.Lstat(dest); err == nil {
+		ext := filepath.Ext(dest)
+		dest = fmt.Sprintf("%s %s%s", strings.TrimSuffix(dest, ext), time.Now().Format("15.04.05.000"), ext)
+	}
+	if err := os.Rename(path, dest); err != nil {
+		return types.NewPathError(op, path, err)
+	}
+	return nil
+}
+
+// Command cleans a whole category by running the tool that owns it, such
+// as `brew cleanup`. The command runs once; every item of the category is
+// then reported as cleaned.
+type Command struct {
+	Args []string
+	// Parse turns the command's output into what it says it freed, e.g.
+	// brew's "freed approximately 1.2GB"; nil when the command says nothing
+	Parse func(out string) string
+
+	once   sync.Once
+	err    error
+	report string
+}
+
+// RunCommand returns a strategy running args once for the whole category
+func RunCommand(args ...string) *Command {
+	return &Command{Args: args}
+}
+
+func (c *Command) Name() string { return "run " + strings.Join(c.Args, " ") }
+
+// Remove runs the command the first time it is called and returns its result
+func (c *Command) Remove(path string) error {
+	c.once.Do(func() {
+		if _, err := exec.LookPath(c.Args[0]); err != nil {
+			c.err = fmt.Errorf("%s is not installed: %w", c.Args[0], err)
+			return
+		}
+		out, err := exec.Command(c.Args[0], c.Args[1:]...).CombinedOutput()
+		if err != nil {
+			msg := strings.TrimSpace(string(out))
+			if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+				msg = msg[i+1:]
+			}
+			c.err = fmt.Errorf("%s: %w: %s", strings.Join(c.Args, " "), err, msg)
+			return
+		}
+		if c.Parse != nil {
+			c.report = c.Parse(string(out))
+		}
+	})
+	if c.err != nil {
+		return types.NewPathError("clean", path, c.err)
+	}
+	return nil
+}
+
+// Report returns what Parse made of the command's output, empty before the
+// command ran
+func (c *Command) Report(path string) string { return c.report }
+
+// Switch picks a strategy by item path, falling back to a default
+type Switch struct {
+	Paths    map[string]Strategy
+	Fallback Strategy
+}
+
+func (s Switch) Name() string {
+	var names []string
+	for _, st := range s.Paths {
+		if name := st.Name(); !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if name := s.Fallback.Name(); !contains(names, name) {
+		names = append(names, name)
+	}
+	return strings.Join(names, " or ")
+}
+
+// Remove uses the strategy registered for path or the closest parent
+func (s Switch) Remove(path string) error {
+	return s.strategyFor(path).Remove(path)
+}
+
+// Report returns what the strategy used for path said, if it reports
+func (s Switch) Report(path string) string {
+	if r, ok := s.strategyFor(path).(Reporter); ok {
+		return r.Report(path)
+	}
+	return ""
+}
+
+func (s Switch) strategyFor(path string) Strategy {
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if st, ok := s.Paths[dir]; ok {
+			return st
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+	return s.Fallback
+}
+
+// ErrPartial is returned for paths below an item whose strategy runs a
+// command, which can only clean the whole category
+var ErrPartial = errors.New("cleaned only as a whole by its command")
+
+// Items limits st to the given items: paths below them, found while
+// exploring, are still cleaned with st, unless that would run a command
+func Items(st Strategy, paths []string) Strategy {
+	items := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		items[path] = true
+	}
+	return itemStrategy{Strategy: st, items: items}
+}
+
+type itemStrategy struct {
+	Strategy
+	items map[string]bool
+}
+
+// Remove refuses paths below an item that st would clean by running a
+// command, since that cleans everything rather than the path
+func (s itemStrategy) Remove(path string) error {
+	if !s.items[path] {
+		st := s.Strategy
+		if sw, ok := st.(Switch); ok {
+			st = sw.strategyFor(path)
+		}
+		if _, ok := st.(*Command); ok {
+			return types.NewPathError("clean", path, ErrPartial)
+		}
+	}
+	return s.Strategy.Remove(path)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
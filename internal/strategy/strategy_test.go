@@ -0,0 +1,178 @@
+package strategy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func TestMoveToTrash(t *testing.T) {
+	home := t.TempDir()
+	trash := MoveToTrash(home)
+
+	for i := 0; i < 2; i++ {
+		path := filepath.Join(home, "Downloads", "setup.dmg")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := trash.Remove(path); err != nil {
+			t.Fatalf("Remove(%q) = %v", path, err)
+		}
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", path)
+		}
+	}
+
+	// The second file must not overwrite the first
+	entries, err := os.ReadDir(filepath.Join(home, ".Trash"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Trash has %d entries, want 2", len(entries))
+	}
+
+	if err := trash.Remove(filepath.Join(home, "missing")); !errors.Is(err, types.ErrNotFound) {
+		t.Errorf("Remove(missing) = %v, want ErrNotFound", err)
+	}
+	if err := trash.Remove("/"); !errors.Is(err, types.ErrProtectedPath) {
+		t.Errorf("Remove(/) = %v, want ErrProtectedPath", err)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	home := t.TempDir()
+	archive := Archive{Dir: filepath.Join(home, "Pictures", "Screenshots")}
+
+	path := filepath.Join(home, "Screenshot.png")
+	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Remove(path); err != nil {
+		t.Fatalf("Remove(%q) = %v", path, err)
+	}
+	if _, err := os.Lstat(filepath.Join(archive.Dir, "Screenshot.png")); err != nil {
+		t.Errorf("not archived: %v", err)
+	}
+	if want := "move to " + archive.Dir; archive.Name() != want {
+		t.Errorf("Name() = %q, want %q", archive.Name(), want)
+	}
+}
+
+func TestCommandRunsOnce(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "runs")
+	cmd := RunCommand("sh", "-c", "echo run >> "+log)
+
+	for _, path := range []string{"/cache/a", "/cache/b"} {
+		if err := cmd.Remove(path); err != nil {
+			t.Fatalf("Remove(%q) = %v", path, err)
+		}
+	}
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "run\n" {
+		t.Errorf("command output %q, want a single run", data)
+	}
+
+	failing := RunCommand("sh", "-c", "echo nope; exit 3")
+	if err := failing.Remove("/cache/a"); err == nil {
+		t.Error("failing command reported success")
+	}
+	if err := RunCommand("no-such-cleanup-tool").Remove("/cache/a"); err == nil {
+		t.Error("missing command reported success")
+	}
+
+	parsed := RunCommand("sh", "-c", "echo freed 3MB")
+	parsed.Parse = func(out string) string { return strings.TrimSpace(out) }
+	if report := parsed.Report("/cache/a"); report != "" {
+		t.Errorf("Report() before running = %q", report)
+	}
+	if err := parsed.Remove("/cache/a"); err != nil {
+		t.Fatal(err)
+	}
+	if report := parsed.Report("/cache/a"); report != "freed 3MB" {
+		t.Errorf("Report() = %q, want the parsed output", report)
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	var got []string
+	record := func(name string) Strategy {
+		return fakeStrategy{name: name, calls: &got}
+	}
+	sw := Switch{
+		Paths:    map[string]Strategy{"/home/.gem": record("gem")},
+		Fallback: record("delete"),
+	}
+
+	for _, path := range []string{"/home/.gem", "/home/.gem/cache/x", "/home/.bundle/cache"} {
+		if err := sw.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"gem", "gem", "delete"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("call %d used %q, want %q", i, got[i], want[i])
+		}
+	}
+	if name := sw.Name(); name != "gem or delete" {
+		t.Errorf("Name() = %q", name)
+	}
+
+	sw.Paths["/home/.npm"] = reportingStrategy{fakeStrategy{name: "npm", calls: &got}}
+	if report := sw.Report("/home/.npm"); report != "npm reclaimed 1 MB" {
+		t.Errorf("Report(npm) = %q", report)
+	}
+	if report := sw.Report("/home/.gem"); report != "" {
+		t.Errorf("Report(gem) = %q, want nothing", report)
+	}
+}
+
+type fakeStrategy struct {
+	name  string
+	calls *[]string
+}
+
+func (f fakeStrategy) Name() string { return f.name }
+
+func (f fakeStrategy) Remove(string) error {
+	*f.calls = append(*f.calls, f.name)
+	return nil
+}
+
+type reportingStrategy struct{ fakeStrategy }
+
+func (reportingStrategy) Report(string) string { return "npm reclaimed 1 MB" }
+
+func TestItemsRefusesCommandsBelowItems(t *testing.T) {
+	var got []string
+	sw := Switch{
+		Paths:    map[string]Strategy{"/home/go/pkg/mod": RunCommand("sh", "-c", "exit 0")},
+		Fallback: fakeStrategy{name: "delete", calls: &got},
+	}
+	st := Items(sw, []string{"/home/go/pkg/mod", "/home/go/sdk"})
+
+	if err := st.Remove("/home/go/pkg/mod/golang.org/x/text@v0.1.0"); !errors.Is(err, ErrPartial) {
+		t.Errorf("Remove(module) = %v, want ErrPartial", err)
+	}
+	if err := st.Remove("/home/go/pkg/mod"); err != nil {
+		t.Errorf("Remove(item) = %v", err)
+	}
+	if err := st.Remove("/home/go/sdk/go1.21.0"); err != nil || len(got) != 1 {
+		t.Errorf("Remove(below a deleted item) = %v, calls %v", err, got)
+	}
+	if err := Items(RunCommand("sh", "-c", "exit 0"), []string{"/cache"}).Remove("/cache/x"); !errors.Is(err, ErrPartial) {
+		t.Errorf("Remove(below a command item) = %v, want ErrPartial", err)
+	}
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+)
+
+// Error kinds shared by the scanner and clean layers
+var (
+	ErrPermission    = errors.New("permission denied")
+	ErrNotFound      = errors.New("no longer exists")
+	ErrInUse         = errors.New("in use by another process")
+	ErrProtectedPath = errors.New("protected path")
+)
+
+// PathError records a failed operation on a path along with its kind
+type PathError struct {
+	Op   string // "scan" or "remove"
+	Path string
+	Kind error // One of the Err* kinds above, nil if unknown
+	Err  error // Underlying error
+}
+
+// NewPathError wraps err for path, classifying it into one of the known kinds
+func NewPathError(op, path string, err error) *PathError {
+	return &PathError{
+		Op:   op,
+		Path: path,
+		Kind: classify(err),
+		Err:  err,
+	}
+}
+
+func (e *PathError) Error() string {
+	if e.Kind != nil && !errors.Is(e.Err, e.Kind) {
+		return fmt.Sprintf("%s %s: %v (%v)", e.Op, e.Path, e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Path, e.Err)
+}
+
+// Unwrap allows errors.Is to match both the kind and the underlying error
+func (e *PathError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// classify maps a file system error to one of the known kinds
+func classify(err error) error {
+	switch {
+	case errors.Is(err, ErrProtectedPath):
+		return ErrProtectedPath
+	case errors.Is(err, fs.ErrPermission):
+		return ErrPermission
+	case errors.Is(err, fs.ErrNotExist):
+		return ErrNotFound
+	case errors.Is(err, syscall.EBUSY), errors.Is(err, syscall.ETXTBSY):
+		return ErrInUse
+	}
+	return nil
+}
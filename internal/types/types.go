@@ -1,12 +1,69 @@
 package types
 
-import "github.com/charmbracelet/bubbles/table"
+import (
+	"os"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+)
 
 // ScanResult represents files found in a category
 type ScanResult struct {
 	Category string
 	Items    []FileItem
 	Total    int64
+	Errors   []*PathError // Locations that could not be read
+	Advisory bool         // Report only: sizes are shown but items are never deleted
+	Hint     string       // How to remove advisory content through official means
+	// Remover deletes the category's items in place of the default
+	// remover, e.g. by asking the plugin that found them; nil for the default
+	Remover func(path string) error
+	Method  string // How Remover cleans items, e.g. "move to Trash"; empty when deleted
+	// Report returns what the tool that cleaned path said it freed, e.g.
+	// npm's reclaimed space; nil or empty when it said nothing
+	Report func(path string) string
+	// Directories and files the scanner visited, for the scan summary
+	DirsVisited  int64
+	FilesVisited int64
+}
+
+// ScannerStats is what running one scanner cost
+type ScannerStats struct {
+	Name     string        `json:"name,omitempty"` // Registered name, empty for plugins
+	Category string        `json:"category"`
+	Duration time.Duration `json:"duration"`
+	Dirs     int64         `json:"dirs"`
+	Files    int64         `json:"files"`
+}
+
+// ScanStats summarizes the resources a scan used
+type ScanStats struct {
+	Duration   time.Duration  `json:"duration"`
+	PeakMemory uint64         `json:"peak_memory"` // Highest heap in use during the scan
+	Scanners   []ScannerStats `json:"scanners"`    // Slowest first
+}
+
+// Dirs returns the directories visited by all scanners
+func (s *ScanStats) Dirs() int64 {
+	var n int64
+	for _, sc := range s.Scanners {
+		n += sc.Dirs
+	}
+	return n
+}
+
+// Files returns the files visited by all scanners
+func (s *ScanStats) Files() int64 {
+	var n int64
+	for _, sc := range s.Scanners {
+		n += sc.Files
+	}
+	return n
+}
+
+// AddError records a location that could not be scanned
+func (r *ScanResult) AddError(path string, err error) {
+	r.Errors = append(r.Errors, NewPathError("scan", path, err))
 }
 
 // FileItem represents a single file or directory
@@ -19,10 +76,39 @@ type FileItem struct {
 	Children []FileItem
 }
 
+// CategoryInfo explains a category to users who do not know what it holds
+type CategoryInfo struct {
+	Description  string // What the files are
+	Consequences string // What happens when they are deleted
+	Regeneration string // How they come back and what that costs
+}
+
+// RiskLevel describes how disruptive deleting a category is
+type RiskLevel int
+
+const (
+	RiskLow    RiskLevel = iota // Regenerated automatically, e.g. caches
+	RiskMedium                  // Regenerable but costly, e.g. dependencies
+	RiskHigh                    // May contain user data
+)
+
+func (r RiskLevel) String() string {
+	switch r {
+	case RiskLow:
+		return "Low"
+	case RiskMedium:
+		return "Medium"
+	case RiskHigh:
+		return "High"
+	}
+	return "Unknown"
+}
+
 // Messages
 type ScanCompleteMsg struct {
 	Results   map[string]*ScanResult
 	TotalSize int64
+	Stats     *ScanStats // Nil when not measured, e.g. in demo mode
 }
 
 type ScanProgressMsg struct {
@@ -42,19 +128,44 @@ type CleanProgressMsg struct {
 }
 
 type CleanCompleteMsg struct {
-	Freed int64
-	Path  string // Path of the cleaned item
+	Freed       int64
+	Path        string // Path of the cleaned item
+	Err         error  // Set when the item could not be removed
+	VolumeFreed int64  // Change in free space on the home volume
+	Measured    bool   // Whether VolumeFreed could be measured
+	Report      string // What the tool that cleaned the item said it freed
 }
 
 type BatchCleanCompleteMsg struct {
-	Freed int64
-	Paths []string // Paths of the cleaned items
+	Freed       int64
+	Paths       []string // Paths of the cleaned items
+	Errors      []error  // Items that could not be removed
+	VolumeFreed int64    // Change in free space on the home volume
+	Measured    bool     // Whether VolumeFreed could be measured
+	Duration    time.Duration
 }
 
 type DiskUsageMsg struct {
 	Table table.Model
 }
 
+// DirectoryListingMsg carries the contents of a directory opened in the detail view
+type DirectoryListingMsg struct {
+	Path  string
+	Items []FileItem
+}
+
+// PreviewMsg carries the head of a file and its metadata for the preview view
+type PreviewMsg struct {
+	Path        string
+	Size        int64
+	ModTime     time.Time
+	Mode        os.FileMode
+	ContentType string
+	Text        string // Empty for binary files
+	Truncated   bool   // True when only the first part of the file was read
+}
+
 type ErrMsg struct{ Err error }
 
 func (e ErrMsg) Error() string { return e.Err.Error() }
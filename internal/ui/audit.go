@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// auditLoadedMsg carries the deletion log for the audit view
+type auditLoadedMsg struct {
+	records []audit.Record
+}
+
+// loadAudit reads the deletion log
+func loadAudit(path string) tea.Cmd {
+	return func() tea.Msg {
+		if path == "" {
+			return auditLoadedMsg{}
+		}
+		records, err := audit.Load(path)
+		if err != nil {
+			return types.ErrMsg{Err: err}
+		}
+		return auditLoadedMsg{records: records}
+	}
+}
+
+// writeAudit appends records to the deletion log
+func (m Model) writeAudit(records []audit.Record) tea.Cmd {
+	if m.auditFile == "" || m.demo || len(records) == 0 {
+		return nil
+	}
+	path := m.auditFile
+	return func() tea.Msg {
+		if err := audit.Append(path, records...); err != nil {
+			return types.ErrMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// auditClean logs the outcome of a single item clean. It must run before
+// the item is dropped from the listing.
+func (m Model) auditClean(msg types.CleanCompleteMsg) tea.Cmd {
+	if msg.Path == "" {
+		return nil
+	}
+	var size int64
+	for _, item := range m.detailItems {
+		if item.Path == msg.Path {
+			size = item.Size
+			break
+		}
+	}
+	return m.writeAudit([]audit.Record{audit.NewRecord(time.Now(), msg.Path, size, m.currentCategory, msg.Err)})
+}
+
+// auditBatchClean logs the outcome of every item in a batch clean. It must
+// run before the deleted items are dropped from the selection.
+func (m Model) auditBatchClean(msg types.BatchCleanCompleteMsg) tea.Cmd {
+	now := time.Now()
+	var records []audit.Record
+	failed := make(map[string]bool)
+
+	for _, err := range msg.Errors {
+		var pathErr *types.PathError
+		if !errors.As(err, &pathErr) {
+			continue
+		}
+		marked := m.markedItems[pathErr.Path]
+		records = append(records, audit.NewRecord(now, pathErr.Path, marked.item.Size, marked.category, err))
+		failed[pathErr.Path] = true
+	}
+	for _, path := range msg.Paths {
+		if failed[path] {
+			continue
+		}
+		marked := m.markedItems[path]
+		records = append(records, audit.NewRecord(now, path, marked.item.Size, marked.category, nil))
+	}
+
+	return m.writeAudit(records)
+}
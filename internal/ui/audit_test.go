@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/launchd"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func TestBatchCleanIsAudited(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "deletions.jsonl")
+	m := InitialModel(Options{AuditFile: logFile})
+	m.state = "cleaning"
+	m.cleanReturn = "results"
+	m.markedItems = map[string]markedItem{
+		"/c/gone":   {item: types.FileItem{Path: "/c/gone", Size: 100}, category: "Cache Files"},
+		"/c/vanish": {item: types.FileItem{Path: "/c/vanish", Size: 20}, category: "Cache Files"},
+		"/l/locked": {item: types.FileItem{Path: "/l/locked", Size: 5}, category: "Log Files"},
+	}
+
+	_, cmd := m.Update(types.BatchCleanCompleteMsg{
+		Freed: 120,
+		Paths: []string{"/c/gone", "/c/vanish"},
+		Errors: []error{
+			types.NewPathError("remove", "/c/vanish", os.ErrNotExist),
+			types.NewPathError("remove", "/l/locked", os.ErrPermission),
+		},
+	})
+	runCmd(cmd)
+
+	records, err := audit.Load(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]audit.Record)
+	for _, r := range records {
+		got[r.Path] = r
+	}
+	want := map[string]struct {
+		outcome  string
+		size     int64
+		category string
+	}{
+		"/c/gone":   {audit.OutcomeDeleted, 100, "Cache Files"},
+		"/c/vanish": {audit.OutcomeMissing, 20, "Cache Files"},
+		"/l/locked": {audit.OutcomeFailed, 5, "Log Files"},
+	}
+	if len(got) != len(want) || len(records) != len(want) {
+		t.Fatalf("records = %+v, want one per path in %v", records, want)
+	}
+	for path, w := range want {
+		r := got[path]
+		if r.Outcome != w.outcome || r.Size != w.size || r.Category != w.category {
+			t.Errorf("%s: got %+v, want %+v", path, r, w)
+		}
+	}
+	if got["/l/locked"].Error == "" {
+		t.Error("failed record has no error message")
+	}
+}
+
+// runCmd runs cmd and any commands it batches, discarding their messages
+func runCmd(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runCmd(c)
+		}
+	}
+}
+
+func TestLaunchJobRemovalIsAudited(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "deletions.jsonl")
+	m := InitialModel(Options{AuditFile: logFile})
+	m.state = "launchd"
+	m.launchJobs = []launchd.Job{
+		{Label: "com.gone.updater", Plist: "/a/com.gone.updater.plist", Missing: "/Applications/Gone.app"},
+		{Label: "com.gone.daemon", Plist: "/b/com.gone.daemon.plist", Missing: "/Applications/Gone.app"},
+	}
+	m.launchChoice = 1
+
+	updated, cmd := m.Update(launchJobRemovedMsg{job: m.launchJobs[1]})
+	runCmd(cmd)
+	m = updated.(Model)
+	if len(m.launchJobs) != 1 || m.launchJobs[0].Label != "com.gone.updater" || m.launchChoice != 0 {
+		t.Errorf("jobs = %+v, choice %d", m.launchJobs, m.launchChoice)
+	}
+
+	records, err := audit.Load(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Path != "/b/com.gone.daemon.plist" || records[0].Outcome != audit.OutcomeDeleted {
+		t.Errorf("records = %+v", records)
+	}
+}
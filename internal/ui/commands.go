@@ -1,180 +1,120 @@
 package ui
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
-	"strings"
-	"sync"
-	"sync/atomic"
+	"path/filepath"
+	"sort"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
 
+	"github.com/rahulvramesh/cleanWithCli/internal/demo"
+	"github.com/rahulvramesh/cleanWithCli/internal/history"
 	"github.com/rahulvramesh/cleanWithCli/internal/scanner"
+	"github.com/rahulvramesh/cleanWithCli/internal/space"
+	"github.com/rahulvramesh/cleanWithCli/internal/status"
 	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+	"github.com/rahulvramesh/cleanWithCli/pkg/cleaner"
 )
 
-// Channel for sending scan updates
-var scanUpdateChan chan types.ScanProgressMsg
-
 // Command functions
 func performDevScan(s *scanner.Scanner) tea.Cmd {
-	return func() tea.Msg {
-		// Create a channel for live updates
-		scanUpdateChan = make(chan types.ScanProgressMsg, 100)
+	return scanCmd(s, cleaner.ModeDev)
+}
+
+func performScan(s *scanner.Scanner) tea.Cmd {
+	return scanCmd(s, cleaner.ModeFull)
+}
 
-		// Start a goroutine to send updates
-		go func() {
-			for range scanUpdateChan {
-				// Updates are being handled by the channel
+func performQuickScan(s *scanner.Scanner) tea.Cmd {
+	return scanCmd(s, cleaner.ModeQuick)
+}
+
+// scanCmd scans the scanner's home and root directories, plus its plugins,
+// in the given mode
+func scanCmd(s *scanner.Scanner, mode cleaner.Mode) tea.Cmd {
+	return func() tea.Msg {
+		opts := cleaner.Options{
+			Mode:              mode,
+			HomeDir:           s.HomeDir,
+			RootDir:           s.RootDir,
+			Roots:             s.Roots,
+			InstallerAge:      s.InstallerAge,
+			ScreenshotAge:     s.ScreenshotAge,
+			ScreenshotArchive: s.ScreenshotArchive,
+			Sizes:             s.Sizes,
+			Remove:            s.Remove,
+		}
+		// Plugins can report anything, so Quick Clean leaves them out
+		if mode != cleaner.ModeQuick {
+			for _, p := range s.Plugins {
+				opts.Extra = append(opts.Extra, p.Scan)
 			}
-		}()
-
-		// Note: Deep scan operations run in parallel but may take longer
-		// due to traversing entire home directory
-		scanners := []struct {
-			name string
-			fn   func() *types.ScanResult
-		}{
-			{"Node Modules", s.ScanNodeModules},
-			{"Python Artifacts", s.ScanPythonArtifacts},
-			{"Rust Artifacts", s.ScanRustArtifacts},
-			{"Build Artifacts", s.ScanBuildArtifacts},
-			{"NPM/Yarn/PNPM Caches", s.ScanNpmYarnCaches},
-			{"Go Artifacts", s.ScanGoArtifacts},
-			{"Java/JVM Artifacts", s.ScanJavaArtifacts},
-			{"Ruby Artifacts", s.ScanRubyArtifacts},
-			{"Docker Artifacts", s.ScanDockerArtifacts},
-			{"IDE Caches", s.ScanIDECaches},
-			{"Xcode Files", s.ScanXcodeFiles},
-			{"Homebrew Cache", s.ScanBrewCache},
-			{"CocoaPods", s.ScanCocoaPods},
-		}
-
-		results := make(map[string]*types.ScanResult)
-		var totalSize int64
-		var totalFound int
-
-		// Use goroutines for parallel scanning
-		var wg sync.WaitGroup
-		for _, sc := range scanners {
-			wg.Add(1)
-			go func(name string, scanFunc func() *types.ScanResult) {
-				defer wg.Done()
-
-				result := scanFunc()
-				if result.Total > 0 {
-					results[name] = result
-					totalSize += result.Total
-					totalFound += len(result.Items)
-				}
-			}(sc.name, sc.fn)
-		}
-
-		wg.Wait()
-		close(scanUpdateChan)
+		}
+		for name := range s.Disabled {
+			opts.Disabled = append(opts.Disabled, name)
+		}
 
+		report, err := cleaner.Scan(context.Background(), opts)
+		if err != nil {
+			return types.ErrMsg{Err: err}
+		}
 		return types.ScanCompleteMsg{
-			Results:   results,
-			TotalSize: totalSize,
+			Results:   report.Results,
+			TotalSize: report.TotalSize,
+			Stats:     report.Stats,
 		}
 	}
 }
 
-func performScan(s *scanner.Scanner) tea.Cmd {
-	return func() tea.Msg {
-		scanners := []struct {
-			name string
-			fn   func() *types.ScanResult
-		}{
-			{"Cache Files", s.ScanCacheFiles},
-			{"Log Files", s.ScanLogFiles},
-			{"Trash", s.ScanTrash},
-			{"Old Downloads", s.ScanDownloads},
-			{"Xcode Files", s.ScanXcodeFiles},
-			{"Homebrew Cache", s.ScanBrewCache},
-			{"Node Modules", s.ScanNodeModules},
-		}
-
-		results := make(map[string]*types.ScanResult)
-		var totalSize int64
-		var completed int32
-
-		// Use goroutines for parallel scanning
-		var wg sync.WaitGroup
-		for _, sc := range scanners {
-			wg.Add(1)
-			go func(name string, scanFunc func() *types.ScanResult) {
-				defer wg.Done()
-
-				result := scanFunc()
-				if result.Total > 0 {
-					results[name] = result
-					totalSize += result.Total
-				}
-
-				atomic.AddInt32(&completed, 1)
-			}(sc.name, sc.fn)
-		}
-
-		wg.Wait()
-
-		return types.ScanCompleteMsg{
-			Results:   results,
-			TotalSize: totalSize,
+// runScan starts scan, or returns synthetic results in demo mode
+func (m Model) runScan(scan func(*scanner.Scanner) tea.Cmd) tea.Cmd {
+	if m.demo {
+		return func() tea.Msg {
+			return demo.Results()
 		}
 	}
+	return scan(m.scanner)
 }
 
+// showDiskUsage lists the mounted file systems like df -h
 func showDiskUsage() tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("df", "-h")
-		output, err := cmd.Output()
+		mounts, err := utils.Mounts()
 		if err != nil {
 			return types.ErrMsg{Err: err}
 		}
-
-		// Parse the disk usage output and create a table
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-		if len(lines) == 0 {
+		if len(mounts) == 0 {
 			return types.ErrMsg{Err: fmt.Errorf("no disk usage data")}
 		}
 
-		// Create table data
 		var rows []table.Row
-		for i, line := range lines {
-			if i == 0 {
-				continue // Skip header
-			}
-
-			fields := strings.Fields(line)
-			if len(fields) < 9 {
-				continue // Skip malformed lines
-			}
-
-			// Extract fields for macOS df output format
-			filesystem := fields[0]
-			size := fields[1]
-			used := fields[2]
-			avail := fields[3]
-			capacity := fields[4]
-			mountPoint := strings.Join(fields[8:], " ")
-
+		for _, mount := range mounts {
 			// Truncate long filesystem names
+			filesystem := mount.Device
 			if len(filesystem) > 25 {
 				filesystem = filesystem[:22] + "..."
 			}
 
 			rows = append(rows, table.Row{
 				filesystem,
-				size,
-				used,
-				avail,
-				capacity,
-				mountPoint,
+				humanize.Bytes(uint64(mount.Total)),
+				humanize.Bytes(uint64(mount.Used)),
+				humanize.Bytes(uint64(mount.Free)),
+				fmt.Sprintf("%d%%", mount.Capacity()),
+				mount.Path,
 			})
 		}
 
@@ -211,11 +151,320 @@ func showDiskUsage() tea.Cmd {
 	}
 }
 
-func exploreDirectory(m *Model, dirPath string) tea.Cmd {
+// spaceReportMsg carries the free space explanation for the Disk Usage view
+type spaceReportMsg struct {
+	report *space.Report
+	err    error
+}
+
+// loadSpaceReport explains the free space on the home volume
+func (m Model) loadSpaceReport() tea.Cmd {
+	if m.demo {
+		return nil
+	}
+	home := m.scanner.HomeDir
+	return func() tea.Msg {
+		report, err := space.Explain(home)
+		return spaceReportMsg{report: report, err: err}
+	}
+}
+
+// snapshotsThinnedMsg reports the outcome of thinning local snapshots
+type snapshotsThinnedMsg struct {
+	err error
+}
+
+// thinSnapshots asks Time Machine to delete local snapshots on the home
+// volume
+func (m Model) thinSnapshots() tea.Cmd {
+	if m.demo {
+		return nil
+	}
+	home := m.scanner.HomeDir
+	return func() tea.Msg {
+		return snapshotsThinnedMsg{err: space.ThinSnapshots(home)}
+	}
+}
+
+// exploreDirectory lists the entries of dirPath sorted by size
+func exploreDirectory(dirPath string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := os.ReadDir(dirPath)
+		if err != nil {
+			return types.ErrMsg{Err: types.NewPathError("scan", dirPath, err)}
+		}
+
+		items := make([]types.FileItem, 0, len(entries))
+		for _, entry := range entries {
+			path := filepath.Join(dirPath, entry.Name())
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+
+			size := info.Size()
+			if entry.IsDir() {
+				size, _ = utils.GetDirSize(path)
+			}
+			items = append(items, types.FileItem{
+				Path:  path,
+				Size:  size,
+				Name:  entry.Name(),
+				IsDir: entry.IsDir(),
+			})
+		}
+
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Size > items[j].Size
+		})
+
+		return types.DirectoryListingMsg{Path: dirPath, Items: items}
+	}
+}
+
+// revealInFinder selects the given path in a new Finder window
+func revealInFinder(path string) tea.Cmd {
+	return func() tea.Msg {
+		if err := exec.Command("open", "-R", path).Run(); err != nil {
+			return types.ErrMsg{Err: fmt.Errorf("could not reveal %s in Finder: %w", path, err)}
+		}
+		return nil
+	}
+}
+
+// publishStatus writes the current scan state to the status file, if enabled
+func (m Model) publishStatus(state string) tea.Cmd {
+	if m.statusFile == "" {
+		return nil
+	}
+	st := status.Status{
+		State:       state,
+		Reclaimable: m.totalSize,
+		Items:       m.getTotalItems(),
+	}
+	path := m.statusFile
+	return func() tea.Msg {
+		status.Write(path, st)
+		return nil
+	}
+}
+
+// previewLimit is the number of bytes read from a file for the preview view
+const previewLimit = 8 * 1024
+
+// loadPreview reads the beginning of a file for the preview view
+func loadPreview(path string) tea.Cmd {
+	return func() tea.Msg {
+		f, err := os.Open(path)
+		if err != nil {
+			return types.ErrMsg{Err: err}
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return types.ErrMsg{Err: err}
+		}
+
+		buf := make([]byte, previewLimit)
+		n, err := io.ReadFull(f, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return types.ErrMsg{Err: err}
+		}
+		buf = buf[:n]
+
+		msg := types.PreviewMsg{
+			Path:        path,
+			Size:        info.Size(),
+			ModTime:     info.ModTime(),
+			Mode:        info.Mode(),
+			ContentType: http.DetectContentType(buf),
+			Truncated:   info.Size() > int64(n),
+		}
+
+		// Drop a multi-byte character cut off by the read limit
+		text := buf
+		for i := 0; msg.Truncated && i < utf8.UTFMax && len(text) > 0 && !utf8.Valid(text); i++ {
+			text = text[:len(text)-1]
+		}
+
+		// Only show content that looks like text
+		if utf8.Valid(text) && !bytes.ContainsRune(text, 0) {
+			msg.Text = string(text)
+		}
+		return msg
+	}
+}
+
+// historyLoadedMsg carries the scan history for the history view
+type historyLoadedMsg struct {
+	entries []history.Entry
+}
+
+// loadHistory reads the scan history file
+func loadHistory(path string) tea.Cmd {
+	return func() tea.Msg {
+		if path == "" {
+			return historyLoadedMsg{}
+		}
+		entries, err := history.Load(path)
+		if err != nil {
+			return types.ErrMsg{Err: err}
+		}
+		return historyLoadedMsg{entries: history.Scans(entries)}
+	}
+}
+
+// timelinePoint is a free disk space sample shown on the timeline
+type timelinePoint struct {
+	time time.Time
+	free int64
+	kind string
+}
+
+// timelineLoadedMsg carries the free space samples for the timeline view
+type timelineLoadedMsg struct {
+	points []timelinePoint
+	disk   int64 // Size of the home volume
+}
+
+// loadTimeline reads free space samples from the history file and adds a
+// sample of the current free space
+func loadTimeline(path, home string) tea.Cmd {
+	return func() tea.Msg {
+		var entries []history.Entry
+		if path != "" {
+			var err error
+			if entries, err = history.Load(path); err != nil {
+				return types.ErrMsg{Err: err}
+			}
+		}
+
+		var msg timelineLoadedMsg
+		for _, e := range entries {
+			if e.FreeBytes > 0 {
+				msg.points = append(msg.points, timelinePoint{time: e.Time, free: e.FreeBytes, kind: e.Kind})
+				msg.disk = e.DiskBytes
+			}
+		}
+		if free, disk, err := utils.DiskSpace(home); err == nil {
+			msg.points = append(msg.points, timelinePoint{time: time.Now(), free: free, kind: "now"})
+			msg.disk = disk
+		}
+		return msg
+	}
+}
+
+// historyBaselineMsg carries the previous scan of the same kind, for diffing
+// against the current results
+type historyBaselineMsg struct {
+	entry *history.Entry
+}
+
+// saveHistory appends a summary of the current results to the history file
+// and reports the previous scan of the same kind as the diff baseline
+func (m Model) saveHistory() tea.Cmd {
+	if m.historyFile == "" || m.demo {
+		return nil
+	}
+	entry := history.NewEntry(m.scanKind, m.results, m.totalSize)
+	entry.Stats = m.scanStats
+	path := m.historyFile
+	home := m.scanner.HomeDir
 	return func() tea.Msg {
-		// This would need to be implemented to explore directories
-		// For now, just return an error
-		return types.ErrMsg{Err: fmt.Errorf("directory exploration not implemented")}
+		entry.FreeBytes, entry.DiskBytes, _ = utils.DiskSpace(home)
+
+		entries, err := history.Load(path)
+		if err != nil {
+			return types.ErrMsg{Err: err}
+		}
+		if err := history.Append(path, entry); err != nil {
+			return types.ErrMsg{Err: err}
+		}
+		return historyBaselineMsg{entry: history.Previous(entries, entry.Kind)}
+	}
+}
+
+// saveSizes keeps the directory sizes measured by the scan for the next one
+func (m Model) saveSizes() tea.Cmd {
+	if m.scanner.Sizes == nil || m.demo {
+		return nil
+	}
+	sizes := m.scanner.Sizes
+	return func() tea.Msg {
+		sizes.Save()
+		return nil
+	}
+}
+
+// saveCleanSample records the free disk space after a clean, so the timeline
+// shows the effect of each cleanup
+func (m Model) saveCleanSample() tea.Cmd {
+	if m.historyFile == "" || m.demo {
+		return nil
+	}
+	path := m.historyFile
+	home := m.scanner.HomeDir
+	return func() tea.Msg {
+		free, disk, err := utils.DiskSpace(home)
+		if err != nil {
+			return nil
+		}
+		entry := history.Entry{Time: time.Now(), Kind: history.KindClean, FreeBytes: free, DiskBytes: disk}
+		if err := history.Append(path, entry); err != nil {
+			return types.ErrMsg{Err: err}
+		}
+		return diskSpaceMsg{free: free, total: disk}
+	}
+}
+
+// remover returns the function used to delete paths, routing items of
+// categories with their own remover, such as plugin categories, to it.
+// Cached sizes of what a deletion changed are dropped.
+func (m Model) remover() func(string) error {
+	owners := make(map[string]func(string) error)
+	for _, result := range m.results {
+		if result.Remover == nil {
+			continue
+		}
+		for _, item := range result.Items {
+			owners[item.Path] = result.Remover
+		}
+	}
+
+	remove, sizes := m.remove, m.scanner.Sizes
+	return func(path string) error {
+		defer sizes.Forget(path)
+		// Items may also be found below a category item while exploring
+		for dir := path; len(owners) > 0; dir = filepath.Dir(dir) {
+			if r, ok := owners[dir]; ok {
+				return r(path)
+			}
+			if parent := filepath.Dir(dir); parent == dir {
+				break
+			}
+		}
+		return remove(path)
+	}
+}
+
+// reporter returns the function asking the categories that report on
+// cleaning, such as the npm cache, what cleaning a path freed
+func (m Model) reporter() func(string) string {
+	owners := make(map[string]func(string) string)
+	for _, result := range m.results {
+		if result.Report == nil {
+			continue
+		}
+		for _, item := range result.Items {
+			owners[item.Path] = result.Report
+		}
+	}
+	return func(path string) string {
+		if r, ok := owners[path]; ok {
+			return r(path)
+		}
+		return ""
 	}
 }
 
@@ -225,45 +474,96 @@ func cleanProgressTicker() tea.Cmd {
 	})
 }
 
-func performCleanMarkedItemsWithProgress(s *scanner.Scanner, markedItems map[string]bool, detailItems []types.FileItem) tea.Cmd {
+// measureFreed runs clean and returns how much free space the volume holding
+// path gained, reporting false when path is empty or can't be measured
+func measureFreed(path string, clean func()) (int64, bool) {
+	if path == "" {
+		clean()
+		return 0, false
+	}
+	before, _, errBefore := utils.DiskSpace(path)
+	clean()
+	after, _, errAfter := utils.DiskSpace(path)
+	if errBefore != nil || errAfter != nil {
+		return 0, false
+	}
+	return after - before, true
+}
+
+// volumePath is the path whose volume is measured around cleans, empty in
+// demo mode where nothing is deleted
+func (m Model) volumePath() string {
+	if m.demo {
+		return ""
+	}
+	return m.scanner.HomeDir
+}
+
+func performCleanMarkedItemsWithProgress(remove func(string) error, volume string, items []types.FileItem) tea.Cmd {
 	return func() tea.Msg {
-		var freed int64
-		var paths []string
-
-		for path := range markedItems {
-			err := os.RemoveAll(path)
-			if err == nil {
-				// Find the size of the deleted item
-				for _, item := range detailItems {
-					if item.Path == path {
-						freed += item.Size
-						paths = append(paths, path)
-						break
-					}
-				}
-			}
-		}
+		started := time.Now()
+		var report cleaner.CleanReport
+		volumeFreed, measured := measureFreed(volume, func() {
+			report = cleaner.Clean(items, cleaner.StrategyFunc(remove))
+		})
 
 		cleaningInProgress = false
 		return types.BatchCleanCompleteMsg{
-			Freed: freed,
-			Paths: paths,
+			Freed:       report.Freed,
+			Paths:       report.Removed,
+			Errors:      report.Errors,
+			VolumeFreed: volumeFreed,
+			Measured:    measured,
+			Duration:    time.Since(started),
 		}
 	}
 }
 
-func performCleanItemWithProgress(s *scanner.Scanner, item types.FileItem) tea.Cmd {
+func performCleanItemWithProgress(remove func(string) error, report func(string) string, volume string, item types.FileItem) tea.Cmd {
 	return func() tea.Msg {
-		err := os.RemoveAll(item.Path)
+		var err error
+		volumeFreed, measured := measureFreed(volume, func() {
+			err = remove(item.Path)
+		})
 		var freed int64
-		if err == nil {
+		var said string
+		switch {
+		case err == nil:
+			freed = cleaner.Freed(item)
+			said = report(item.Path)
+		case errors.Is(err, types.ErrNotFound):
 			freed = item.Size
 		}
 
 		cleaningInProgress = false
 		return types.CleanCompleteMsg{
-			Freed: freed,
-			Path:  item.Path,
+			Freed:       freed,
+			Path:        item.Path,
+			Err:         err,
+			VolumeFreed: volumeFreed,
+			Measured:    measured,
+			Report:      said,
+		}
+	}
+}
+
+// largestFilesCount is how many files the largest files walk lists
+const largestFilesCount = 100
+
+// largestFilesMsg carries the largest files under the chosen folder
+type largestFilesMsg struct {
+	result *types.ScanResult
+}
+
+// findLargestFiles walks root for its largest files. In demo mode they are
+// removed by the simulated remover, like everything else.
+func (m Model) findLargestFiles(root string) tea.Cmd {
+	s, demo := m.scanner, m.demo
+	return func() tea.Msg {
+		result := s.LargestFiles(root, largestFilesCount)
+		if demo {
+			result.Remover = nil
 		}
+		return largestFilesMsg{result: result}
 	}
 }
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// maxShownErrors limits how many individual errors are listed at once
+const maxShownErrors = 3
+
+// describeError maps an error to a short title and a suggested recovery action
+func describeError(err error) (title, hint string) {
+	switch {
+	case errors.Is(err, types.ErrProtectedPath):
+		return "Protected location", "System and home root folders are never deleted by this tool."
+	case errors.Is(err, types.ErrPermission):
+		return "Permission denied", "Grant your terminal Full Disk Access in System Settings › Privacy & Security, or skip this item."
+	case errors.Is(err, types.ErrInUse):
+		return "In use", "Quit the application using it and try again."
+	case errors.Is(err, types.ErrNotFound):
+		return "Already gone", "It was removed outside the cleaner; rescan to refresh the totals."
+	}
+	return "Error", ""
+}
+
+// flattenErrors expands errors created with errors.Join into a list
+func flattenErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		if _, isPath := err.(*types.PathError); !isPath {
+			return joined.Unwrap()
+		}
+	}
+	return []error{err}
+}
+
+// renderError renders err with a specific message and recovery hint per kind
+func (m Model) renderError() string {
+	var s strings.Builder
+
+	errs := flattenErrors(m.err)
+	hints := make(map[string]bool)
+
+	for i, err := range errs {
+		if i == maxShownErrors {
+			s.WriteString(DimStyle.Render(fmt.Sprintf("… and %d more", len(errs)-maxShownErrors)))
+			s.WriteString("\n")
+			break
+		}
+		title, hint := describeError(err)
+		s.WriteString(ErrorStyle.Render(utils.SanitizeName(fmt.Sprintf("%s: %v", title, err))))
+		s.WriteString("\n")
+		if hint != "" && !hints[hint] {
+			hints[hint] = true
+			s.WriteString(DimStyle.Render("  → " + hint))
+			s.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// scanErrorSummary describes locations skipped during the last scan
+func (m Model) scanErrorSummary() string {
+	var total, denied int
+	for _, result := range m.results {
+		for _, err := range result.Errors {
+			total++
+			if errors.Is(err, types.ErrPermission) {
+				denied++
+			}
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+
+	summary := fmt.Sprintf("⚠ %d locations could not be read", total)
+	if denied > 0 {
+		_, hint := describeError(types.ErrPermission)
+		summary += fmt.Sprintf(" (%d permission denied) → %s", denied, hint)
+	}
+	return summary
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// freedCheck compares the space a clean reported freeing with the change in
+// free space on the volume
+type freedCheck struct {
+	reported   int64
+	volume     int64
+	categories map[string]int64 // Reported bytes freed per category
+}
+
+// freedCheckThreshold is the smallest shortfall worth explaining
+const freedCheckThreshold = 10 * 1024 * 1024
+
+// shortfall returns how much of the reported space didn't show up as free
+// space, or 0 if the difference is within measurement noise
+func (c freedCheck) shortfall() int64 {
+	diff := c.reported - c.volume
+	if diff < freedCheckThreshold || diff < c.reported/10 {
+		return 0
+	}
+	return diff
+}
+
+// render describes the check, explaining any shortfall
+func (c freedCheck) render() string {
+	var s strings.Builder
+
+	categories := make([]string, 0, len(c.categories))
+	for category := range c.categories {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return c.categories[categories[i]] > c.categories[categories[j]]
+	})
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%s %s", category, humanize.Bytes(uint64(c.categories[category])))
+	}
+	if len(parts) > 0 {
+		s.WriteString("  " + DimStyle.Render("Freed: "+strings.Join(parts, " • ")) + "\n")
+	}
+
+	volume := formatDelta(c.volume)
+	s.WriteString("  " + DimStyle.Render(fmt.Sprintf("Volume free space changed by %s of the %s reported.", volume, humanize.Bytes(uint64(c.reported)))) + "\n")
+
+	if missing := c.shortfall(); missing > 0 {
+		s.WriteString("  " + WarningStyle.Render(fmt.Sprintf("%s hasn't come back yet:", humanize.Bytes(uint64(missing)))) + "\n")
+		s.WriteString("  " + DimStyle.Render("• APFS local snapshots keep deleted files until they expire (tmutil listlocalsnapshots /)") + "\n")
+		s.WriteString("  " + DimStyle.Render("• macOS may count the space as purgeable before Finder shows it as free") + "\n")
+		s.WriteString("  " + DimStyle.Render("• Hardlinked or cloned files free nothing until every copy is gone") + "\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/launchd"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// launchCategory names removed launch jobs in the deletion log
+const launchCategory = "Launch Agents"
+
+// launchJobsMsg carries the third-party launch agents and daemons
+type launchJobsMsg struct {
+	jobs []launchd.Job
+	errs []error
+}
+
+// launchJobRemovedMsg reports the outcome of removing a launch job
+type launchJobRemovedMsg struct {
+	job launchd.Job
+	err error
+}
+
+// loadLaunchJobs lists the launch agents and daemons. Demo mode has none,
+// as they would be the real ones.
+func (m Model) loadLaunchJobs() tea.Cmd {
+	if m.demo {
+		return func() tea.Msg { return launchJobsMsg{} }
+	}
+	root, home := m.scanner.RootDir, m.scanner.HomeDir
+	return func() tea.Msg {
+		jobs, errs := launchd.List(root, home)
+		return launchJobsMsg{jobs: jobs, errs: errs}
+	}
+}
+
+// removeLaunchJob unloads a launch job and deletes its property list
+func (m Model) removeLaunchJob(job launchd.Job) tea.Cmd {
+	if m.demo {
+		return func() tea.Msg { return launchJobRemovedMsg{job: job} }
+	}
+	return func() tea.Msg {
+		return launchJobRemovedMsg{job: job, err: launchd.Remove(job)}
+	}
+}
+
+// launchJobRemoved drops a removed job from the list and logs the outcome
+func (m Model) launchJobRemoved(msg launchJobRemovedMsg) (Model, tea.Cmd) {
+	record := audit.NewRecord(time.Now(), msg.job.Plist, 0, launchCategory, msg.err)
+	if msg.err != nil {
+		m.launchMessage = "Removing " + msg.job.Label + " failed: " + msg.err.Error()
+		if errors.Is(msg.err, types.ErrPermission) {
+			m.launchMessage += " (run with sudo to remove system-wide jobs)"
+		}
+		return m, m.writeAudit([]audit.Record{record})
+	}
+
+	m.launchMessage = "✅ Removed " + msg.job.Label
+	for i, job := range m.launchJobs {
+		if job.Plist == msg.job.Plist {
+			m.launchJobs = append(m.launchJobs[:i:i], m.launchJobs[i+1:]...)
+			break
+		}
+	}
+	m.launchChoice = min(m.launchChoice, max(0, len(m.launchJobs)-1))
+	return m, m.writeAudit([]audit.Record{record})
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// A volume is nearly full with less than lowDiskBytes or lowDiskPercent
+// free
+const (
+	lowDiskBytes   = 15 * 1000 * 1000 * 1000
+	lowDiskPercent = 5
+)
+
+// diskSpaceMsg carries the free space on the home volume
+type diskSpaceMsg struct {
+	free, total int64
+}
+
+// checkDiskSpace measures the free space on the home volume
+func (m Model) checkDiskSpace() tea.Cmd {
+	if m.demo {
+		return nil
+	}
+	home := m.scanner.HomeDir
+	return func() tea.Msg {
+		free, total, err := utils.DiskSpace(home)
+		if err != nil {
+			return nil
+		}
+		return diskSpaceMsg{free: free, total: total}
+	}
+}
+
+// lowDisk reports whether the home volume is nearly full
+func (m Model) lowDisk() bool {
+	if m.diskTotal <= 0 {
+		return false
+	}
+	return m.diskFree < lowDiskBytes || m.diskFree*100 < m.diskTotal*lowDiskPercent
+}
+
+// renderLowDiskBanner warns that the home volume is nearly full and
+// suggests where to start
+func (m Model) renderLowDiskBanner() string {
+	if !m.lowDisk() {
+		return ""
+	}
+	var s strings.Builder
+	percent := float64(m.diskFree) * 100 / float64(m.diskTotal)
+	s.WriteString("  " + ErrorStyle.Render(fmt.Sprintf("⚠ Low disk space: %s free of %s (%.0f%%)",
+		humanize.Bytes(uint64(m.diskFree)), humanize.Bytes(uint64(m.diskTotal)), percent)) + "\n")
+	s.WriteString("  " + DimStyle.Render("Start with Quick Clean: it only removes caches, trash and old logs.") + "\n")
+	return s.String()
+}
@@ -1,20 +1,34 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/changelog"
+	"github.com/rahulvramesh/cleanWithCli/internal/demo"
+	"github.com/rahulvramesh/cleanWithCli/internal/grouping"
+	"github.com/rahulvramesh/cleanWithCli/internal/history"
+	"github.com/rahulvramesh/cleanWithCli/internal/launchd"
+	"github.com/rahulvramesh/cleanWithCli/internal/plugin"
 	"github.com/rahulvramesh/cleanWithCli/internal/scanner"
+	"github.com/rahulvramesh/cleanWithCli/internal/schedule"
+	"github.com/rahulvramesh/cleanWithCli/internal/sizecache"
+	"github.com/rahulvramesh/cleanWithCli/internal/space"
 	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
 )
 
 // Model represents the application state
 type Model struct {
 	scanner        *scanner.Scanner
-	state          string // "menu", "scanning", "results", "cleaning", "diskusage", "detail"
+	state          string // "menu", "scanning", "results", "cleaning", "diskusage", "detail", "preview", "review", "confirmAll", "history", "timeline", "audit", "launchd", "whatsnew"
 	menuChoice     int
 	scanProgress   float64
 	scanMessage    string
@@ -29,9 +43,15 @@ type Model struct {
 	height         int
 	err            error
 	diskUsageTable table.Model
+	scanStats      *types.ScanStats // What the last scan cost
+	statsExpanded  bool             // Whether the scan summary lists each scanner
+	spaceReport    *space.Report    // Free space explanation on the Disk Usage view
+	spaceMessage   string           // Outcome of thinning snapshots
 	// Detail view fields
 	currentCategory string
 	currentPath     []string // breadcrumb path
+	currentDir      string   // Directory being listed, empty at the category root
+	navStack        []navFrame
 	detailItems     []types.FileItem
 	detailChoice    int
 	detailOffset    int // Scroll offset for detail view
@@ -39,26 +59,158 @@ type Model struct {
 	scanningPaths  []string // Recently scanned paths
 	scanFoundItems int      // Number of items found
 	scanTotalSize  int64    // Total size found so far
+	// Preview view fields
+	preview       *types.PreviewMsg
+	previewOffset int // First visible line of the preview
 	// Multi-selection fields
-	markedItems map[string]bool // Track marked items by path
+	markedItems  map[string]markedItem // Track marked items by path, across categories
+	patternInput textinput.Model       // Glob prompt for marking by pattern
+	rootInput    textinput.Model       // Folder prompt for the largest files walk
+	reviewChoice int
+	reviewOffset int
+	reviewReturn string // State to return to when leaving the review screen
+	cleanReturn  string // State to return to after a batch clean
+	// Category info screen
+	infoCategory string
+	infoReturn   string // State to return to when leaving the info screen
+	// Show absolute paths instead of ~/… forms
+	absolutePaths bool
+	// Render without animations
+	plain bool
+	// Synthetic data mode: scans are generated and removals are simulated
+	demo   bool
+	remove func(path string) error
+	// Scan history
+	historyFile    string
+	scanKind       string // Kind of the scan in progress: "full", "dev" or "quick"
+	historyEntries []history.Entry
+	historyOffset  int
+	baseline       *history.Entry // Previous scan of the same kind, nil if none
+	timeline       []timelinePoint
+	timelineDisk   int64
+	// Deletion audit log
+	auditFile    string
+	auditRecords []audit.Record
+	auditChoice  int // Index into the records shown newest first
+	// Third-party launch agents and daemons
+	launchJobs    []launchd.Job
+	launchChoice  int
+	launchMessage string // Outcome of the last removal
+	// Reported vs. measured space freed by the last clean
+	freedCheck *freedCheck
+	// Release notes shown after an update
+	whatsNew []changelog.Section
+	// User rules for renaming, merging and splitting categories
+	grouping *grouping.Config
+	// Installed scheduled clean, shown on the menu
+	schedule *schedule.Config
+	// Free and total bytes on the home volume, for the low space banner
+	diskFree, diskTotal int64
+	// Whether the terminal reported losing focus, so finished work is
+	// announced with a notification
+	blurred bool
+	// Status file for external monitors (empty disables it)
+	statusFile string
+	// Clock the views read, fixed in tests so renders are reproducible
+	now func() time.Time
+}
+
+// Options configures the model at startup
+type Options struct {
+	StatusFile        string // Path to publish scan state to, empty to disable
+	Plain             bool   // Render without spinners for dumb terminals
+	Demo              bool   // Use synthetic results and never touch the file system
+	HistoryFile       string // Path of the scan history file, empty to disable
+	AuditFile         string // Path of the deletion audit log, empty to disable
+	Plugins           []*plugin.Plugin
+	Disabled          []string            // Names of built-in scanners to skip
+	Roots             []string            // Folders cleaned of .DS_Store and AppleDouble files, nil for the home folder and mounted volumes
+	WhatsNew          []changelog.Section // Changes since the last version run, shown once at startup
+	InstallerAge      int                 // Days before disk images and packages are listed, 0 for the default
+	ScreenshotAge     int                 // Days before Desktop screenshots are listed, 0 for the default
+	ScreenshotArchive string              // Folder old screenshots are moved into, empty for the Trash
+	Grouping          *grouping.Config    // How to rename, merge and split categories, nil for none
+	Schedule          *schedule.Config    // Installed scheduled clean, nil for none
+	Sizes             *sizecache.Cache    // Directory sizes cached between scans, nil to measure everything
+	// Deletion limits in ops/sec keyed by mount type, nil for no limits
+	DeleteRates map[string]float64
 }
 
 // Initialize the model
-func InitialModel() Model {
+func InitialModel(opts Options) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
+	pi := textinput.New()
+	pi.Prompt = "Mark matching: "
+	pi.Placeholder = "*old*  or  */archive/*  or  >90d"
+
+	ri := textinput.New()
+	ri.Prompt = "Find largest files in: "
+
+	sc := scanner.NewScanner()
+	remove := utils.RemovePath
+	if opts.DeleteRates != nil {
+		remove = utils.RateLimitedRemover(opts.DeleteRates)
+	}
+	sc.Plugins = opts.Plugins
+	sc.Sizes = opts.Sizes
+	sc.Roots = opts.Roots
+	sc.InstallerAge = opts.InstallerAge
+	sc.ScreenshotAge = opts.ScreenshotAge
+	sc.ScreenshotArchive = opts.ScreenshotArchive
+	sc.Disabled = make(map[string]bool, len(opts.Disabled))
+	for _, name := range opts.Disabled {
+		sc.Disabled[name] = true
+	}
+	if opts.Demo {
+		sc.HomeDir = demo.HomeDir
+		sc.Plugins = nil
+		sc.Sizes = nil
+		remove = func(string) error { return nil }
+	}
+	// Category strategies that delete go through the same rate limits
+	sc.Remove = remove
+
+	state := "menu"
+	if len(opts.WhatsNew) > 0 {
+		state = "whatsnew"
+	}
+
 	return Model{
-		scanner:     scanner.NewScanner(),
-		state:       "menu",
-		spinner:     s,
-		progress:    progress.New(progress.WithDefaultGradient()),
-		markedItems: make(map[string]bool),
+		scanner:      sc,
+		state:        state,
+		whatsNew:     opts.WhatsNew,
+		spinner:      s,
+		progress:     progress.New(progress.WithDefaultGradient()),
+		markedItems:  make(map[string]markedItem),
+		patternInput: pi,
+		rootInput:    ri,
+		statusFile:   opts.StatusFile,
+		historyFile:  opts.HistoryFile,
+		auditFile:    opts.AuditFile,
+		plain:        opts.Plain,
+		demo:         opts.Demo,
+		remove:       remove,
+		grouping:     opts.Grouping,
+		schedule:     opts.Schedule,
+		now:          time.Now,
 	}
 }
 
 // Init starts the spinner
 func (m Model) Init() tea.Cmd {
-	return m.spinner.Tick
+	if m.plain {
+		return m.checkDiskSpace()
+	}
+	return tea.Batch(m.spinner.Tick, m.checkDiskSpace())
+}
+
+// spinnerView renders the spinner, or a static marker in plain mode
+func (m Model) spinnerView() string {
+	if m.plain {
+		return "*"
+	}
+	return m.spinner.View()
 }
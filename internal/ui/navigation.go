@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// navFrame is a cached detail listing that can be returned to with backspace
+type navFrame struct {
+	dir    string // Directory being listed, empty for the category root
+	items  []types.FileItem
+	choice int
+	offset int
+}
+
+// pushDirectory enters dir, saving the current listing on the navigation stack
+func (m *Model) pushDirectory(dir string, items []types.FileItem) {
+	m.navStack = append(m.navStack, navFrame{
+		dir:    m.currentDir,
+		items:  m.detailItems,
+		choice: m.detailChoice,
+		offset: m.detailOffset,
+	})
+	m.currentDir = dir
+	m.currentPath = append(m.currentPath, filepath.Base(dir))
+	m.detailItems = items
+	m.detailChoice = 0
+	m.detailOffset = 0
+}
+
+// popDirectory returns to the previous listing, reporting whether there was one
+func (m *Model) popDirectory() bool {
+	if len(m.navStack) == 0 {
+		return false
+	}
+
+	frame := m.navStack[len(m.navStack)-1]
+	m.navStack = m.navStack[:len(m.navStack)-1]
+	m.currentDir = frame.dir
+	m.currentPath = m.currentPath[:len(m.currentPath)-1]
+	m.detailItems = frame.items
+	m.detailChoice = min(frame.choice, max(0, len(frame.items)-1))
+	m.detailOffset = min(frame.offset, m.detailChoice)
+	return true
+}
+
+// resetNavigation clears the directory stack when leaving the detail view
+func (m *Model) resetNavigation() {
+	m.navStack = nil
+	m.currentDir = ""
+}
+
+// shrinkAncestors subtracts freed bytes from cached listings that contain path
+func (m *Model) shrinkAncestors(path string, freed int64) {
+	for i := range m.navStack {
+		items := m.navStack[i].items
+		for j := range items {
+			if strings.HasPrefix(path, items[j].Path+string(filepath.Separator)) {
+				// Copy before modifying so category results are not aliased
+				updated := make([]types.FileItem, len(items))
+				copy(updated, items)
+				updated[j].Size -= freed
+				m.navStack[i].items = updated
+
+				// Keep the category root in sync for the next visit
+				if m.navStack[i].dir == "" {
+					if result, exists := m.results[m.currentCategory]; exists {
+						result.Items = updated
+					}
+				}
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/scanner"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func TestNavigationStack(t *testing.T) {
+	m := InitialModel(Options{})
+	m.results = map[string]*types.ScanResult{
+		"Cache Files": {
+			Category: "Cache Files",
+			Items: []types.FileItem{
+				{Path: "/c/a", Name: "a", Size: 100, IsDir: true},
+				{Path: "/c/b", Name: "b", Size: 50, IsDir: true},
+			},
+			Total: 150,
+		},
+	}
+	m.state = "detail"
+	m.currentCategory = "Cache Files"
+	m.currentPath = []string{"Cache Files"}
+	m.detailItems = m.results["Cache Files"].Items
+	m.detailChoice = 1
+
+	m.pushDirectory("/c/b", []types.FileItem{{Path: "/c/b/x", Name: "x", Size: 50, IsDir: true}})
+	m.pushDirectory("/c/b/x", []types.FileItem{{Path: "/c/b/x/file", Name: "file", Size: 20}})
+
+	if got := len(m.currentPath); got != 3 {
+		t.Fatalf("breadcrumb depth = %d, want 3", got)
+	}
+
+	// Deleting below b must shrink every cached ancestor, even after the
+	// current listing becomes empty
+	m.shrinkAncestors("/c/b/x/file", 20)
+	m.detailItems = nil
+
+	if !m.popDirectory() {
+		t.Fatal("popDirectory() = false at depth 3")
+	}
+	if m.currentDir != "/c/b" || m.detailItems[0].Size != 30 {
+		t.Errorf("after first pop: dir = %q, items = %+v", m.currentDir, m.detailItems)
+	}
+
+	if !m.popDirectory() {
+		t.Fatal("popDirectory() = false at depth 2")
+	}
+	if m.currentDir != "" || m.detailChoice != 1 || m.detailItems[1].Size != 30 {
+		t.Errorf("after second pop: dir = %q, choice = %d, items = %+v", m.currentDir, m.detailChoice, m.detailItems)
+	}
+	if size := m.results["Cache Files"].Items[1].Size; size != 30 {
+		t.Errorf("category item size = %d, want 30", size)
+	}
+
+	if m.popDirectory() {
+		t.Error("popDirectory() = true at the category root")
+	}
+}
+
+func TestLargestFilesOpenDetail(t *testing.T) {
+	m := InitialModel(Options{})
+	m.markedItems["/old/scan"] = markedItem{item: types.FileItem{Path: "/old/scan"}}
+
+	updated, _ := m.Update(largestFilesMsg{result: &types.ScanResult{
+		Category: scanner.LargestFilesCategory,
+		Items:    []types.FileItem{{Path: "/u/big.dmg", Name: "📄 big.dmg", Size: 40_000}},
+		Total:    40_000,
+	}})
+	m = updated.(Model)
+
+	if m.state != "detail" || m.currentCategory != scanner.LargestFilesCategory || len(m.detailItems) != 1 {
+		t.Errorf("state %q, category %q, items %+v", m.state, m.currentCategory, m.detailItems)
+	}
+	if len(m.markedItems) != 0 || m.totalSize != 40_000 {
+		t.Errorf("marked %v, total %d", m.markedItems, m.totalSize)
+	}
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/notify"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// notifyAfter is how long a scan or clean must take before finishing it
+// in the background is worth a notification
+const notifyAfter = 10 * time.Second
+
+// notifyDone posts a notification when work that took a while finishes
+// while the terminal is in the background
+func (m Model) notifyDone(took time.Duration, message string) tea.Cmd {
+	if !m.blurred || took < notifyAfter || m.demo {
+		return nil
+	}
+	return func() tea.Msg {
+		// Notifications are a courtesy; failing to post one isn't an error
+		notify.Send("mac-cleaner", message)
+		return nil
+	}
+}
+
+// notifyScanDone summarizes a finished scan
+func (m Model) notifyScanDone(msg types.ScanCompleteMsg) tea.Cmd {
+	if msg.Stats == nil {
+		return nil
+	}
+	items := 0
+	for _, result := range msg.Results {
+		items += len(result.Items)
+	}
+	return m.notifyDone(msg.Stats.Duration, fmt.Sprintf("Scan finished: %d items, %s reclaimable", items, humanize.Bytes(uint64(msg.TotalSize))))
+}
+
+// notifyCleanDone summarizes a finished batch clean
+func (m Model) notifyCleanDone(msg types.BatchCleanCompleteMsg) tea.Cmd {
+	message := fmt.Sprintf("Clean finished: freed %s (%d items)", humanize.Bytes(uint64(msg.Freed)), len(msg.Paths))
+	if len(msg.Errors) > 0 {
+		message += fmt.Sprintf(", %d failed", len(msg.Errors))
+	}
+	return m.notifyDone(msg.Duration, message)
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func TestNotifyOnlyInBackground(t *testing.T) {
+	m := fixtureModel(80, 30)
+	long := types.ScanCompleteMsg{Results: m.results, Stats: &types.ScanStats{Duration: time.Minute}}
+
+	if m.notifyScanDone(long) != nil {
+		t.Error("notified while the terminal has focus")
+	}
+
+	updated, _ := m.Update(tea.BlurMsg{})
+	m = updated.(Model)
+	if m.notifyScanDone(long) == nil {
+		t.Error("no notification for a long scan finishing in the background")
+	}
+	if m.notifyScanDone(types.ScanCompleteMsg{Stats: &types.ScanStats{Duration: time.Second}}) != nil {
+		t.Error("notified for a quick scan")
+	}
+	if m.notifyCleanDone(types.BatchCleanCompleteMsg{Duration: time.Minute}) == nil {
+		t.Error("no notification for a long clean finishing in the background")
+	}
+
+	updated, _ = m.Update(tea.FocusMsg{})
+	m = updated.(Model)
+	if m.notifyCleanDone(types.BatchCleanCompleteMsg{Duration: time.Minute}) != nil {
+		t.Error("notified after the terminal regained focus")
+	}
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// ColorDisabled reports whether colors should be turned off, following the
+// NO_COLOR convention and TERM=dumb
+func ColorDisabled() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return true
+	}
+	return os.Getenv("TERM") == "dumb"
+}
+
+// StdoutIsTerminal reports whether stdout is an interactive terminal
+func StdoutIsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// DisableColor switches all styles to uncolored output
+func DisableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// RunPlain runs a full scan, including plugins, without the TUI and writes
+// a plain-text report to w, for use when output is piped or captured
+func RunPlain(w io.Writer, opts Options) error {
+	s := InitialModel(opts).scanner
+	msg, ok := performScan(s)().(types.ScanCompleteMsg)
+	if !ok {
+		return fmt.Errorf("scan did not complete")
+	}
+
+	msg.Results = opts.Grouping.Apply(msg.Results, utils.RemovePath)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CATEGORY\tITEMS\tSIZE")
+
+	var items int
+	for _, category := range utils.GetSortedCategories(msg.Results) {
+		result := msg.Results[category]
+		if result.Advisory {
+			fmt.Fprintf(tw, "%s (report only)\t%d\t%s\n", category, len(result.Items), utils.FormatFileSize(result.Total))
+			continue
+		}
+		items += len(result.Items)
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", category, len(result.Items), utils.FormatFileSize(result.Total))
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t%s\n", items, utils.FormatFileSize(msg.TotalSize))
+
+	return tw.Flush()
+}
@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// markedItem is an item selected for deletion, remembered across categories
+type markedItem struct {
+	item     types.FileItem
+	category string
+}
+
+// isMarked reports whether path is currently selected
+func (m Model) isMarked(path string) bool {
+	_, ok := m.markedItems[path]
+	return ok
+}
+
+// reportOnly reports whether the current category is advisory, so its
+// items can't be marked or cleaned
+func (m Model) reportOnly() bool {
+	result, ok := m.results[m.currentCategory]
+	return ok && result.Advisory
+}
+
+// toggleMark selects or deselects item in the current category
+func (m *Model) toggleMark(item types.FileItem) {
+	if m.reportOnly() {
+		return
+	}
+	if m.isMarked(item.Path) {
+		delete(m.markedItems, item.Path)
+		return
+	}
+	m.markedItems[item.Path] = markedItem{item: item, category: m.currentCategory}
+}
+
+// markAll selects every item in the current listing
+func (m *Model) markAll() {
+	if m.reportOnly() {
+		return
+	}
+	for _, item := range m.detailItems {
+		m.markedItems[item.Path] = markedItem{item: item, category: m.currentCategory}
+	}
+}
+
+// invertMarks flips the selection of every item in the current listing
+func (m *Model) invertMarks() {
+	for _, item := range m.detailItems {
+		m.toggleMark(item)
+	}
+}
+
+// markMatching selects items in the current listing whose name or path
+// matches pattern, or that are older than the days in a ">90d" pattern,
+// returning how many matched
+func (m *Model) markMatching(pattern string) int {
+	if m.reportOnly() {
+		return 0
+	}
+	var match func(item types.FileItem) bool
+	if days, ok := olderThan(pattern); ok {
+		match = func(item types.FileItem) bool { return item.Age > days }
+	} else {
+		glob := utils.GlobMatcher(pattern)
+		match = func(item types.FileItem) bool { return glob(item.Name) || glob(item.Path) }
+	}
+	count := 0
+	for _, item := range m.detailItems {
+		if match(item) {
+			m.markedItems[item.Path] = markedItem{item: item, category: m.currentCategory}
+			count++
+		}
+	}
+	return count
+}
+
+// staleBuildDays is how long a DerivedData folder can go without a build
+// before Shift+B marks it
+const staleBuildDays = 30
+
+// isDerivedData reports whether item is a project's Xcode DerivedData folder
+func isDerivedData(item types.FileItem) bool {
+	return filepath.Base(filepath.Dir(item.Path)) == "DerivedData"
+}
+
+// markStaleBuilds selects the DerivedData folders in the current listing
+// whose project wasn't built for staleBuildDays, returning how many
+func (m *Model) markStaleBuilds() int {
+	if m.reportOnly() {
+		return 0
+	}
+	count := 0
+	for _, item := range m.detailItems {
+		if isDerivedData(item) && item.Age > staleBuildDays {
+			m.markedItems[item.Path] = markedItem{item: item, category: m.currentCategory}
+			count++
+		}
+	}
+	return count
+}
+
+// olderThan parses an age pattern such as ">90d" into a number of days
+func olderThan(pattern string) (int, bool) {
+	rest, ok := strings.CutPrefix(pattern, ">")
+	if !ok {
+		return 0, false
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(rest), "d"))
+	if err != nil || days < 0 {
+		return 0, false
+	}
+	return days, true
+}
+
+// markEverything selects every item in every scanned category
+func (m *Model) markEverything() {
+	for category, result := range m.results {
+		if result.Advisory {
+			continue
+		}
+		for _, item := range result.Items {
+			m.markedItems[item.Path] = markedItem{item: item, category: category}
+		}
+	}
+}
+
+// dropEmptyCategories removes categories with nothing left in them from the
+// results, keeping the results cursor in range
+func (m *Model) dropEmptyCategories() {
+	for category, result := range m.results {
+		if len(result.Items) == 0 {
+			delete(m.results, category)
+		}
+	}
+	m.menuChoice = min(m.menuChoice, len(m.results))
+}
+
+// markedSize returns the combined size of all selected items. Items inside
+// a marked directory go with it, so they aren't counted again.
+func (m Model) markedSize() int64 {
+	var size int64
+	for path, marked := range m.markedItems {
+		if !m.hasMarkedAncestor(path) {
+			size += marked.item.Size
+		}
+	}
+	return size
+}
+
+// hasMarkedAncestor reports whether a folder holding path is marked. It
+// looks up each parent rather than comparing against every mark, as
+// utils.HasPathPrefix would, since selections can hold thousands of items.
+func (m Model) hasMarkedAncestor(path string) bool {
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if _, ok := m.markedItems[dir]; ok {
+			return true
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return false
+		}
+	}
+}
+
+// sortedMarked returns the selection ordered by category, then by path
+func (m Model) sortedMarked() []markedItem {
+	items := make([]markedItem, 0, len(m.markedItems))
+	for _, marked := range m.markedItems {
+		items = append(items, marked)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].category != items[j].category {
+			return items[i].category < items[j].category
+		}
+		return items[i].item.Path < items[j].item.Path
+	})
+	return items
+}
+
+// markedFileItems returns the selected items for a batch clean
+func (m Model) markedFileItems() []types.FileItem {
+	items := make([]types.FileItem, 0, len(m.markedItems))
+	for _, marked := range m.sortedMarked() {
+		items = append(items, marked.item)
+	}
+	return items
+}
+
+// forgetDeleted drops deleted paths, and anything below them, from the
+// selection, the current listing and the category results
+func (m *Model) forgetDeleted(paths []string) {
+	deleted := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		deleted[path] = true
+	}
+	isDeleted := func(path string) bool {
+		return deleted[path] || utils.HasPathPrefix(path, paths)
+	}
+
+	for _, path := range paths {
+		marked, ok := m.markedItems[path]
+		if !ok {
+			continue
+		}
+		m.shrinkAncestors(path, marked.item.Size)
+		if result, exists := m.results[marked.category]; exists {
+			result.Total -= marked.item.Size
+		}
+	}
+
+	for path := range m.markedItems {
+		if isDeleted(path) {
+			delete(m.markedItems, path)
+		}
+	}
+
+	m.detailItems = filterItems(m.detailItems, isDeleted)
+	for _, result := range m.results {
+		result.Items = filterItems(result.Items, isDeleted)
+	}
+}
+
+// filterItems returns items whose path is not matched by drop
+func filterItems(items []types.FileItem, drop func(path string) bool) []types.FileItem {
+	kept := []types.FileItem{}
+	for _, item := range items {
+		if !drop(item.Path) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func TestAdvisoryItemsCannotBeMarked(t *testing.T) {
+	m := fixtureModel(80, 30)
+	m.results["Sound Libraries"] = &types.ScanResult{
+		Category: "Sound Libraries",
+		Items:    []types.FileItem{{Path: "/Library/Audio/Apple Loops", Name: "Apple Loops", Size: 1_000}},
+		Total:    1_000,
+		Advisory: true,
+	}
+	m.state = "detail"
+	m.currentCategory = "Sound Libraries"
+	m.detailItems = m.results["Sound Libraries"].Items
+
+	m.toggleMark(m.detailItems[0])
+	m.markAll()
+	m.invertMarks()
+	if n := m.markMatching("*"); n != 0 || len(m.markedItems) != 0 {
+		t.Fatalf("marked %d items in a report-only category: %v", len(m.markedItems), m.markedItems)
+	}
+
+	m.markEverything()
+	if m.isMarked("/Library/Audio/Apple Loops") {
+		t.Error("markEverything marked a report-only item")
+	}
+	if got, want := len(m.markedItems), 4; got != want {
+		t.Errorf("markEverything marked %d items, want %d", got, want)
+	}
+}
+
+func TestMarkedSizeCountsFoldersOnce(t *testing.T) {
+	m := fixtureModel(80, 30)
+	m.state = "detail"
+	m.currentCategory = "Cache Files"
+
+	// A folder, a file inside it found while exploring, and a sibling whose
+	// name only starts the same
+	m.toggleMark(types.FileItem{Path: "/Users/dev/Library/Caches/app", Size: 1_000, IsDir: true})
+	m.toggleMark(types.FileItem{Path: "/Users/dev/Library/Caches/app/blobs/1", Size: 400})
+	m.toggleMark(types.FileItem{Path: "/Users/dev/Library/Caches/app-helper", Size: 50, IsDir: true})
+
+	if got, want := m.markedSize(), int64(1_050); got != want {
+		t.Errorf("markedSize() = %d, want %d", got, want)
+	}
+}
+
+func TestQuickCleanOneKey(t *testing.T) {
+	m := fixtureModel(80, 30)
+	m.state = "results"
+
+	// Other scans never clean everything from a single key
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if got := next.(Model).state; got != "results" {
+		t.Fatalf("c after a full scan moved to %q", got)
+	}
+
+	// After a quick scan c opens the summary, and only y cleans
+	m.scanKind = "quick"
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if got := next.(Model).state; got != "confirmAll" {
+		t.Fatalf("c after a quick scan moved to %q, want confirmAll", got)
+	}
+	next, cmd := next.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	quick := next.(Model)
+	if quick.state != "cleaning" || cmd == nil {
+		t.Fatalf("y on the summary: state %q, want cleaning", quick.state)
+	}
+	if got, want := len(quick.markedItems), m.getTotalItems(); got != want {
+		t.Errorf("marked %d items, want all %d", got, want)
+	}
+}
+
+func TestMarkOlderThan(t *testing.T) {
+	m := fixtureModel(80, 30)
+	m.state = "detail"
+	m.currentCategory = "Xcode Files"
+	m.detailItems = []types.FileItem{
+		{Path: "/a.xcarchive", Name: "📦 Archive: App 1.0 (9 Mar 2024)", Age: 200},
+		{Path: "/b.xcarchive", Name: "📦 Archive: App 1.1 (1 Sep 2024)", Age: 91},
+		{Path: "/c.xcarchive", Name: "📦 Archive: App 1.2 (28 Sep 2024)", Age: 20},
+	}
+
+	if n := m.markMatching(">90d"); n != 2 || !m.isMarked("/a.xcarchive") || m.isMarked("/c.xcarchive") {
+		t.Errorf("marked %d items older than 90 days: %v", n, m.markedItems)
+	}
+	// Not an age, so it is matched as a name
+	if n := m.markMatching(">old"); n != 0 {
+		t.Errorf(">old marked %d items", n)
+	}
+}
+
+func TestMarkStaleBuilds(t *testing.T) {
+	m := fixtureModel(80, 30)
+	m.state = "detail"
+	m.currentCategory = "Xcode Files"
+	m.detailItems = []types.FileItem{
+		{Path: "/Users/dev/Library/Developer/Xcode/DerivedData/App-abc", Name: "🔨 code/App.xcodeproj", Age: 45},
+		{Path: "/Users/dev/Library/Developer/Xcode/DerivedData/Web-def", Name: "🔨 code/Web.xcodeproj", Age: 2},
+		{Path: "/Users/dev/Library/Developer/Xcode/Archives/2024-03-09/App.xcarchive", Name: "📦 Archive: App", Age: 200},
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	got := next.(Model)
+	if got.state != "review" || len(got.markedItems) != 1 || !got.isMarked(m.detailItems[0].Path) {
+		t.Errorf("Shift+B: state %q, marked %v", got.state, got.markedItems)
+	}
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// maxStatsRows caps the scanners listed in the expanded scan summary
+const maxStatsRows = 10
+
+// renderScanStats summarizes what the last scan cost, with the slowest
+// scanners listed when expanded
+func (m Model) renderScanStats() string {
+	st := m.scanStats
+	if st == nil {
+		return ""
+	}
+	var s strings.Builder
+
+	arrow, hint := "▸", "s for details"
+	if m.statsExpanded {
+		arrow, hint = "▾", "s to hide"
+	}
+	summary := fmt.Sprintf("%s Scan took %s • %s dirs • %s files • peak %s",
+		arrow, formatDuration(st.Duration), humanize.Comma(st.Dirs()), humanize.Comma(st.Files()), humanize.Bytes(st.PeakMemory))
+	s.WriteString("  " + DimStyle.Render(summary) + " " + DimStyle.Render("("+hint+")") + "\n")
+	if !m.statsExpanded {
+		return s.String()
+	}
+
+	header := fmt.Sprintf("    %s %8s  %10s  %10s  %s", utils.PadRight("Scanner", 26), "Time", "Dirs", "Files", "Skip with")
+	s.WriteString(DimStyle.Render(header) + "\n")
+	for i, sc := range st.Scanners {
+		if i == maxStatsRows {
+			s.WriteString(DimStyle.Render(fmt.Sprintf("    … %d more", len(st.Scanners)-maxStatsRows)) + "\n")
+			break
+		}
+		skip := sc.Name
+		if skip == "" {
+			skip = "(plugin)"
+		}
+		s.WriteString(fmt.Sprintf("    %s %8s  %10s  %10s  %s\n",
+			utils.PadRight(utils.TruncateMiddle(sc.Category, 26), 26),
+			formatDuration(sc.Duration),
+			humanize.Comma(sc.Dirs),
+			humanize.Comma(sc.Files),
+			DimStyle.Render(skip)))
+	}
+	s.WriteString("    " + DimStyle.Render("Skip slow scanners with -skip <name>") + "\n")
+	return s.String()
+}
+
+// formatDuration rounds d for display: milliseconds below a second, tenths
+// of a second above
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -1,13 +1,18 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dustin/go-humanize"
 
+	"github.com/rahulvramesh/cleanWithCli/internal/scanner"
+	"github.com/rahulvramesh/cleanWithCli/internal/status"
 	"github.com/rahulvramesh/cleanWithCli/internal/types"
 	"github.com/rahulvramesh/cleanWithCli/internal/utils"
 )
@@ -31,7 +36,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case diskSpaceMsg:
+		m.diskFree, m.diskTotal = msg.free, msg.total
+		return m, nil
+
+	case tea.FocusMsg:
+		m.blurred = false
+		return m, nil
+
+	case tea.BlurMsg:
+		m.blurred = true
+		return m, nil
+
 	case tea.KeyMsg:
+		// The pattern prompt takes all keys while it is open
+		if m.patternInput.Focused() {
+			return m.updatePatternInput(msg)
+		}
+		if m.rootInput.Focused() {
+			return m.updateRootInput(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			if m.state == "diskusage" {
@@ -43,33 +68,57 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "enter":
 			switch m.state {
+			case "whatsnew":
+				m.state = "menu"
 			case "menu":
 				switch m.menuChoice {
 				case 0: // Full Scan
 					m.state = "scanning"
+					m.scanKind = "full"
 					return m, tea.Batch(
 						m.spinner.Tick,
-						performScan(m.scanner),
+						m.runScan(performScan),
+						m.publishStatus(status.StateScanning),
 					)
 				case 1: // Dev Scan
 					m.state = "scanning"
+					m.scanKind = "dev"
 					m.scanMessage = "Starting Dev Scan - Deep scanning all projects..."
 					m.scanningPaths = []string{}
 					m.scanFoundItems = 0
 					m.scanTotalSize = 0
 					return m, tea.Batch(
 						m.spinner.Tick,
-						performDevScan(m.scanner),
+						m.runScan(performDevScan),
+						m.publishStatus(status.StateScanning),
 					)
 				case 2: // Quick Clean
 					m.state = "scanning"
+					m.scanKind = "quick"
+					m.scanMessage = "Looking for caches, trash and old logs..."
 					return m, tea.Batch(
 						m.spinner.Tick,
-						performScan(m.scanner),
+						m.runScan(performQuickScan),
+						m.publishStatus(status.StateScanning),
 					)
 				case 3: // Disk Usage
-					return m, showDiskUsage()
-				case 4: // Exit
+					m.spaceReport = nil
+					m.spaceMessage = ""
+					return m, tea.Batch(showDiskUsage(), m.loadSpaceReport())
+				case 4: // Scan History
+					return m, loadHistory(m.historyFile)
+				case 5: // Disk Timeline
+					return m, loadTimeline(m.historyFile, m.scanner.HomeDir)
+				case 6: // Deletion Log
+					return m, loadAudit(m.auditFile)
+				case 7: // Launch Agents
+					m.launchMessage = ""
+					return m, m.loadLaunchJobs()
+				case 8: // Largest Files
+					m.rootInput.SetValue(m.scanner.HomeDir)
+					m.rootInput.CursorEnd()
+					return m, m.rootInput.Focus()
+				case 9: // Exit
 					return m, tea.Quit
 				}
 			case "results":
@@ -87,7 +136,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.detailItems = m.results[category].Items
 						m.detailChoice = 0
 						m.detailOffset = 0
-						m.markedItems = make(map[string]bool) // Reset marked items
+						m.resetNavigation()
 						m.state = "detail"
 					}
 				}
@@ -96,7 +145,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					item := m.detailItems[m.detailChoice]
 					if item.IsDir {
 						// Explore subdirectory
-						return m, exploreDirectory(&m, item.Path)
+						return m, exploreDirectory(item.Path)
 					}
 				}
 			}
@@ -114,6 +163,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				var cmd tea.Cmd
 				m.diskUsageTable, cmd = m.diskUsageTable.Update(msg)
 				return m, cmd
+			} else if m.state == "history" {
+				if m.historyOffset > 0 {
+					m.historyOffset--
+				}
+			} else if m.state == "audit" {
+				if m.auditChoice > 0 {
+					m.auditChoice--
+				}
+			} else if m.state == "launchd" {
+				if m.launchChoice > 0 {
+					m.launchChoice--
+				}
+			} else if m.state == "review" {
+				if m.reviewChoice > 0 {
+					m.reviewChoice--
+					if m.reviewChoice < m.reviewOffset {
+						m.reviewOffset = m.reviewChoice
+					}
+				}
+			} else if m.state == "preview" {
+				if m.previewOffset > 0 {
+					m.previewOffset--
+				}
 			} else if m.state == "detail" {
 				if m.detailChoice > 0 {
 					m.detailChoice--
@@ -126,7 +198,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "down", "j":
 			if m.state == "menu" {
-				if m.menuChoice < 4 {
+				if m.menuChoice < len(menuItems)-1 {
 					m.menuChoice++
 				}
 			} else if m.state == "results" {
@@ -137,6 +209,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				var cmd tea.Cmd
 				m.diskUsageTable, cmd = m.diskUsageTable.Update(msg)
 				return m, cmd
+			} else if m.state == "history" {
+				if m.historyOffset < len(m.historyEntries)-1 {
+					m.historyOffset++
+				}
+			} else if m.state == "audit" {
+				if m.auditChoice < len(m.auditRecords)-1 {
+					m.auditChoice++
+				}
+			} else if m.state == "launchd" {
+				if m.launchChoice < len(m.launchJobs)-1 {
+					m.launchChoice++
+				}
+			} else if m.state == "review" {
+				if m.reviewChoice < len(m.markedItems)-1 {
+					m.reviewChoice++
+					viewportHeight := m.height - 15
+					if m.reviewChoice >= m.reviewOffset+viewportHeight {
+						m.reviewOffset = m.reviewChoice - viewportHeight + 1
+					}
+				}
+			} else if m.state == "preview" {
+				if m.preview != nil && m.previewOffset < strings.Count(m.preview.Text, "\n") {
+					m.previewOffset++
+				}
 			} else if m.state == "detail" {
 				if m.detailChoice < len(m.detailItems)-1 {
 					m.detailChoice++
@@ -165,26 +261,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "backspace", "delete":
-			if m.state == "detail" && len(m.currentPath) > 1 {
+			if m.state == "detail" {
 				// Go back one level in detail view
-				m.currentPath = m.currentPath[:len(m.currentPath)-1]
-				if len(m.currentPath) == 1 {
-					// Back to category root
-					m.detailItems = m.results[m.currentCategory].Items
-				} else {
-					// Reload parent directory
-					return m, exploreDirectory(&m, filepath.Dir(m.detailItems[0].Path))
-				}
-				m.detailChoice = 0
-				m.detailOffset = 0
+				m.popDirectory()
 			}
 
 		case "esc":
-			if m.state == "detail" {
+			if m.state == "whatsnew" {
+				m.state = "menu"
+			} else if m.state == "info" {
+				m.state = m.infoReturn
+			} else if m.state == "preview" {
+				m.state = "detail"
+				m.preview = nil
+			} else if m.state == "detail" {
 				m.state = "results"
+				m.scanMessage = ""
 				m.detailChoice = 0
-				m.markedItems = make(map[string]bool) // Reset marked items
-			} else if m.state == "results" || m.state == "cleaning" || m.state == "diskusage" {
+				m.resetNavigation()
+			} else if m.state == "review" {
+				m.state = m.reviewReturn
+			} else if m.state == "confirmAll" {
+				m.state = "results"
+			} else if m.state == "results" || m.state == "cleaning" || m.state == "diskusage" || m.state == "history" || m.state == "timeline" || m.state == "audit" || m.state == "launchd" {
 				m.state = "menu"
 				m.menuChoice = 0
 			}
@@ -192,57 +291,186 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case " ": // Space key
 			// Toggle marking of selected item in detail view
 			if m.state == "detail" && m.detailChoice < len(m.detailItems) {
-				item := m.detailItems[m.detailChoice]
-				if m.markedItems[item.Path] {
-					delete(m.markedItems, item.Path)
-				} else {
-					m.markedItems[item.Path] = true
+				m.toggleMark(m.detailItems[m.detailChoice])
+			} else if m.state == "review" {
+				// Unmark the selected item in the review list
+				marked := m.sortedMarked()
+				if m.reviewChoice < len(marked) {
+					delete(m.markedItems, marked[m.reviewChoice].item.Path)
+					m.reviewChoice = min(m.reviewChoice, max(0, len(m.markedItems)-1))
 				}
 			}
 
 		case "A": // Shift+A
 			// Mark all items in detail view
 			if m.state == "detail" {
-				for _, item := range m.detailItems {
-					m.markedItems[item.Path] = true
-				}
+				m.markAll()
+			}
+
+		case "I": // Shift+I
+			// Invert marks in detail view
+			if m.state == "detail" {
+				m.invertMarks()
 			}
 
 		case "N": // Shift+N
 			// Unmark all items
+			if m.state == "detail" || m.state == "review" {
+				m.markedItems = make(map[string]markedItem)
+				m.reviewChoice = 0
+			}
+
+		case "B": // Shift+B
+			// Mark DerivedData of projects not built for a while and review them
+			if m.state == "detail" {
+				n := m.markStaleBuilds()
+				m.scanMessage = fmt.Sprintf("✅ Marked %d projects not built in %d days", n, staleBuildDays)
+				if n > 0 {
+					m.reviewReturn = m.state
+					m.reviewChoice = 0
+					m.reviewOffset = 0
+					m.state = "review"
+				}
+			}
+
+		case "X": // Shift+X
+			// Ask for confirmation before cleaning every scanned item
+			if m.state == "results" && m.getTotalItems() > 0 {
+				m.state = "confirmAll"
+			}
+
+		case "y":
+			// Confirm cleaning every scanned item
+			if m.state == "confirmAll" {
+				return m.cleanEverything()
+			}
+
+		case "n":
+			if m.state == "confirmAll" {
+				m.state = "results"
+			}
+
+		case "/":
+			// Open the prompt to mark items by pattern
 			if m.state == "detail" {
-				m.markedItems = make(map[string]bool)
+				m.patternInput.SetValue("")
+				return m, m.patternInput.Focus()
+			}
+
+		case "s":
+			// Show or hide what the scan cost per scanner
+			if m.state == "results" && m.scanStats != nil {
+				m.statsExpanded = !m.statsExpanded
+			}
+
+		case "t":
+			// Thin local snapshots holding on to deleted files
+			if m.state == "diskusage" && m.spaceReport != nil && len(m.spaceReport.Snapshots) > 0 {
+				m.spaceMessage = "Thinning local snapshots..."
+				return m, m.thinSnapshots()
+			}
+
+		case "i":
+			// Explain the selected category
+			switch m.state {
+			case "results":
+				categories := utils.GetSortedCategories(m.results)
+				if m.menuChoice < len(categories) {
+					m.infoCategory = categories[m.menuChoice]
+					m.infoReturn = m.state
+					m.state = "info"
+				}
+			case "detail":
+				m.infoCategory = m.currentCategory
+				m.infoReturn = m.state
+				m.state = "info"
+			case "info":
+				m.state = m.infoReturn
+			}
+
+		case "~":
+			// Toggle between ~/… and absolute paths
+			m.absolutePaths = !m.absolutePaths
+
+		case "r":
+			// Review everything marked across categories
+			if (m.state == "detail" || m.state == "results") && len(m.markedItems) > 0 {
+				m.reviewReturn = m.state
+				m.reviewChoice = 0
+				m.reviewOffset = 0
+				m.state = "review"
 			}
 
 		case "D": // Shift+D
 			// Delete marked items
-			if m.state == "detail" && len(m.markedItems) > 0 {
+			if (m.state == "detail" || m.state == "review") && len(m.markedItems) > 0 {
+				m.cleanReturn = m.state
+				if m.state == "review" {
+					m.cleanReturn = m.reviewReturn
+				}
 				m.state = "cleaning"
 				m.cleanProgress = 0.0
 				m.scanMessage = fmt.Sprintf("Starting to clean %d marked items...", len(m.markedItems))
 				return m, tea.Batch(
 					m.spinner.Tick,
 					cleanProgressTicker(),
-					performCleanMarkedItemsWithProgress(m.scanner, m.markedItems, m.detailItems),
+					performCleanMarkedItemsWithProgress(m.remover(), m.volumePath(), m.markedFileItems()),
+					m.publishStatus(status.StateCleaning),
 				)
 			}
 
+		case "p":
+			// Preview selected file
+			if m.state == "detail" && m.detailChoice < len(m.detailItems) {
+				item := m.detailItems[m.detailChoice]
+				if !item.IsDir {
+					return m, loadPreview(item.Path)
+				}
+			}
+
+		case "o":
+			// Reveal selected item in Finder
+			if m.state == "detail" && m.detailChoice < len(m.detailItems) {
+				return m, revealInFinder(m.detailItems[m.detailChoice].Path)
+			}
+
 		case "c":
+			// Quick Clean results open the summary by risk with one key;
+			// y runs the clean
+			if m.state == "results" && m.scanKind == "quick" && m.getTotalItems() > 0 {
+				m.state = "confirmAll"
+				return m, nil
+			}
+			// Remove the selected launch job when its program is gone
+			if m.state == "launchd" && m.launchChoice < len(m.launchJobs) {
+				job := m.launchJobs[m.launchChoice]
+				if !job.Stale() {
+					m.launchMessage = job.Label + " still has its program; only stale jobs can be removed"
+					return m, nil
+				}
+				m.launchMessage = "Removing " + job.Label + "..."
+				return m, m.removeLaunchJob(job)
+			}
 			// Clean selected item in detail view
-			if m.state == "detail" && m.detailChoice < len(m.detailItems) {
+			if m.state == "detail" && m.detailChoice < len(m.detailItems) && !m.reportOnly() {
 				item := m.detailItems[m.detailChoice]
 				m.state = "cleaning"
 				m.cleanProgress = 0.0
-				m.scanMessage = fmt.Sprintf("Cleaning %s...", item.Name)
+				m.scanMessage = fmt.Sprintf("Cleaning %s...", utils.SanitizeName(item.Name))
 				return m, tea.Batch(
 					m.spinner.Tick,
 					cleanProgressTicker(),
-					performCleanItemWithProgress(m.scanner, item),
+					performCleanItemWithProgress(m.remover(), m.reporter(), m.volumePath(), item),
+					m.publishStatus(status.StateCleaning),
 				)
 			}
 		}
 
 	case spinner.TickMsg:
+		if m.plain {
+			// Let the animation stop after the first frame
+			return m, nil
+		}
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
@@ -271,13 +499,50 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case types.ScanCompleteMsg:
-		m.results = msg.Results
+		m.err = nil
+		m.markedItems = make(map[string]markedItem) // Selections refer to the previous scan
+		m.baseline = nil
+		m.freedCheck = nil
+		m.results = m.grouping.Apply(msg.Results, m.remove)
 		m.totalSize = msg.TotalSize
+		m.scanStats = msg.Stats
 		m.state = "results"
 		m.menuChoice = 0
-		return m, nil
+		return m, tea.Batch(
+			m.publishStatus(status.StateIdle),
+			m.saveHistory(),
+			m.saveSizes(),
+			m.notifyScanDone(msg),
+		)
+
+	case largestFilesMsg:
+		m.err = nil
+		m.markedItems = make(map[string]markedItem)
+		m.baseline = nil
+		m.freedCheck = nil
+		m.scanStats = nil
+		m.results = map[string]*types.ScanResult{scanner.LargestFilesCategory: msg.result}
+		m.totalSize = msg.result.Total
+		m.menuChoice = 0
+		m.scanMessage = ""
+		m.currentCategory = scanner.LargestFilesCategory
+		m.currentPath = []string{scanner.LargestFilesCategory}
+		m.detailItems = msg.result.Items
+		m.detailChoice = 0
+		m.detailOffset = 0
+		m.resetNavigation()
+		m.state = "detail"
+		return m, m.publishStatus(status.StateIdle)
 
 	case types.CleanCompleteMsg:
+		m.err = msg.Err
+		auditCmd := m.auditClean(msg)
+		if m.state == "cleaning" && msg.Err != nil && !errors.Is(msg.Err, types.ErrNotFound) {
+			// Keep the item in the list so the user can retry or skip it
+			m.state = "detail"
+			m.scanMessage = ""
+			return m, tea.Batch(m.publishStatus(status.StateIdle), auditCmd)
+		}
 		if m.state == "cleaning" {
 			// If we were in detail view, refresh it
 			if msg.Path != "" {
@@ -321,42 +586,49 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 
+				m.shrinkAncestors(msg.Path, msg.Freed)
 				m.totalSize -= msg.Freed
+				m.freedCheck = nil
+				if msg.Measured && msg.Freed > 0 {
+					m.freedCheck = &freedCheck{
+						reported:   msg.Freed,
+						volume:     msg.VolumeFreed,
+						categories: map[string]int64{m.currentCategory: msg.Freed},
+					}
+				}
 				m.state = "detail" // Return to detail view
 
 				// Show success message briefly
-				deletedName := filepath.Base(msg.Path)
+				deletedName := utils.SanitizeName(filepath.Base(msg.Path))
 				m.scanMessage = fmt.Sprintf("✅ Deleted %s (%s)", deletedName, humanize.Bytes(uint64(msg.Freed)))
+				if msg.Report != "" {
+					m.scanMessage += "; " + utils.SanitizeName(msg.Report)
+				}
 			} else {
 				// Regular cleaning from results view
 				m.totalSize -= msg.Freed
 				m.state = "results"
 			}
 		}
-		return m, nil
+		return m, tea.Batch(m.publishStatus(status.StateIdle), m.saveCleanSample(), m.saveSizes(), auditCmd)
 
 	case types.BatchCleanCompleteMsg:
+		m.err = errors.Join(msg.Errors...)
+		auditCmd := m.auditBatchClean(msg)
 		if m.state == "cleaning" {
-			// Remove all deleted items from the list
-			newItems := []types.FileItem{}
-			for _, item := range m.detailItems {
-				isDeleted := false
-				for _, deletedPath := range msg.Paths {
-					if item.Path == deletedPath {
-						isDeleted = true
-						break
+			m.freedCheck = nil
+			if msg.Measured && msg.Freed > 0 {
+				categories := make(map[string]int64)
+				for _, path := range msg.Paths {
+					if marked, ok := m.markedItems[path]; ok {
+						categories[marked.category] += marked.item.Size
 					}
 				}
-				if !isDeleted {
-					newItems = append(newItems, item)
-				}
+				m.freedCheck = &freedCheck{reported: msg.Freed, volume: msg.VolumeFreed, categories: categories}
 			}
-			m.detailItems = newItems
 
-			// Clear marked items for deleted paths
-			for _, deletedPath := range msg.Paths {
-				delete(m.markedItems, deletedPath)
-			}
+			m.forgetDeleted(msg.Paths)
+			m.dropEmptyCategories()
 
 			// Adjust selection if needed
 			if m.detailChoice >= len(m.detailItems) && len(m.detailItems) > 0 {
@@ -371,34 +643,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.detailOffset = m.detailChoice
 			}
 
-			// Update the total size in results
-			if m.currentCategory != "" {
-				if result, exists := m.results[m.currentCategory]; exists {
-					// Update the category's items list
-					newCategoryItems := []types.FileItem{}
-					for _, item := range result.Items {
-						isDeleted := false
-						for _, deletedPath := range msg.Paths {
-							if item.Path == deletedPath {
-								isDeleted = true
-								break
-							}
-						}
-						if !isDeleted {
-							newCategoryItems = append(newCategoryItems, item)
-						}
-					}
-					result.Items = newCategoryItems
-					result.Total -= msg.Freed
-				}
-			}
-
 			m.totalSize -= msg.Freed
-			m.state = "detail" // Return to detail view
+			m.state = m.cleanReturn // Return to where the clean was started
 
 			// Show success message
 			m.scanMessage = fmt.Sprintf("✅ Deleted %d items (%s)", len(msg.Paths), humanize.Bytes(uint64(msg.Freed)))
 		}
+		return m, tea.Batch(m.publishStatus(status.StateIdle), m.saveCleanSample(), m.saveSizes(), auditCmd, m.notifyCleanDone(msg))
+
+	case types.DirectoryListingMsg:
+		if m.state == "detail" {
+			m.pushDirectory(msg.Path, msg.Items)
+		}
+		return m, nil
+
+	case types.PreviewMsg:
+		m.preview = &msg
+		m.previewOffset = 0
+		m.state = "preview"
+		return m, nil
+
+	case historyBaselineMsg:
+		m.baseline = msg.entry
+		return m, nil
+
+	case auditLoadedMsg:
+		m.auditRecords = msg.records
+		m.auditChoice = 0
+		m.state = "audit"
+		return m, nil
+
+	case launchJobsMsg:
+		m.launchJobs = msg.jobs
+		m.launchChoice = 0
+		m.state = "launchd"
+		if len(msg.errs) > 0 {
+			m.err = errors.Join(msg.errs...)
+		}
+		return m, nil
+
+	case launchJobRemovedMsg:
+		return m.launchJobRemoved(msg)
+
+	case timelineLoadedMsg:
+		m.timeline = msg.points
+		m.timelineDisk = msg.disk
+		m.state = "timeline"
+		return m, nil
+
+	case historyLoadedMsg:
+		m.historyEntries = msg.entries
+		m.historyOffset = 0
+		m.state = "history"
 		return m, nil
 
 	case types.DiskUsageMsg:
@@ -406,6 +702,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = "diskusage"
 		return m, nil
 
+	case spaceReportMsg:
+		if msg.err != nil {
+			m.spaceMessage = "Couldn't measure free space: " + msg.err.Error()
+			return m, nil
+		}
+		m.spaceReport = msg.report
+		return m, nil
+
+	case snapshotsThinnedMsg:
+		if msg.err != nil {
+			m.spaceMessage = "Thinning snapshots failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.spaceMessage = "✅ Asked Time Machine to thin local snapshots"
+		return m, m.loadSpaceReport()
+
 	case types.ErrMsg:
 		m.err = msg
 		return m, nil
@@ -419,3 +731,69 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	return m, nil
 }
+
+// updatePatternInput handles keys while the mark-by-pattern prompt is open
+func (m Model) updatePatternInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.patternInput.Blur()
+		return m, nil
+	case "enter":
+		m.patternInput.Blur()
+		if pattern := strings.TrimSpace(m.patternInput.Value()); pattern != "" {
+			count := m.markMatching(pattern)
+			m.scanMessage = fmt.Sprintf("✅ Marked %d items matching %s", count, pattern)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.patternInput, cmd = m.patternInput.Update(msg)
+	return m, cmd
+}
+
+// updateRootInput handles keys while the largest files prompt is open
+func (m Model) updateRootInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.rootInput.Blur()
+		return m, nil
+	case "enter":
+		m.rootInput.Blur()
+		root := strings.TrimSpace(m.rootInput.Value())
+		if root == "~" || strings.HasPrefix(root, "~/") {
+			root = filepath.Join(m.scanner.HomeDir, root[1:])
+		}
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			m.err = types.NewPathError("scan", root, errors.New("not a folder"))
+			return m, nil
+		}
+		m.state = "scanning"
+		m.scanMessage = fmt.Sprintf("Finding the %d largest files in %s...", largestFilesCount, m.displayPath(root))
+		m.scanningPaths = []string{}
+		return m, tea.Batch(
+			m.spinner.Tick,
+			m.findLargestFiles(filepath.Clean(root)),
+			m.publishStatus(status.StateScanning),
+		)
+	}
+
+	var cmd tea.Cmd
+	m.rootInput, cmd = m.rootInput.Update(msg)
+	return m, cmd
+}
+
+// cleanEverything cleans every item of every category that can be cleaned
+func (m Model) cleanEverything() (tea.Model, tea.Cmd) {
+	m.markEverything()
+	m.cleanReturn = "results"
+	m.state = "cleaning"
+	m.cleanProgress = 0.0
+	m.scanMessage = fmt.Sprintf("Cleaning all %d items...", len(m.markedItems))
+	return m, tea.Batch(
+		m.spinner.Tick,
+		cleanProgressTicker(),
+		performCleanMarkedItemsWithProgress(m.remover(), m.volumePath(), m.markedFileItems()),
+		m.publishStatus(status.StateCleaning),
+	)
+}
@@ -2,11 +2,16 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
 
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/history"
+	"github.com/rahulvramesh/cleanWithCli/internal/scanner"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
 	"github.com/rahulvramesh/cleanWithCli/internal/utils"
 )
 
@@ -43,6 +48,24 @@ func (m Model) View() string {
 		content = m.renderDiskUsage()
 	case "detail":
 		content = m.renderDetail()
+	case "preview":
+		content = m.renderPreview()
+	case "review":
+		content = m.renderReview()
+	case "confirmAll":
+		content = m.renderConfirmAll()
+	case "history":
+		content = m.renderHistory()
+	case "timeline":
+		content = m.renderTimeline()
+	case "audit":
+		content = m.renderAudit()
+	case "launchd":
+		content = m.renderLaunchJobs()
+	case "whatsnew":
+		content = m.renderWhatsNew()
+	case "info":
+		content = m.renderInfo()
 	}
 
 	// Add horizontal padding
@@ -51,7 +74,7 @@ func (m Model) View() string {
 
 	if m.err != nil {
 		s.WriteString("\n\n")
-		errMsg := lipgloss.NewStyle().Padding(0, 3).Render(ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		errMsg := lipgloss.NewStyle().Padding(0, 3).Render(m.renderError())
 		s.WriteString(errMsg)
 	}
 
@@ -59,21 +82,31 @@ func (m Model) View() string {
 	return s.String()
 }
 
+// menuItems are the main menu entries, in the order handled by Update
+var menuItems = []string{
+	"🔍 Full System Scan",
+	"💻 Dev Scan (Development caches & artifacts)",
+	"🚀 Quick Clean (Safe files only)",
+	"📊 Disk Usage Report",
+	"📜 Scan History",
+	"📈 Disk Timeline",
+	"🧾 Deletion Log",
+	"🚀 Launch Agents & Daemons",
+	"🐘 Largest Files",
+	"❌ Exit",
+}
+
 func (m Model) renderMenu() string {
 	var s strings.Builder
 
-	items := []string{
-		"🔍 Full System Scan",
-		"💻 Dev Scan (Development caches & artifacts)",
-		"🚀 Quick Clean (Safe files only)",
-		"📊 Disk Usage Report",
-		"❌ Exit",
-	}
-
 	s.WriteString(HeaderStyle.Render("Main Menu"))
-	s.WriteString("\n\n\n")
+	s.WriteString("\n\n")
+	if banner := m.renderLowDiskBanner(); banner != "" {
+		s.WriteString(banner + "\n")
+	}
+	s.WriteString("\n")
 
-	for i, item := range items {
+	for i, item := range menuItems {
 		cursor := "  "
 		style := lipgloss.NewStyle()
 
@@ -85,7 +118,19 @@ func (m Model) renderMenu() string {
 		s.WriteString("  " + cursor + style.Render(item) + "\n\n")
 	}
 
+	if m.schedule != nil {
+		next := m.schedule.Next(m.now()).Format("Mon 2 Jan 15:04")
+		s.WriteString("  " + DimStyle.Render(fmt.Sprintf("⏰ Next scheduled %s clean: %s", m.schedule.Mode, next)))
+		s.WriteString("\n")
+	}
+
 	s.WriteString("\n\n")
+	if m.rootInput.Focused() {
+		s.WriteString("  " + m.rootInput.View())
+		s.WriteString("\n\n")
+		s.WriteString(DimStyle.Render(fmt.Sprintf("Lists the %d largest files in the folder, whatever they are • Enter: Find • ESC: Cancel", largestFilesCount)))
+		return s.String()
+	}
 	s.WriteString(DimStyle.Render("Use ↑/↓ or j/k to navigate, Enter to select, q to quit"))
 
 	return s.String()
@@ -106,7 +151,7 @@ func (m Model) renderScanning() string {
 		s.WriteString("\n\n")
 	}
 
-	s.WriteString("  " + m.spinner.View() + " " + m.scanMessage)
+	s.WriteString("  " + m.spinnerView() + " " + m.scanMessage)
 	s.WriteString("\n\n")
 
 	// Show recently scanned paths
@@ -115,10 +160,7 @@ func (m Model) renderScanning() string {
 		s.WriteString("\n")
 		for _, path := range m.scanningPaths {
 			// Truncate long paths
-			displayPath := path
-			if len(path) > 60 {
-				displayPath = "..." + path[len(path)-57:]
-			}
+			displayPath := utils.TruncateMiddle(m.displayPath(path), 60)
 			s.WriteString("     " + DimStyle.Render(displayPath))
 			s.WriteString("\n")
 		}
@@ -146,7 +188,17 @@ func (m Model) renderResults() string {
 	var s strings.Builder
 
 	s.WriteString(HeaderStyle.Render("Scan Results"))
-	s.WriteString("\n\n\n")
+	s.WriteString("\n\n")
+
+	// Show success message after a clean
+	if strings.Contains(m.scanMessage, "✅") {
+		s.WriteString("  " + SuccessStyle.Render(m.scanMessage))
+		s.WriteString("\n")
+		if m.freedCheck != nil {
+			s.WriteString(m.freedCheck.render())
+		}
+	}
+	s.WriteString("\n")
 
 	if len(m.results) == 0 {
 		s.WriteString("  " + WarningStyle.Render("No cleanable files found"))
@@ -156,8 +208,14 @@ func (m Model) renderResults() string {
 	// Create table
 	categories := utils.GetSortedCategories(m.results)
 
-	s.WriteString("  Category                    Items        Size\n")
-	s.WriteString("  ─────────────────────────────────────────────\n")
+	header := "  Category                    Items        Size"
+	rule := "  ─────────────────────────────────────────────"
+	if m.baseline != nil {
+		header += "  Δ since last"
+		rule += "──────────────"
+	}
+	s.WriteString(header + "\n")
+	s.WriteString(rule + "\n")
 
 	for i, category := range categories {
 		result := m.results[category]
@@ -169,16 +227,20 @@ func (m Model) renderResults() string {
 			style = SelectedStyle
 		}
 
-		line := fmt.Sprintf("%-25s %5d  %10s",
-			category,
+		line := fmt.Sprintf("%s %5d  %10s",
+			utils.PadRight(utils.TruncateMiddle(category, 25), 25),
 			len(result.Items),
 			humanize.Bytes(uint64(result.Total)),
 		)
 
-		s.WriteString("  " + cursor + style.Render(line) + "\n")
+		s.WriteString("  " + cursor + style.Render(line) + m.renderCategoryDelta(category, result.Total))
+		if result.Advisory {
+			s.WriteString(DimStyle.Render("  ℹ report only"))
+		}
+		s.WriteString("\n")
 	}
 
-	s.WriteString("  ─────────────────────────────────────────────\n")
+	s.WriteString(rule + "\n")
 
 	// Total
 	totalLine := fmt.Sprintf("%-25s %5d  %10s",
@@ -186,7 +248,18 @@ func (m Model) renderResults() string {
 		m.getTotalItems(),
 		humanize.Bytes(uint64(m.totalSize)),
 	)
-	s.WriteString("    " + SuccessStyle.Render(totalLine) + "\n\n")
+	s.WriteString("    " + SuccessStyle.Render(totalLine))
+	if m.baseline != nil {
+		s.WriteString(renderDelta(m.totalSize - m.baseline.TotalSize))
+	}
+	s.WriteString("\n\n")
+
+	if summary := m.scanErrorSummary(); summary != "" {
+		s.WriteString("  " + WarningStyle.Render(summary) + "\n\n")
+	}
+	if stats := m.renderScanStats(); stats != "" {
+		s.WriteString(stats + "\n")
+	}
 
 	// Back option
 	cursor := "  "
@@ -198,7 +271,15 @@ func (m Model) renderResults() string {
 	s.WriteString("  " + cursor + style.Render("← Back to Menu") + "\n")
 
 	s.WriteString("\n\n")
-	s.WriteString(DimStyle.Render("Press Enter to explore category • ESC to go back to menu"))
+	if len(m.markedItems) > 0 {
+		s.WriteString("  " + SuccessStyle.Render(fmt.Sprintf("Marked: %d items (%s)", len(m.markedItems), humanize.Bytes(uint64(m.markedSize())))))
+		s.WriteString("\n\n")
+	}
+	if m.scanKind == "quick" && m.getTotalItems() > 0 {
+		s.WriteString("  " + SuccessStyle.Render(fmt.Sprintf("Press c to review and clean all %d items (%s)", m.getTotalItems(), humanize.Bytes(uint64(m.totalSize)))))
+		s.WriteString("\n\n")
+	}
+	s.WriteString(DimStyle.Render("Press Enter to explore category • i for category info • r to review marked items • Shift+X to clean everything • ESC to go back to menu"))
 
 	return s.String()
 }
@@ -209,9 +290,9 @@ func (m Model) renderCleaning() string {
 	s.WriteString(HeaderStyle.Render("Cleaning Files..."))
 	s.WriteString("\n\n\n")
 	if m.scanMessage != "" {
-		s.WriteString("  " + m.spinner.View() + " " + m.scanMessage)
+		s.WriteString("  " + m.spinnerView() + " " + m.scanMessage)
 	} else {
-		s.WriteString("  " + m.spinner.View() + " Removing selected files...")
+		s.WriteString("  " + m.spinnerView() + " Removing selected files...")
 	}
 	s.WriteString("\n\n\n")
 	s.WriteString(m.progress.ViewAs(m.cleanProgress))
@@ -225,9 +306,92 @@ func (m Model) renderDiskUsage() string {
 	s.WriteString(HeaderStyle.Render("Disk Usage Report"))
 	s.WriteString("\n\n\n")
 	s.WriteString(m.diskUsageTable.View())
-	s.WriteString("\n\n\n")
-	s.WriteString(DimStyle.Render("Use ↑/↓ or j/k to navigate, ESC or q to go back to menu"))
+	s.WriteString("\n\n")
+	s.WriteString(m.renderSpacePanel())
+	s.WriteString("\n")
+	help := "Use ↑/↓ or j/k to navigate, ESC or q to go back to menu"
+	if m.spaceReport != nil && len(m.spaceReport.Snapshots) > 0 {
+		help = "t thin local snapshots • " + help
+	}
+	s.WriteString(DimStyle.Render(help))
+
+	return s.String()
+}
+
+// renderSpacePanel reconciles the free space df reports with the APFS
+// container, purgeable space, local snapshots and the last scan, explaining why cleaning may not
+// raise free space right away
+func (m Model) renderSpacePanel() string {
+	var s strings.Builder
+	s.WriteString(SelectedStyle.Render("Where's my free space?"))
+	s.WriteString("\n")
+
+	r := m.spaceReport
+	if r == nil {
+		if m.spaceMessage != "" {
+			s.WriteString("  " + WarningStyle.Render(m.spaceMessage) + "\n")
+		} else {
+			s.WriteString("  " + m.spinnerView() + " Measuring free space...\n")
+		}
+		return s.String()
+	}
+
+	row := func(label, value, note string) {
+		line := "  " + utils.PadRight(label, 17) + utils.PadRight(value, 10)
+		if note != "" {
+			line += DimStyle.Render(note)
+		}
+		s.WriteString(line + "\n")
+	}
 
+	row("Free (df)", humanize.Bytes(uint64(r.Free)), "of "+humanize.Bytes(uint64(r.Total)))
+	if c := r.Container; c != nil {
+		row("APFS container", humanize.Bytes(uint64(c.Size)), c.Reference+", free space shared by its volumes")
+		if c.VolumeUsed > 0 {
+			row("  This volume", humanize.Bytes(uint64(c.VolumeUsed)), "used, including its snapshots")
+			row("  Other volumes", humanize.Bytes(uint64(c.Other())), "System, VM, Preboot and others")
+		}
+	}
+	if r.PurgeableErr != nil {
+		row("Purgeable", "unknown", "")
+	} else {
+		row("Purgeable", humanize.Bytes(uint64(r.Purgeable)), "macOS frees it on demand")
+	}
+	switch {
+	case r.SnapshotErr != nil:
+		row("Local snapshots", "unknown", "")
+	case len(r.Snapshots) == 0:
+		row("Local snapshots", "none", "")
+	default:
+		note := "hold deleted files"
+		if oldest := r.Oldest(); !oldest.IsZero() {
+			note = "oldest " + oldest.Format("2 Jan 15:04") + ", " + note
+		}
+		row("Local snapshots", fmt.Sprintf("%d", len(r.Snapshots)), note)
+	}
+	if m.results != nil {
+		row("Last scan found", humanize.Bytes(uint64(m.totalSize)), "to reclaim")
+	} else {
+		row("Last scan found", "-", "run a scan to see what cleaning frees")
+	}
+
+	wrap := lipgloss.NewStyle().Width(max(40, m.width-10))
+	var explain string
+	switch {
+	case len(r.Snapshots) > 0:
+		explain = "Deleting a file that is also in a snapshot frees nothing until the snapshot is thinned or expires, so free space may not go up right after a clean."
+	case r.Purgeable > 0:
+		explain = "Finder counts purgeable space as available, so it shows more free space than df."
+	}
+	if explain != "" {
+		s.WriteString("\n")
+		for _, line := range strings.Split(wrap.Render(explain), "\n") {
+			s.WriteString("  " + DimStyle.Render(strings.TrimRight(line, " ")) + "\n")
+		}
+	}
+	if m.spaceMessage != "" {
+		s.WriteString("  " + SuccessStyle.Render(m.spaceMessage) + "\n")
+	}
 	return s.String()
 }
 
@@ -235,7 +399,7 @@ func (m Model) renderDetail() string {
 	var s strings.Builder
 
 	// Breadcrumb navigation
-	breadcrumb := strings.Join(m.currentPath, " > ")
+	breadcrumb := utils.SanitizeName(strings.Join(m.currentPath, " > "))
 	s.WriteString(HeaderStyle.Render("📁 " + breadcrumb))
 	s.WriteString("\n\n")
 
@@ -243,6 +407,21 @@ func (m Model) renderDetail() string {
 	if m.state == "detail" && strings.Contains(m.scanMessage, "✅") {
 		s.WriteString("  " + SuccessStyle.Render(m.scanMessage))
 		s.WriteString("\n")
+		if m.freedCheck != nil {
+			s.WriteString(m.freedCheck.render())
+		}
+	}
+	if result, ok := m.results[m.currentCategory]; ok && result.Advisory {
+		s.WriteString("  " + WarningStyle.Render("ℹ Report only: the cleaner never deletes these items."))
+		s.WriteString("\n")
+		if result.Hint != "" {
+			s.WriteString("  " + DimStyle.Render(result.Hint))
+			s.WriteString("\n")
+		}
+	}
+	if result, ok := m.results[m.currentCategory]; ok && result.Method != "" && !result.Advisory {
+		s.WriteString("  " + DimStyle.Render("Cleaned by: "+result.Method))
+		s.WriteString("\n")
 	}
 	s.WriteString("\n")
 
@@ -289,7 +468,7 @@ func (m Model) renderDetail() string {
 
 		// Checkbox indicator
 		checkbox := "☐"
-		if m.markedItems[item.Path] {
+		if m.isMarked(item.Path) {
 			checkbox = "☑️"
 		}
 
@@ -300,11 +479,10 @@ func (m Model) renderDetail() string {
 
 		// Adjust name width based on terminal width (accounting for checkbox)
 		nameWidth := min(45, m.width-35)
-		line := fmt.Sprintf("%s %s %-*s %10s",
+		line := fmt.Sprintf("%s %s %s %10s",
 			checkbox,
 			icon,
-			nameWidth,
-			utils.TruncatePath(item.Name, nameWidth),
+			utils.PadRight(utils.TruncateMiddle(utils.SanitizeName(item.Name), nameWidth), nameWidth),
 			humanize.Bytes(uint64(item.Size)),
 		)
 
@@ -317,6 +495,12 @@ func (m Model) renderDetail() string {
 		s.WriteString("\n")
 	}
 
+	// Full path of the selected row, never truncated
+	if m.detailChoice < len(m.detailItems) {
+		s.WriteString("\n")
+		s.WriteString("  " + DimStyle.Render("→ "+m.displayPath(m.detailItems[m.detailChoice].Path)))
+	}
+
 	// Show total size for current view
 	var totalSize int64
 	for _, item := range m.detailItems {
@@ -328,31 +512,614 @@ func (m Model) renderDetail() string {
 	// Show marked items status
 	markedCount := len(m.markedItems)
 	if markedCount > 0 {
-		var markedSize int64
-		for path := range m.markedItems {
-			// Find the size of marked items
-			for _, item := range m.detailItems {
-				if item.Path == path {
-					markedSize += item.Size
-					break
+		s.WriteString(" • ")
+		s.WriteString(SuccessStyle.Render(fmt.Sprintf("Marked: %d items (%s)", markedCount, humanize.Bytes(uint64(m.markedSize())))))
+	}
+	s.WriteString("\n\n")
+
+	// Instructions
+	if m.patternInput.Focused() {
+		s.WriteString("  " + m.patternInput.View())
+		s.WriteString("\n\n")
+		s.WriteString(DimStyle.Render("* matches anything, including /; ? matches one character; >90d marks items older than 90 days • Enter: Mark • ESC: Cancel"))
+		return s.String()
+	}
+	help := "↑/↓ Navigate • Enter: Open • Backspace: Up • Space: Mark • Shift+A: Mark All • Shift+N: Unmark All • Shift+I: Invert • /: Mark by Pattern • "
+	for _, item := range m.detailItems {
+		if isDerivedData(item) {
+			help += fmt.Sprintf("Shift+B: Mark Builds Older Than %d Days • ", staleBuildDays)
+			break
+		}
+	}
+	help += "Shift+D: Delete Marked • r: Review • c: Clean • p: Preview • o: Reveal in Finder • i: Info • ~: Absolute/~ Paths • ESC: Back"
+	s.WriteString(DimStyle.Render(help))
+
+	return s.String()
+}
+
+// formatDelta formats a size change as "+1.2 GB", "-3 MB" or "="
+func formatDelta(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + humanize.Bytes(uint64(delta))
+	case delta < 0:
+		return "-" + humanize.Bytes(uint64(-delta))
+	default:
+		return "="
+	}
+}
+
+// renderDelta renders a size change for the Δ column, highlighting growth
+func renderDelta(delta int64) string {
+	text := fmt.Sprintf("  %12s", formatDelta(delta))
+	switch {
+	case delta > 0:
+		return WarningStyle.Render(text)
+	case delta < 0:
+		return SuccessStyle.Render(text)
+	default:
+		return DimStyle.Render(text)
+	}
+}
+
+// renderCategoryDelta renders the Δ column of a results row, or nothing
+// when there is no previous scan to compare against. Categories missing
+// from the previous scan, like DerivedData that reappeared, show as new.
+func (m Model) renderCategoryDelta(category string, total int64) string {
+	if m.baseline == nil {
+		return ""
+	}
+	prev, ok := m.baseline.Categories[category]
+	if !ok {
+		return WarningStyle.Render(fmt.Sprintf("  %12s", "new"))
+	}
+	return renderDelta(total - prev.Total)
+}
+
+func (m Model) renderHistory() string {
+	var s strings.Builder
+
+	s.WriteString(HeaderStyle.Render("Scan History"))
+	s.WriteString("\n\n\n")
+
+	if len(m.historyEntries) == 0 {
+		s.WriteString("  " + DimStyle.Render("No scans recorded yet"))
+		s.WriteString("\n\n")
+		s.WriteString(DimStyle.Render("ESC: Back"))
+		return s.String()
+	}
+
+	s.WriteString("  When               Scan    Items        Size       Change\n")
+	s.WriteString("  ──────────────────────────────────────────────────────────\n")
+
+	// Newest first
+	viewportHeight := max(5, m.height-15)
+	shown := 0
+	for i := len(m.historyEntries) - 1 - m.historyOffset; i >= 0 && shown < viewportHeight; i-- {
+		entry := m.historyEntries[i]
+		// Scans of different kinds cover different categories
+		change := ""
+		if prev := history.Previous(m.historyEntries[:i], entry.Kind); prev != nil {
+			change = formatDelta(entry.TotalSize - prev.TotalSize)
+		}
+
+		line := fmt.Sprintf("%-18s %-6s %6d  %10s  %11s",
+			entry.Time.Local().Format("2006-01-02 15:04"),
+			entry.Kind,
+			entry.Items(),
+			humanize.Bytes(uint64(entry.TotalSize)),
+			change,
+		)
+		s.WriteString("  " + line + "\n")
+		shown++
+	}
+
+	s.WriteString("\n")
+	s.WriteString(DimStyle.Render(fmt.Sprintf("%d scans recorded • ↑/↓ Scroll • ESC: Back", len(m.historyEntries))))
+
+	return s.String()
+}
+
+// sparkLevels are the block characters used for sparklines, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values scaled between their minimum and maximum
+func sparkline(values []int64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+
+	var s strings.Builder
+	for _, v := range values {
+		level := len(sparkLevels) - 1
+		if hi > lo {
+			level = int((v - lo) * int64(len(sparkLevels)-1) / (hi - lo))
+		}
+		s.WriteRune(sparkLevels[level])
+	}
+	return s.String()
+}
+
+func (m Model) renderTimeline() string {
+	var s strings.Builder
+
+	s.WriteString(HeaderStyle.Render("Disk Timeline"))
+	s.WriteString("\n\n\n")
+
+	if len(m.timeline) == 0 {
+		s.WriteString("  " + DimStyle.Render("No free space samples yet; run a scan first"))
+		s.WriteString("\n\n")
+		s.WriteString(DimStyle.Render("ESC: Back"))
+		return s.String()
+	}
+
+	// Keep as many recent samples as fit on one line
+	points := m.timeline
+	if limit := max(10, m.width-12); len(points) > limit {
+		points = points[len(points)-limit:]
+	}
+
+	values := make([]int64, len(points))
+	for i, p := range points {
+		values[i] = p.free
+	}
+
+	first, last := points[0], points[len(points)-1]
+	s.WriteString(fmt.Sprintf("  Free space  %s\n", SuccessStyle.Render(sparkline(values))))
+	s.WriteString(fmt.Sprintf("              %s → %s\n\n",
+		first.time.Local().Format("2006-01-02"),
+		last.time.Local().Format("2006-01-02")))
+
+	summary := fmt.Sprintf("  Now: %s free", humanize.Bytes(uint64(last.free)))
+	if m.timelineDisk > 0 {
+		summary += fmt.Sprintf(" of %s", humanize.Bytes(uint64(m.timelineDisk)))
+	}
+	summary += fmt.Sprintf(" • %s since %s", formatDelta(last.free-first.free), first.time.Local().Format("Jan 2"))
+	s.WriteString(summary + "\n\n")
+
+	// Bar chart of the most recent samples, newest last
+	recent := points
+	if limit := max(3, m.height-20); len(recent) > limit {
+		recent = recent[len(recent)-limit:]
+	}
+	var hi int64
+	for _, p := range recent {
+		if p.free > hi {
+			hi = p.free
+		}
+	}
+	barWidth := max(10, min(40, m.width-50))
+	for _, p := range recent {
+		n := 0
+		if hi > 0 {
+			n = int(p.free * int64(barWidth) / hi)
+		}
+		line := fmt.Sprintf("  %s  %-5s %s %s",
+			p.time.Local().Format("01-02 15:04"),
+			p.kind,
+			utils.PadRight(strings.Repeat("█", n), barWidth),
+			humanize.Bytes(uint64(p.free)),
+		)
+		s.WriteString(line + "\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(DimStyle.Render(fmt.Sprintf("%d samples from scans and cleans • ESC: Back", len(m.timeline))))
+
+	return s.String()
+}
+
+func (m Model) renderAudit() string {
+	var s strings.Builder
+
+	s.WriteString(HeaderStyle.Render("Deletion Log"))
+	s.WriteString("\n\n\n")
+
+	if len(m.auditRecords) == 0 {
+		s.WriteString("  " + DimStyle.Render("Nothing has been deleted yet"))
+		s.WriteString("\n\n")
+		s.WriteString(DimStyle.Render("ESC: Back"))
+		return s.String()
+	}
+
+	pathWidth := max(20, m.width-48)
+	viewportHeight := max(5, m.height-17)
+	offset := 0
+	if m.auditChoice >= viewportHeight {
+		offset = m.auditChoice - viewportHeight + 1
+	}
+
+	// Newest first
+	n := len(m.auditRecords)
+	for i := offset; i < n && i < offset+viewportHeight; i++ {
+		r := m.auditRecords[n-1-i]
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.auditChoice {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+
+		outcome := utils.PadRight(r.Outcome, 7)
+		switch r.Outcome {
+		case audit.OutcomeFailed:
+			outcome = ErrorStyle.Render(outcome)
+		case audit.OutcomeMissing:
+			outcome = WarningStyle.Render(outcome)
+		}
+
+		line := fmt.Sprintf("%s %10s  %s",
+			r.Time.Local().Format("2006-01-02 15:04"),
+			humanize.Bytes(uint64(r.Size)),
+			utils.TruncateMiddle(utils.SanitizeName(m.displayPath(r.Path)), pathWidth),
+		)
+		s.WriteString("  " + cursor + outcome + " " + style.Render(line) + "\n")
+	}
+
+	// Details of the selected record
+	r := m.auditRecords[n-1-m.auditChoice]
+	s.WriteString("\n")
+	details := "→ " + utils.SanitizeName(r.Path)
+	if r.Category != "" {
+		details += "  (" + r.Category + ")"
+	}
+	s.WriteString("  " + DimStyle.Render(details) + "\n")
+	if r.Error != "" {
+		s.WriteString("  " + ErrorStyle.Render(utils.SanitizeName(r.Error)) + "\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(DimStyle.Render(fmt.Sprintf("%d deletions logged • ↑/↓ Navigate • ESC: Back", n)))
+
+	return s.String()
+}
+
+func (m Model) renderLaunchJobs() string {
+	var s strings.Builder
+
+	s.WriteString(HeaderStyle.Render("Launch Agents & Daemons"))
+	s.WriteString("\n\n")
+	if m.launchMessage != "" {
+		s.WriteString("  " + m.launchMessage + "\n")
+	}
+	s.WriteString("\n")
+
+	if len(m.launchJobs) == 0 {
+		s.WriteString("  " + DimStyle.Render("No third-party launch agents or daemons are installed"))
+		s.WriteString("\n\n")
+		s.WriteString(DimStyle.Render("ESC: Back"))
+		return s.String()
+	}
+
+	labelWidth := max(20, m.width-30)
+	viewportHeight := max(5, m.height-18)
+	offset := 0
+	if m.launchChoice >= viewportHeight {
+		offset = m.launchChoice - viewportHeight + 1
+	}
+
+	stale := 0
+	for _, job := range m.launchJobs {
+		if job.Stale() {
+			stale++
+		}
+	}
+	for i := offset; i < len(m.launchJobs) && i < offset+viewportHeight; i++ {
+		job := m.launchJobs[i]
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.launchChoice {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+
+		status := DimStyle.Render(utils.PadRight("ok", 5))
+		if job.Stale() {
+			status = WarningStyle.Render(utils.PadRight("stale", 5))
+		}
+		line := utils.PadRight(job.Kind, 10) + "  " + utils.TruncateMiddle(utils.SanitizeName(job.Label), labelWidth)
+		s.WriteString("  " + cursor + status + " " + style.Render(line) + "\n")
+	}
+
+	// Details of the selected job
+	job := m.launchJobs[m.launchChoice]
+	s.WriteString("\n")
+	program := job.Program
+	if program == "" {
+		program = "(no program)"
+	}
+	if job.Stale() {
+		program += "  (" + job.Missing + " no longer exists)"
+	}
+	s.WriteString("  " + DimStyle.Render("→ runs "+utils.SanitizeName(program)) + "\n")
+	s.WriteString("  " + DimStyle.Render("  from "+m.displayPath(job.Plist)) + "\n")
+
+	s.WriteString("\n")
+	s.WriteString(DimStyle.Render(fmt.Sprintf("%d jobs, %d stale • ↑/↓ Navigate • c: Remove stale job • ESC: Back", len(m.launchJobs), stale)))
+
+	return s.String()
+}
+
+func (m Model) renderWhatsNew() string {
+	var s strings.Builder
+
+	s.WriteString(HeaderStyle.Render("What's New"))
+	s.WriteString("\n\n")
+
+	for _, section := range m.whatsNew {
+		s.WriteString("\n  " + SuccessStyle.Render(section.Version) + "\n")
+		for _, group := range section.Groups {
+			if group.Title != "" {
+				style := SelectedStyle
+				if group.Title == "Safety" {
+					// Safety changes affect what gets deleted
+					style = WarningStyle
 				}
+				s.WriteString("\n    " + style.Render(group.Title) + "\n")
+			}
+			for _, item := range group.Items {
+				s.WriteString("    • " + item + "\n")
 			}
 		}
-		s.WriteString(" • ")
-		s.WriteString(SuccessStyle.Render(fmt.Sprintf("Marked: %d items (%s)", markedCount, humanize.Bytes(uint64(markedSize)))))
 	}
+
+	s.WriteString("\n")
+	s.WriteString(DimStyle.Render("Press Enter to continue"))
+
+	return s.String()
+}
+
+func (m Model) renderInfo() string {
+	var s strings.Builder
+
+	s.WriteString(HeaderStyle.Render("ℹ " + utils.SanitizeName(m.infoCategory)))
 	s.WriteString("\n\n")
 
-	// Instructions
-	s.WriteString(DimStyle.Render("↑/↓ Navigate • Space: Mark • Shift+A: Mark All • Shift+N: Unmark All • Shift+D: Delete Marked • c: Clean • ESC: Back"))
+	wrap := lipgloss.NewStyle().Width(max(40, m.width-10))
+	section := func(title, text string) {
+		if text == "" {
+			return
+		}
+		s.WriteString("\n  " + SelectedStyle.Render(title) + "\n")
+		for _, line := range strings.Split(wrap.Render(text), "\n") {
+			s.WriteString("    " + strings.TrimRight(line, " ") + "\n")
+		}
+	}
+
+	result := m.results[m.infoCategory]
+	if result != nil {
+		s.WriteString(fmt.Sprintf("  %d items, %s", len(result.Items), humanize.Bytes(uint64(result.Total))))
+		if !result.Advisory {
+			s.WriteString(fmt.Sprintf(" • %s risk", m.grouping.Risk(m.infoCategory)))
+		}
+		s.WriteString("\n")
+	}
+
+	sources := m.grouping.Sources(m.infoCategory)
+	if len(sources) > 1 {
+		// A merged category: describe each part
+		for _, from := range sources {
+			info, _ := scanner.Info(from)
+			section(from, info.Description)
+		}
+	} else if info, ok := scanner.Info(sources[0]); ok {
+		section("What it is", info.Description)
+		section("If you delete it", info.Consequences)
+		section("Getting it back", info.Regeneration)
+	} else {
+		s.WriteString("\n  " + DimStyle.Render("No description is available for this category.") + "\n")
+	}
+	if result != nil {
+		if result.Advisory {
+			section("Report only", result.Hint)
+		} else if result.Method != "" {
+			section("Cleaned by", result.Method)
+		}
+	}
+
+	s.WriteString("\n")
+	s.WriteString(DimStyle.Render("Press i or ESC to go back"))
 
 	return s.String()
 }
 
+func (m Model) renderConfirmAll() string {
+	var s strings.Builder
+
+	s.WriteString(HeaderStyle.Render("Clean Everything?"))
+	s.WriteString("\n\n\n")
+
+	// Group categories by risk
+	type group struct {
+		categories []string
+		items      int
+		size       int64
+	}
+	groups := make(map[types.RiskLevel]*group)
+	// Not everything is deleted: some categories go to the Trash or an
+	// archive folder, others are cleaned by their own tool
+	var deleted, moved, tooled int
+	for _, category := range utils.GetSortedCategories(m.results) {
+		result := m.results[category]
+		if result.Advisory {
+			continue
+		}
+		switch {
+		case result.Method == "" || result.Method == "delete":
+			deleted += len(result.Items)
+		case strings.HasPrefix(result.Method, "move to "):
+			moved += len(result.Items)
+		default:
+			tooled += len(result.Items)
+		}
+		risk := m.grouping.Risk(category)
+		if groups[risk] == nil {
+			groups[risk] = &group{}
+		}
+		groups[risk].categories = append(groups[risk].categories, category)
+		groups[risk].items += len(result.Items)
+		groups[risk].size += result.Total
+	}
+
+	for _, risk := range []types.RiskLevel{types.RiskHigh, types.RiskMedium, types.RiskLow} {
+		g := groups[risk]
+		if g == nil {
+			continue
+		}
+
+		style := DimStyle
+		switch risk {
+		case types.RiskHigh:
+			style = ErrorStyle
+		case types.RiskMedium:
+			style = WarningStyle
+		}
+
+		header := fmt.Sprintf("%-8s risk  %5d items  %10s", risk, g.items, humanize.Bytes(uint64(g.size)))
+		s.WriteString("  " + style.Render(header) + "\n")
+		s.WriteString("    " + DimStyle.Render(strings.Join(g.categories, ", ")) + "\n\n")
+	}
+
+	var how []string
+	if deleted > 0 {
+		how = append(how, fmt.Sprintf("permanently delete %d", deleted))
+	}
+	if moved > 0 {
+		how = append(how, fmt.Sprintf("move %d to the Trash or an archive folder", moved))
+	}
+	if tooled > 0 {
+		how = append(how, fmt.Sprintf("clean %d with the tool that owns them", tooled))
+	}
+	s.WriteString("  " + SuccessStyle.Render(fmt.Sprintf("This will clean %d items (%s):", m.getTotalItems(), humanize.Bytes(uint64(m.totalSize)))))
+	s.WriteString("\n")
+	for _, h := range how {
+		s.WriteString("    " + DimStyle.Render("• "+h) + "\n")
+	}
+	if groups[types.RiskHigh] != nil {
+		s.WriteString("  " + ErrorStyle.Render("High risk categories may contain your own files. Review them first if unsure."))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(DimStyle.Render("y: Clean everything • n/ESC: Cancel"))
+
+	return s.String()
+}
+
+func (m Model) renderReview() string {
+	var s strings.Builder
+
+	marked := m.sortedMarked()
+	s.WriteString(HeaderStyle.Render(fmt.Sprintf("Review Selection: %d items • %s", len(marked), humanize.Bytes(uint64(m.markedSize())))))
+	s.WriteString("\n\n\n")
+
+	if len(marked) == 0 {
+		s.WriteString("  " + DimStyle.Render("Nothing is marked"))
+		s.WriteString("\n\n")
+		s.WriteString(DimStyle.Render("ESC: Back"))
+		return s.String()
+	}
+
+	viewportHeight := max(5, m.height-15)
+	startIdx := m.reviewOffset
+	endIdx := min(startIdx+viewportHeight, len(marked))
+
+	category := ""
+	nameWidth := min(60, m.width-30)
+	for i := startIdx; i < endIdx; i++ {
+		entry := marked[i]
+		if entry.category != category || i == startIdx {
+			category = entry.category
+			s.WriteString("  " + HeaderStyle.Render(utils.SanitizeName(category)) + "\n")
+		}
+
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if m.reviewChoice == i {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+
+		line := fmt.Sprintf("%s %10s",
+			utils.PadRight(utils.TruncateMiddle(m.displayPath(entry.item.Path), nameWidth), nameWidth),
+			humanize.Bytes(uint64(entry.item.Size)),
+		)
+		s.WriteString("  " + cursor + style.Render(line) + "\n")
+	}
+	if endIdx < len(marked) {
+		s.WriteString("  " + DimStyle.Render(fmt.Sprintf("… %d more", len(marked)-endIdx)) + "\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(DimStyle.Render("↑/↓ Navigate • Space: Unmark • Shift+N: Unmark All • Shift+D: Delete All • ESC: Back"))
+
+	return s.String()
+}
+
+func (m Model) renderPreview() string {
+	var s strings.Builder
+
+	p := m.preview
+	if p == nil {
+		return s.String()
+	}
+
+	s.WriteString(HeaderStyle.Render("🔎 " + utils.SanitizeName(filepath.Base(p.Path))))
+	s.WriteString("\n\n")
+
+	// Metadata
+	s.WriteString("  " + DimStyle.Render(fmt.Sprintf("Path:     %s", m.displayPath(p.Path))) + "\n")
+	s.WriteString("  " + DimStyle.Render(fmt.Sprintf("Size:     %s", humanize.Bytes(uint64(p.Size)))) + "\n")
+	s.WriteString("  " + DimStyle.Render(fmt.Sprintf("Modified: %s (%s)", p.ModTime.Format("2006-01-02 15:04"), humanize.RelTime(p.ModTime, m.now(), "ago", "from now"))) + "\n")
+	s.WriteString("  " + DimStyle.Render(fmt.Sprintf("Type:     %s • %s", p.ContentType, p.Mode)) + "\n")
+	s.WriteString("\n")
+
+	if p.Text == "" {
+		s.WriteString("  " + WarningStyle.Render("Binary file, content not shown"))
+		s.WriteString("\n\n")
+	} else {
+		viewportHeight := m.height - 16
+		if viewportHeight < 5 {
+			viewportHeight = 5
+		}
+
+		lines := strings.Split(strings.ReplaceAll(p.Text, "\t", "    "), "\n")
+		start := min(m.previewOffset, len(lines)-1)
+		end := min(start+viewportHeight, len(lines))
+		for _, line := range lines[start:end] {
+			s.WriteString("  │ " + utils.TruncatePath(utils.SanitizeName(line), max(10, m.width-12)) + "\n")
+		}
+		if p.Truncated && end == len(lines) {
+			s.WriteString("  " + DimStyle.Render(fmt.Sprintf("… showing first %s", humanize.Bytes(previewLimit))) + "\n")
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString(DimStyle.Render("↑/↓ Scroll • ESC: Back"))
+
+	return s.String()
+}
+
+// displayPath formats a path for display according to the path toggle
+func (m Model) displayPath(path string) string {
+	return utils.SanitizeName(utils.DisplayPath(path, m.scanner.HomeDir, m.absolutePaths))
+}
+
 func (m Model) getTotalItems() int {
 	total := 0
 	for _, result := range m.results {
-		total += len(result.Items)
+		if !result.Advisory {
+			total += len(result.Items)
+		}
 	}
 	return total
 }
@@ -0,0 +1,369 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/audit"
+	"github.com/rahulvramesh/cleanWithCli/internal/changelog"
+	"github.com/rahulvramesh/cleanWithCli/internal/history"
+	"github.com/rahulvramesh/cleanWithCli/internal/launchd"
+	"github.com/rahulvramesh/cleanWithCli/internal/schedule"
+	"github.com/rahulvramesh/cleanWithCli/internal/space"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestMain(m *testing.M) {
+	// Render without colors so golden files are independent of the terminal
+	lipgloss.SetColorProfile(termenv.Ascii)
+	os.Exit(m.Run())
+}
+
+// fixtureModel returns a model populated with fixed scan results
+func fixtureModel(width, height int) Model {
+	m := InitialModel(Options{})
+	m.scanner.HomeDir = "/Users/dev"
+	m.now = func() time.Time { return time.Date(2024, 3, 12, 10, 0, 0, 0, time.Local) }
+	m.width = width
+	m.height = height
+	m.results = map[string]*types.ScanResult{
+		"Cache Files": {
+			Category: "Cache Files",
+			Items: []types.FileItem{
+				{Path: "/Users/dev/Library/Caches/com.apple.Safari", Name: "com.apple.Safari", Size: 52_428_800, IsDir: true},
+				{Path: "/Users/dev/Library/Caches/com.spotify.client", Name: "com.spotify.client", Size: 1_073_741_824, IsDir: true},
+			},
+			Total: 1_126_170_624,
+		},
+		"Log Files": {
+			Category: "Log Files",
+			Items: []types.FileItem{
+				{Path: "/Users/dev/Library/Logs/install.log", Name: "install.log", Size: 2_048},
+			},
+			Total: 2_048,
+		},
+		"Node Modules": {
+			Category: "Node Modules",
+			Items: []types.FileItem{
+				{Path: "/Users/dev/code/a-project-with-a-rather-long-directory-name/node_modules", Name: "📦 code/a-project-with-a-rather-long-directory-name", Size: 734_003_200, IsDir: true},
+			},
+			Total: 734_003_200,
+		},
+	}
+	m.totalSize = 1_126_170_624 + 2_048 + 734_003_200
+	return m
+}
+
+// assertGolden compares got against testdata/<name>.golden
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestViewsGolden(t *testing.T) {
+	widths := []int{80, 120}
+
+	tests := []struct {
+		name   string
+		setup  func(m *Model)
+		render func(m Model) string
+	}{
+		{
+			name:   "menu",
+			setup:  func(m *Model) { m.menuChoice = 1 },
+			render: Model.renderMenu,
+		},
+		{
+			name: "scanning",
+			setup: func(m *Model) {
+				m.state = "scanning"
+				m.scanMessage = "Starting Dev Scan - Deep scanning all projects..."
+				m.scanFoundItems = 3
+				m.scanTotalSize = 4_096
+				m.scanningPaths = []string{
+					"/Users/dev/code/app/node_modules",
+					"/Users/dev/code/some/deeply/nested/project/directory/with/a/very/long/name/target",
+				}
+			},
+			render: Model.renderScanning,
+		},
+		{
+			name: "results",
+			setup: func(m *Model) {
+				m.state = "results"
+				m.menuChoice = 2
+			},
+			render: Model.renderResults,
+		},
+		{
+			name: "results_diff",
+			setup: func(m *Model) {
+				m.state = "results"
+				m.baseline = &history.Entry{
+					Kind:      "full",
+					TotalSize: 900_000_000,
+					Categories: map[string]history.CategoryTotal{
+						"Cache Files": {Items: 2, Total: 100_000_000},
+						"Log Files":   {Items: 1, Total: 2_048},
+					},
+				}
+			},
+			render: Model.renderResults,
+		},
+		{
+			name: "timeline",
+			setup: func(m *Model) {
+				m.state = "timeline"
+				m.timelineDisk = 500_000_000_000
+				day := func(d, gb int, kind string) timelinePoint {
+					return timelinePoint{
+						time: time.Date(2024, 3, d, 9, 30, 0, 0, time.Local),
+						free: int64(gb) * 1_000_000_000,
+						kind: kind,
+					}
+				}
+				m.timeline = []timelinePoint{
+					day(1, 40, "full"), day(2, 35, "dev"), day(2, 60, "clean"),
+					day(9, 52, "full"), day(10, 75, "clean"), day(12, 70, "now"),
+				}
+			},
+			render: Model.renderTimeline,
+		},
+		{
+			name: "audit",
+			setup: func(m *Model) {
+				m.state = "audit"
+				at := time.Date(2024, 3, 9, 14, 5, 0, 0, time.Local)
+				m.auditRecords = []audit.Record{
+					{Time: at, Path: "/Users/dev/Library/Caches/com.apple.Safari", Size: 52_428_800, Category: "Cache Files", Outcome: audit.OutcomeDeleted},
+					{Time: at, Path: "/Users/dev/Library/Logs/install.log", Size: 2_048, Category: "Log Files", Outcome: audit.OutcomeFailed, Error: "remove /Users/dev/Library/Logs/install.log: permission denied"},
+					{Time: at.Add(time.Hour), Path: "/Users/dev/code/app/node_modules", Size: 734_003_200, Category: "Node Modules", Outcome: audit.OutcomeMissing},
+				}
+				m.auditChoice = 1
+			},
+			render: Model.renderAudit,
+		},
+		{
+			name: "launch_agents",
+			setup: func(m *Model) {
+				m.state = "launchd"
+				m.launchJobs = []launchd.Job{
+					{Label: "com.gone.updater", Plist: "/Users/dev/Library/LaunchAgents/com.gone.updater.plist", Kind: launchd.KindUserAgent, Program: "/Applications/Gone.app/Contents/MacOS/Updater", App: "/Applications/Gone.app", Missing: "/Applications/Gone.app"},
+					{Label: "com.vendor.helper", Plist: "/Library/LaunchDaemons/com.vendor.helper.plist", Kind: launchd.KindDaemon, Program: "/Library/PrivilegedHelperTools/com.vendor.helper"},
+				}
+				m.launchMessage = "✅ Removed com.old.agent"
+			},
+			render: Model.renderLaunchJobs,
+		},
+		{
+			name: "results_freed",
+			setup: func(m *Model) {
+				m.state = "results"
+				m.scanMessage = "✅ Deleted 12 items (30 GB)"
+				m.freedCheck = &freedCheck{
+					reported: 30_000_000_000,
+					volume:   2_100_000_000,
+					categories: map[string]int64{
+						"Cache Files":  20_000_000_000,
+						"Node Modules": 10_000_000_000,
+					},
+				}
+			},
+			render: Model.renderResults,
+		},
+		{
+			name: "menu_low_disk",
+			setup: func(m *Model) {
+				m.state = "menu"
+				m.diskFree, m.diskTotal = 4_200_000_000, 494_000_000_000
+			},
+			render: Model.renderMenu,
+		},
+		{
+			name: "results_stats",
+			setup: func(m *Model) {
+				m.state = "results"
+				m.statsExpanded = true
+				m.scanStats = &types.ScanStats{
+					Duration:   12_340 * time.Millisecond,
+					PeakMemory: 212_000_000,
+					Scanners: []types.ScannerStats{
+						{Name: "node-modules", Category: "Node Modules", Duration: 8_120 * time.Millisecond, Dirs: 30_210, Files: 200_110},
+						{Name: "caches", Category: "Cache Files", Duration: 2_450 * time.Millisecond, Dirs: 1_204, Files: 18_330},
+						{Category: "Plugin Category", Duration: 310 * time.Millisecond},
+					},
+				}
+			},
+			render: Model.renderResults,
+		},
+		{
+			name: "whats_new",
+			setup: func(m *Model) {
+				m.state = "whatsnew"
+				m.whatsNew = changelog.Parse("## v0.2.0\n### New categories\n- Xcode Installations\n### Safety\n- Deletions are logged\n")
+			},
+			render: Model.renderWhatsNew,
+		},
+		{
+			name: "info",
+			setup: func(m *Model) {
+				m.state = "info"
+				m.infoCategory = "Node Modules"
+			},
+			render: Model.renderInfo,
+		},
+		{
+			name: "space",
+			setup: func(m *Model) {
+				m.state = "diskusage"
+				m.spaceReport = &space.Report{
+					Free:      21_000_000_000,
+					Total:     494_000_000_000,
+					Purgeable: 8_400_000_000,
+					Container: &space.Container{
+						Reference:  "disk3",
+						Size:       494_000_000_000,
+						Free:       21_000_000_000,
+						VolumeUsed: 431_000_000_000,
+					},
+					Snapshots: []space.Snapshot{
+						{Name: "com.apple.TimeMachine.2024-03-09-140500.local", Time: time.Date(2024, 3, 9, 14, 5, 0, 0, time.Local)},
+						{Name: "com.apple.TimeMachine.2024-03-09-150500.local", Time: time.Date(2024, 3, 9, 15, 5, 0, 0, time.Local)},
+					},
+				}
+			},
+			render: Model.renderSpacePanel,
+		},
+		{
+			name: "menu_schedule",
+			setup: func(m *Model) {
+				m.schedule = &schedule.Config{Mode: "quick", Interval: schedule.Weekly, Weekday: time.Sunday, Hour: 3}
+			},
+			render: Model.renderMenu,
+		},
+		{
+			name: "preview",
+			setup: func(m *Model) {
+				m.state = "preview"
+				m.preview = &types.PreviewMsg{
+					Path:        "/Users/dev/Library/Logs/install.log",
+					Size:        2_048,
+					ModTime:     time.Date(2024, 3, 9, 14, 5, 0, 0, time.Local),
+					Mode:        0o644,
+					ContentType: "text/plain; charset=utf-8",
+					Text:        "installer: Package name is Example\ninstaller: Installing at base path /",
+				}
+			},
+			render: Model.renderPreview,
+		},
+		{
+			name:   "confirm_all",
+			setup:  func(m *Model) { m.state = "confirmAll" },
+			render: Model.renderConfirmAll,
+		},
+		{
+			name: "confirm_all_methods",
+			setup: func(m *Model) {
+				m.state = "confirmAll"
+				m.results["Log Files"].Method = "move to Trash"
+				m.results["Node Modules"].Method = "run pnpm store prune or delete"
+			},
+			render: Model.renderConfirmAll,
+		},
+		{
+			name: "detail",
+			setup: func(m *Model) {
+				m.state = "detail"
+				m.currentCategory = "Cache Files"
+				m.currentPath = []string{"Cache Files"}
+				m.detailItems = m.results["Cache Files"].Items
+				m.detailChoice = 1
+				m.toggleMark(m.detailItems[0])
+			},
+			render: Model.renderDetail,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, width := range widths {
+			name := fmt.Sprintf("%s_%d", tt.name, width)
+			t.Run(name, func(t *testing.T) {
+				m := fixtureModel(width, 30)
+				tt.setup(&m)
+				assertGolden(t, name, tt.render(m))
+			})
+		}
+	}
+}
+
+func TestDetailSanitizesNames(t *testing.T) {
+	m := fixtureModel(80, 30)
+	m.state = "detail"
+	m.currentCategory = "Cache Files"
+	m.currentPath = []string{"Cache Files", "dir\nname"}
+	m.detailItems = []types.FileItem{
+		{Path: "/tmp/x", Name: "evil\x1b[2J\nname", Size: 1},
+	}
+
+	out := m.renderDetail()
+	if strings.ContainsRune(out, '\x1b') {
+		t.Errorf("rendered detail contains a raw escape character:\n%q", out)
+	}
+	if !strings.Contains(out, `evil\x1b[2J\nname`) {
+		t.Errorf("rendered detail does not show the escaped name:\n%s", out)
+	}
+}
+
+func TestHistoryComparesScansOfTheSameKind(t *testing.T) {
+	m := fixtureModel(80, 40)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	m.historyEntries = []history.Entry{
+		{Time: start, Kind: "full", TotalSize: 10_000_000_000},
+		{Time: start.Add(time.Hour), Kind: "quick", TotalSize: 1_000_000_000},
+		{Time: start.Add(2 * time.Hour), Kind: "full", TotalSize: 11_000_000_000},
+	}
+
+	changes := make(map[string]string)
+	for _, line := range strings.Split(m.renderHistory(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 6 && (fields[2] == "full" || fields[2] == "quick") {
+			// When, kind, items, size and unit, then the change if any
+			changes[fields[2]+" "+fields[4]] = strings.Join(fields[6:], " ")
+		}
+	}
+	// A quick scan after a full one is not a drop
+	if got := changes["quick 1.0"]; got != "" {
+		t.Errorf("quick scan change = %q, want none", got)
+	}
+	if got := changes["full 11"]; !strings.HasPrefix(got, "+1.0 GB") {
+		t.Errorf("full scan change = %q, want +1.0 GB from the earlier full scan (%v)", got, changes)
+	}
+}
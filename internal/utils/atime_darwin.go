@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// AccessTime returns when the file was last read, or its modification time if
+// that isn't recorded
+func AccessTime(info os.FileInfo) time.Time {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(st.Atimespec.Unix())
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,13 @@
+//go:build !darwin && !linux
+
+package utils
+
+import (
+	"os"
+	"time"
+)
+
+// AccessTime falls back to the modification time on this platform
+func AccessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
@@ -0,0 +1,23 @@
+//go:build !darwin && !linux
+
+package utils
+
+import (
+	"errors"
+	"os"
+)
+
+// DiskSpace is not supported on this platform
+func DiskSpace(path string) (free, total int64, err error) {
+	return 0, 0, errors.New("disk space is not supported on this platform")
+}
+
+// AllocatedSize falls back to DirSize on this platform
+func AllocatedSize(path string, counts *WalkCounts) (int64, error) {
+	return DirSize(path, counts)
+}
+
+// FileAllocatedSize returns the length of a file on this platform
+func FileAllocatedSize(info os.FileInfo) int64 {
+	return info.Size()
+}
@@ -0,0 +1,50 @@
+//go:build darwin || linux
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// DiskSpace returns the free and total bytes of the file system holding
+// path, counting only space available to unprivileged users as free
+func DiskSpace(path string) (free, total int64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	bsize := int64(st.Bsize)
+	return int64(st.Bavail) * bsize, int64(st.Blocks) * bsize, nil
+}
+
+// AllocatedSize is DirSize counting the blocks files take on disk rather
+// than their length, so sparse files such as VM disk images count only
+// what they store
+func AllocatedSize(path string, counts *WalkCounts) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip files we can't access
+		}
+		counts.Add(info.IsDir())
+		if info.IsDir() {
+			return nil
+		}
+		size += FileAllocatedSize(info)
+		return nil
+	})
+	return size, err
+}
+
+// FileAllocatedSize returns the bytes a file takes on disk, at most its
+// length, so sparse files and cloud files not downloaded count what they
+// store
+func FileAllocatedSize(info os.FileInfo) int64 {
+	n := info.Size()
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		n = min(n, int64(st.Blocks)*512)
+	}
+	return n
+}
@@ -0,0 +1,21 @@
+package utils
+
+// Mount is a mounted file system and its usage, as df reports it
+type Mount struct {
+	Device string // e.g. "/dev/disk3s1s1"
+	Path   string // Mount point
+	Type   string // e.g. "apfs"
+	Total  int64
+	Free   int64 // Available to unprivileged users
+	Used   int64
+}
+
+// Capacity returns the percentage of the space usable by unprivileged users
+// that is in use, rounded up like df's Capacity column
+func (m Mount) Capacity() int {
+	usable := m.Used + m.Free
+	if usable <= 0 {
+		return 0
+	}
+	return int((m.Used*100 + usable - 1) / usable)
+}
@@ -0,0 +1,21 @@
+package utils
+
+import "syscall"
+
+// MountType returns the file system type of the mount holding path, such as
+// "apfs", "nfs" or "smbfs", or "" if it can't be determined
+func MountType(path string) string {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return ""
+	}
+
+	name := make([]byte, 0, len(st.Fstypename))
+	for _, c := range st.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	return string(name)
+}
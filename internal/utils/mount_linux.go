@@ -0,0 +1,26 @@
+package utils
+
+import "syscall"
+
+// fsMagic maps statfs magic numbers to the names used on macOS
+var fsMagic = map[int64]string{
+	0x6969:     "nfs",
+	0x517b:     "smbfs",
+	0xff534d42: "smbfs", // CIFS
+	0xfe534d42: "smbfs", // SMB2
+	0x65735546: "fuse",
+	0xef53:     "ext4",
+	0x9123683e: "btrfs",
+	0x58465342: "xfs",
+	0x01021994: "tmpfs",
+}
+
+// MountType returns the file system type of the mount holding path, such as
+// "ext4", "nfs" or "smbfs", or "" if it can't be determined
+func MountType(path string) string {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return ""
+	}
+	return fsMagic[int64(st.Type)]
+}
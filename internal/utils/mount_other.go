@@ -0,0 +1,8 @@
+//go:build !darwin && !linux
+
+package utils
+
+// MountType is not supported on this platform and always returns ""
+func MountType(path string) string {
+	return ""
+}
@@ -0,0 +1,30 @@
+package utils
+
+import "golang.org/x/sys/unix"
+
+// Mounts lists the mounted file systems, like getmntinfo(3)
+func Mounts() ([]Mount, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]unix.Statfs_t, n)
+	n, err = unix.Getfsstat(buf, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, err
+	}
+
+	mounts := make([]Mount, 0, n)
+	for _, st := range buf[:n] {
+		bsize := int64(st.Bsize)
+		mounts = append(mounts, Mount{
+			Device: unix.ByteSliceToString(st.Mntfromname[:]),
+			Path:   unix.ByteSliceToString(st.Mntonname[:]),
+			Type:   unix.ByteSliceToString(st.Fstypename[:]),
+			Total:  int64(st.Blocks) * bsize,
+			Free:   int64(st.Bavail) * bsize,
+			Used:   int64(st.Blocks-st.Bfree) * bsize,
+		})
+	}
+	return mounts, nil
+}
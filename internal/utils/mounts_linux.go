@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Mounts lists the mounted file systems from /proc/self/mounts, leaving out
+// pseudo file systems without blocks
+func Mounts() ([]Mount, error) {
+	f, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var mounts []Mount
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		// Spaces and other special characters in paths are octal escaped
+		path := unescapeMount(fields[1])
+		var st unix.Statfs_t
+		if err := unix.Statfs(path, &st); err != nil || st.Blocks == 0 {
+			continue
+		}
+		bsize := int64(st.Bsize)
+		mounts = append(mounts, Mount{
+			Device: unescapeMount(fields[0]),
+			Path:   path,
+			Type:   fields[2],
+			Total:  int64(st.Blocks) * bsize,
+			Free:   int64(st.Bavail) * bsize,
+			Used:   int64(st.Blocks-st.Bfree) * bsize,
+		})
+	}
+	return mounts, sc.Err()
+}
+
+// unescapeMount decodes the \040 style escapes used in /proc/self/mounts
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if v, ok := octal(s[i+1 : i+4]); ok {
+				b.WriteByte(v)
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func octal(s string) (byte, bool) {
+	if len(s) != 3 {
+		return 0, false
+	}
+	var v int
+	for _, c := range s {
+		if c < '0' || c > '7' {
+			return 0, false
+		}
+		v = v*8 + int(c-'0')
+	}
+	return byte(v), v < 256
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+
+package utils
+
+import "errors"
+
+// Mounts is not supported on this platform
+func Mounts() ([]Mount, error) {
+	return nil, errors.New("listing mounts is not supported on this platform")
+}
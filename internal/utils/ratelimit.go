@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+// DefaultDeleteRates are the deletion limits, in operations per second, for
+// network-backed mounts. Other mount types are not limited.
+var DefaultDeleteRates = map[string]float64{
+	"nfs":    50,
+	"smbfs":  20,
+	"afpfs":  20,
+	"webdav": 10,
+}
+
+// ParseDeleteRates parses a comma-separated list of type=ops/sec pairs, e.g.
+// "nfs=100,smbfs=5", on top of DefaultDeleteRates. A rate of 0 turns
+// limiting off for that mount type.
+func ParseDeleteRates(spec string) (map[string]float64, error) {
+	rates := make(map[string]float64, len(DefaultDeleteRates))
+	for fsType, rate := range DefaultDeleteRates {
+		rates[fsType] = rate
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		fsType, value, ok := strings.Cut(pair, "=")
+		if !ok || fsType == "" {
+			return nil, fmt.Errorf("invalid delete rate %q, want type=ops", pair)
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("invalid delete rate %q, want a non-negative number", pair)
+		}
+		rates[strings.ToLower(fsType)] = rate
+	}
+	return rates, nil
+}
+
+// RateLimiter spaces out operations to at most a fixed number per second
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter creates a limiter allowing opsPerSec operations per second
+func NewRateLimiter(opsPerSec float64) *RateLimiter {
+	return &RateLimiter{interval: time.Duration(float64(time.Second) / opsPerSec)}
+}
+
+// Wait blocks until the next operation is allowed
+func (l *RateLimiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(wait)
+}
+
+// RateLimitedRemover returns a RemovePath variant that deletes entries one
+// at a time, limited by rates keyed on the mount type of each path. Paths on
+// mounts without a rate are removed at full speed.
+func RateLimitedRemover(rates map[string]float64) func(string) error {
+	var mu sync.Mutex
+	limiters := make(map[string]*RateLimiter)
+
+	limiterFor := func(path string) *RateLimiter {
+		fsType := MountType(path)
+		rate := rates[fsType]
+		if rate <= 0 {
+			return nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		// One limiter per mount type so the rate holds across a whole batch
+		l, ok := limiters[fsType]
+		if !ok {
+			l = NewRateLimiter(rate)
+			limiters[fsType] = l
+		}
+		return l
+	}
+
+	return func(path string) error {
+		l := limiterFor(path)
+		if l == nil {
+			return RemovePath(path)
+		}
+		if IsProtectedPath(path) {
+			return types.NewPathError("remove", path, types.ErrProtectedPath)
+		}
+		if _, err := os.Lstat(path); err != nil {
+			return types.NewPathError("remove", path, err)
+		}
+		if err := removeTree(path, l.Wait); err != nil {
+			return types.NewPathError("remove", path, err)
+		}
+		return nil
+	}
+}
+
+// removeTree removes path and everything under it, children first, calling
+// wait before each removal
+func removeTree(path string, wait func()) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := removeTree(filepath.Join(path, entry.Name()), wait); err != nil {
+				return err
+			}
+		}
+	}
+
+	wait()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -5,20 +5,50 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/dustin/go-humanize"
+	"github.com/mattn/go-runewidth"
 	"github.com/rahulvramesh/cleanWithCli/internal/types"
 )
 
 // GetDirSize calculates the total size of a directory
 func GetDirSize(path string) (int64, error) {
+	return DirSize(path, nil)
+}
+
+// WalkCounts tallies the directories and files a scan visits. A nil
+// WalkCounts counts nothing.
+type WalkCounts struct {
+	Dirs  atomic.Int64
+	Files atomic.Int64
+}
+
+// Add counts one visited entry
+func (c *WalkCounts) Add(isDir bool) {
+	if c == nil {
+		return
+	}
+	if isDir {
+		c.Dirs.Add(1)
+	} else {
+		c.Files.Add(1)
+	}
+}
+
+// DirSize calculates the total size of a directory, counting what it visits
+func DirSize(path string, counts *WalkCounts) (int64, error) {
 	var size int64
 	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip files we can't access
 		}
+		counts.Add(info.IsDir())
 		if !info.IsDir() {
 			size += info.Size()
 		}
@@ -37,12 +67,159 @@ func GetSortedCategories(results map[string]*types.ScanResult) []string {
 	return categories
 }
 
-// TruncatePath truncates a path if it's too long
+// TruncatePath truncates a path to at most maxLen terminal columns
 func TruncatePath(path string, maxLen int) string {
-	if len(path) <= maxLen {
+	if runewidth.StringWidth(path) <= maxLen {
 		return path
 	}
-	return path[:maxLen-3] + "..."
+	if maxLen <= 3 {
+		return runewidth.Truncate(path, maxLen, "")
+	}
+	return runewidth.Truncate(path, maxLen, "...")
+}
+
+// GlobMatcher compiles a shell-style pattern into a case-insensitive
+// matcher in which "*" also matches path separators, e.g. "*/archive/*"
+func GlobMatcher(pattern string) func(s string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?i)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+
+	re := regexp.MustCompile(expr.String())
+	return re.MatchString
+}
+
+// DisplayPath formats path for display, abbreviating the home directory to
+// "~" unless absolute is set
+func DisplayPath(path, home string, absolute bool) string {
+	if absolute || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
+
+// TruncateMiddle shortens a path to maxLen columns by eliding its middle,
+// keeping the root segment, as many trailing segments as fit and the file
+// extension, e.g. "Users/…/project/node_modules". When not even the last
+// segment fits, it is shortened after the root: "/Users/…/a-very….dmg"
+func TruncateMiddle(path string, maxLen int) string {
+	if runewidth.StringWidth(path) <= maxLen {
+		return path
+	}
+
+	const ellipsis = "…"
+	sep := string(filepath.Separator)
+	segments := strings.Split(path, sep)
+
+	// Keep the first named segment, including a leading separator
+	head := segments[0]
+	rest := segments[1:]
+	if head == "" && len(rest) > 0 {
+		head = sep + rest[0]
+		rest = rest[1:]
+	}
+
+	// Add trailing segments while the result still fits
+	tail := ""
+	for i := len(rest) - 1; i >= 0; i-- {
+		candidate := rest[i]
+		if tail != "" {
+			candidate += sep + tail
+		}
+		if runewidth.StringWidth(head+sep+ellipsis+sep+candidate) > maxLen {
+			break
+		}
+		tail = candidate
+	}
+	if tail != "" {
+		return head + sep + ellipsis + sep + tail
+	}
+
+	// Not even the last segment fits: keep the root and shorten the name
+	// itself, unless the root leaves too little room to tell the name apart
+	name := segments[len(segments)-1]
+	if len(rest) > 0 {
+		prefix := head + sep
+		if len(rest) > 1 {
+			prefix += ellipsis + sep
+		}
+		budget := maxLen - runewidth.StringWidth(prefix)
+		if budget >= minNameWidth+runewidth.StringWidth(filepath.Ext(name)) {
+			return prefix + shortenName(name, budget)
+		}
+	}
+	return shortenName(name, maxLen)
+}
+
+// minNameWidth is the fewest columns of a shortened name, before its
+// extension, worth keeping the root segment for
+const minNameWidth = 4
+
+// shortenName shortens a file name to maxLen columns around its extension
+func shortenName(name string, maxLen int) string {
+	ext := filepath.Ext(name)
+	if ext == name || runewidth.StringWidth(ext)+2 > maxLen {
+		ext = ""
+	}
+	base := strings.TrimSuffix(name, ext)
+	budget := maxLen - runewidth.StringWidth(ext) - 1
+	if budget <= 0 {
+		return TruncatePath(name, maxLen)
+	}
+	return runewidth.Truncate(base, budget, "") + "…" + ext
+}
+
+// PadRight pads s with spaces to width terminal columns
+func PadRight(s string, width int) string {
+	return runewidth.FillRight(s, width)
+}
+
+// SanitizeName makes a file name or path safe to render in the terminal by
+// escaping control characters and replacing invalid UTF-8
+func SanitizeName(name string) string {
+	clean := true
+	for _, r := range name {
+		if r == utf8.RuneError || unicode.IsControl(r) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return name
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToValidUTF8(name, "\uFFFD") {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
 }
 
 // FormatFileSize formats file size using humanize
@@ -81,6 +258,62 @@ func ShouldSkipDir(path string) bool {
 	return false
 }
 
+// HasPathPrefix reports whether path lies inside any of the given directories
+func HasPathPrefix(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProtectedPath reports whether path is a system or home root that must
+// never be removed as a whole
+func IsProtectedPath(path string) bool {
+	clean := filepath.Clean(path)
+	// Refuse relative paths and anything with "..", "." or doubled separators
+	if !filepath.IsAbs(clean) || clean != path {
+		return true
+	}
+
+	protected := []string{
+		"/", "/System", "/Library", "/Applications", "/Users",
+		"/usr", "/bin", "/sbin", "/etc", "/var", "/private",
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		protected = append(protected,
+			home,
+			filepath.Join(home, "Library"),
+			filepath.Join(home, "Documents"),
+			filepath.Join(home, "Desktop"),
+			filepath.Join(home, "Downloads"),
+		)
+	}
+
+	for _, p := range protected {
+		if clean == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RemovePath deletes path and everything below it, returning a typed
+// *types.PathError when the removal is refused or fails
+func RemovePath(path string) error {
+	if IsProtectedPath(path) {
+		return types.NewPathError("remove", path, types.ErrProtectedPath)
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return types.NewPathError("remove", path, err)
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return types.NewPathError("remove", path, err)
+	}
+	return nil
+}
+
 // WalkDirWithProgress walks a directory and sends progress updates
 func WalkDirWithProgress(root string, progressChan chan<- types.ScanProgressMsg, fn func(path string, d fs.DirEntry, err error) error) error {
 	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+)
+
+func TestRemovePath(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "cache")
+	if err := os.MkdirAll(filepath.Join(existing, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{"removes existing directory", existing, nil},
+		{"missing path", filepath.Join(dir, "missing"), types.ErrNotFound},
+		{"root is protected", "/", types.ErrProtectedPath},
+		{"relative path is protected", "cache", types.ErrProtectedPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RemovePath(tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RemovePath(%q) = %v, want %v", tt.path, err, tt.wantErr)
+			}
+
+			var pathErr *types.PathError
+			if err != nil && !errors.As(err, &pathErr) {
+				t.Errorf("error %v is not a *types.PathError", err)
+			}
+		})
+	}
+
+	if _, err := os.Stat(existing); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, stat err = %v", existing, err)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "node_modules", "node_modules"},
+		{"unicode kept", "プロジェクト 📦", "プロジェクト 📦"},
+		{"newline", "evil\nname", `evil\nname`},
+		{"carriage return and tab", "a\rb\tc", `a\rb\tc`},
+		{"escape sequence", "\x1b[2Jclear", `\x1b[2Jclear`},
+		{"invalid utf-8", "bad\xffbyte", "bad�byte"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeName(tt.in); got != tt.want {
+				t.Errorf("SanitizeName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncatePath(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"short", 10, "short"},
+		{"exactly-ten", 11, "exactly-ten"},
+		{"a-long-path-name", 10, "a-long-..."},
+		{"日本語のファイル名", 9, "日本語..."},
+		{"abcdef", 2, "ab"},
+		{"abcdef", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := TruncatePath(tt.in, tt.maxLen); got != tt.want {
+			t.Errorf("TruncatePath(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+func TestRemovePathUnusualNames(t *testing.T) {
+	dir := t.TempDir()
+
+	// A name with a newline and control characters
+	odd := filepath.Join(dir, "line\nbreak\x07")
+	if err := os.WriteFile(odd, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A deeply nested tree
+	deep := filepath.Join(dir, "deep")
+	nested := deep
+	for i := 0; i < 100; i++ {
+		nested = filepath.Join(nested, "level")
+	}
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{odd, deep} {
+		if err := RemovePath(path); err != nil {
+			t.Errorf("RemovePath(%q) = %v", path, err)
+		}
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("%q still exists", path)
+		}
+	}
+
+	// Paths that are not in clean form are refused
+	if err := RemovePath(dir + "/deep/../deep"); !errors.Is(err, types.ErrProtectedPath) {
+		t.Errorf("RemovePath with .. = %v, want ErrProtectedPath", err)
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"/Users/dev/code/app", 40, "/Users/dev/code/app"},
+		{"/Users/dev/code/some/deep/project/node_modules", 30, "/Users/…/project/node_modules"},
+		{"📦 code/clients/acme/website-redesign", 30, "📦 code/…/website-redesign"},
+		{"/Users/dev/Downloads/a-very-long-installer-name-v1.2.3.dmg", 20, "/Users/…/a-very….dmg"},
+		{"/Users/a-very-long-installer-name-v1.2.3.dmg", 20, "/Users/a-very-l….dmg"},
+		{"/Users/dev/Downloads/a-very-long-installer-name-v1.2.3.dmg", 12, "a-very-….dmg"},
+		{"no-separators-at-all-here.tar", 12, "no-sepa….tar"},
+		{"/ab", 1, "a"},
+	}
+
+	for _, tt := range tests {
+		got := TruncateMiddle(tt.in, tt.maxLen)
+		if got != tt.want {
+			t.Errorf("TruncateMiddle(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+		}
+		if w := runewidth.StringWidth(got); w > tt.maxLen {
+			t.Errorf("TruncateMiddle(%q, %d) is %d columns wide", tt.in, tt.maxLen, w)
+		}
+	}
+}
+
+func TestDisplayPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		absolute bool
+		want     string
+	}{
+		{"/Users/dev/Library/Caches", false, "~/Library/Caches"},
+		{"/Users/dev", false, "~"},
+		{"/Users/developer/code", false, "/Users/developer/code"},
+		{"/Library/Caches", false, "/Library/Caches"},
+		{"/Users/dev/Library/Caches", true, "/Users/dev/Library/Caches"},
+	}
+
+	for _, tt := range tests {
+		if got := DisplayPath(tt.path, "/Users/dev", tt.absolute); got != tt.want {
+			t.Errorf("DisplayPath(%q, absolute=%v) = %q, want %q", tt.path, tt.absolute, got, tt.want)
+		}
+	}
+}
+
+func TestGlobMatcher(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"*old*", "my-OLD-project", true},
+		{"*old*", "new-project", false},
+		{"*/archive/*", "/Users/dev/archive/2019/site", true},
+		{"*/archive/*", "/Users/dev/archives", false},
+		{"*.log", "install.log", true},
+		{"v?.?", "v1.2", true},
+		{"a+b(c)", "a+b(c)", true},
+	}
+
+	for _, tt := range tests {
+		if got := GlobMatcher(tt.pattern)(tt.s); got != tt.want {
+			t.Errorf("GlobMatcher(%q)(%q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseDeleteRates(t *testing.T) {
+	rates, err := ParseDeleteRates("nfs=100, SMBFS=0,fuse=2.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{"nfs": 100, "smbfs": 0, "fuse": 2.5, "afpfs": 20, "webdav": 10}
+	for fsType, rate := range want {
+		if rates[fsType] != rate {
+			t.Errorf("rates[%q] = %v, want %v", fsType, rates[fsType], rate)
+		}
+	}
+
+	for _, spec := range []string{"nfs", "=5", "nfs=fast", "nfs=-1"} {
+		if _, err := ParseDeleteRates(spec); err == nil {
+			t.Errorf("ParseDeleteRates(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestRemoveTreeWaitsPerEntry(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "build")
+	for _, p := range []string{"a/one", "a/two", "b/three"} {
+		full := filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waits := 0
+	if err := removeTree(root, func() { waits++ }); err != nil {
+		t.Fatal(err)
+	}
+	// Three files, two subdirectories and the root itself
+	if waits != 6 {
+		t.Errorf("waits = %d, want 6", waits)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, stat err = %v", root, err)
+	}
+}
+
+func TestMounts(t *testing.T) {
+	mounts, err := Mounts()
+	if err != nil {
+		t.Skipf("Mounts: %v", err)
+	}
+	for _, m := range mounts {
+		if m.Path == "/" {
+			if m.Total <= 0 || m.Used+m.Free > m.Total || m.Capacity() < 0 || m.Capacity() > 100 {
+				t.Errorf("root mount = %+v, capacity %d%%", m, m.Capacity())
+			}
+			return
+		}
+	}
+	t.Errorf("no root mount in %+v", mounts)
+}
+
+func TestMountCapacity(t *testing.T) {
+	// Like df, capacity counts only space unprivileged users can use and
+	// rounds up
+	m := Mount{Total: 1000, Used: 301, Free: 600}
+	if got := m.Capacity(); got != 34 {
+		t.Errorf("Capacity = %d, want 34", got)
+	}
+	if got := (Mount{}).Capacity(); got != 0 {
+		t.Errorf("empty Capacity = %d, want 0", got)
+	}
+}
+
+func TestAllocatedSizeSparse(t *testing.T) {
+	dir := t.TempDir()
+	disk := filepath.Join(dir, "diffdisk")
+	f, err := os.Create(disk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(make([]byte, 700)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(1 << 30); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	apparent, _ := DirSize(dir, nil)
+	allocated, err := AllocatedSize(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apparent != 1<<30 {
+		t.Errorf("DirSize = %d, want the file's length", apparent)
+	}
+	if allocated >= apparent || allocated < 700 {
+		t.Errorf("AllocatedSize = %d, want the written part of %d", allocated, apparent)
+	}
+}
@@ -0,0 +1,62 @@
+// Package watch monitors free disk space and calls back when it drops
+// below a threshold
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// Watcher samples the free space on the volume holding Path every Interval.
+// OnLow runs when free space first drops below Threshold, and again every
+// Cooldown while it stays low. It runs again right away after free space
+// recovers and drops once more.
+type Watcher struct {
+	Path      string
+	Threshold int64
+	Interval  time.Duration
+	Cooldown  time.Duration
+	OnLow     func(free int64)
+
+	// diskSpace defaults to utils.DiskSpace; tests replace it
+	diskSpace func(path string) (free, total int64, err error)
+	lastFired time.Time
+	low       bool
+}
+
+// check records a sample and reports whether OnLow should run
+func (w *Watcher) check(free int64, now time.Time) bool {
+	if free >= w.Threshold {
+		w.low = false
+		return false
+	}
+	if w.low && now.Sub(w.lastFired) < w.Cooldown {
+		return false
+	}
+	w.low = true
+	w.lastFired = now
+	return true
+}
+
+// Run samples free space until ctx is done. Failed samples are skipped.
+func (w *Watcher) Run(ctx context.Context) error {
+	diskSpace := w.diskSpace
+	if diskSpace == nil {
+		diskSpace = utils.DiskSpace
+	}
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		if free, _, err := diskSpace(w.Path); err == nil && w.check(free, time.Now()) {
+			w.OnLow(free)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
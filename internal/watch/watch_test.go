@@ -0,0 +1,59 @@
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	w := &Watcher{Threshold: 100, Cooldown: time.Hour}
+	start := time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		free  int64
+		after time.Duration
+		want  bool
+	}{
+		{150, 0, false},
+		{90, time.Minute, true},                 // Dropped below
+		{80, 2 * time.Minute, false},            // Still low, cooling down
+		{80, time.Hour + time.Minute, true},     // Still low after the cooldown
+		{120, time.Hour + 2*time.Minute, false}, // Recovered
+		{90, time.Hour + 3*time.Minute, true},   // Dropped again
+	}
+	for i, step := range steps {
+		if got := w.check(step.free, start.Add(step.after)); got != step.want {
+			t.Errorf("step %d: check(%d) = %v, want %v", i, step.free, got, step.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	samples := []int64{200, 50}
+	var fired []int64
+
+	w := &Watcher{
+		Threshold: 100,
+		Interval:  time.Millisecond,
+		Cooldown:  time.Hour,
+		OnLow: func(free int64) {
+			fired = append(fired, free)
+			cancel()
+		},
+		diskSpace: func(string) (int64, int64, error) {
+			free := samples[0]
+			if len(samples) > 1 {
+				samples = samples[1:]
+			}
+			return free, 1000, nil
+		},
+	}
+	if err := w.Run(ctx); err != context.Canceled {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+	if len(fired) != 1 || fired[0] != 50 {
+		t.Errorf("OnLow calls = %v, want [50]", fired)
+	}
+}
@@ -0,0 +1,350 @@
+// Package cleaner is the scanning and cleaning engine behind mac-cleaner,
+// usable from other Go programs without the terminal UI.
+//
+//	report, err := cleaner.Scan(ctx, cleaner.Options{Mode: cleaner.ModeDev})
+//	if err != nil {
+//		return err
+//	}
+//	items := report.Results["Node Modules"].Items
+//	freed := cleaner.Clean(items, cleaner.RemoveAll)
+//
+// Some categories should not simply be deleted; ResultStrategy returns the
+// strategy a scanned category asks for, such as running `brew cleanup`.
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rahulvramesh/cleanWithCli/internal/scanner"
+	"github.com/rahulvramesh/cleanWithCli/internal/sizecache"
+	"github.com/rahulvramesh/cleanWithCli/internal/strategy"
+	"github.com/rahulvramesh/cleanWithCli/internal/types"
+	"github.com/rahulvramesh/cleanWithCli/internal/utils"
+)
+
+// Types shared with the scanner
+type (
+	// FileItem is a single file or directory found by a scan
+	FileItem = types.FileItem
+	// ScanResult holds the items found in one category
+	ScanResult = types.ScanResult
+	// PathError describes a failure on a single path
+	PathError = types.PathError
+	// RiskLevel rates how disruptive deleting a category is
+	RiskLevel = types.RiskLevel
+	// SizeCache remembers directory sizes between scans
+	SizeCache = sizecache.Cache
+	// ScanStats summarizes the time and memory a scan used
+	ScanStats = types.ScanStats
+	// ScannerStats is what running one scanner cost
+	ScannerStats = types.ScannerStats
+)
+
+// Error kinds for use with errors.Is
+var (
+	ErrPermission    = types.ErrPermission
+	ErrNotFound      = types.ErrNotFound
+	ErrInUse         = types.ErrInUse
+	ErrProtectedPath = types.ErrProtectedPath
+)
+
+// Mode selects which categories a scan covers
+type Mode string
+
+const (
+	// ModeFull scans system caches, logs, trash, downloads and common
+	// developer caches
+	ModeFull Mode = scanner.SetFull
+	// ModeDev deep-scans the home directory for development artifacts
+	ModeDev Mode = scanner.SetDev
+	// ModeQuick scans only safe categories: caches, trash and old logs
+	ModeQuick Mode = scanner.SetQuick
+)
+
+// ScanFunc scans a single category
+type ScanFunc func() *ScanResult
+
+// DefaultInstallerAge is the Options.InstallerAge used when it is unset
+const DefaultInstallerAge = scanner.DefaultInstallerAge
+
+// DefaultScreenshotAge is the Options.ScreenshotAge used when it is unset
+const DefaultScreenshotAge = scanner.DefaultScreenshotAge
+
+// Options configures a scan
+type Options struct {
+	Mode              Mode
+	HomeDir           string     // Defaults to the current user's home directory
+	RootDir           string     // Prefix for system-wide locations, defaults to "/"
+	Extra             []ScanFunc // Additional scanners, e.g. plugins
+	Disabled          []string   // Names of built-in scanners to skip, see Scanners
+	Roots             []string   // Folders cleaned of .DS_Store and AppleDouble files, defaults to the home folder and mounted volumes
+	InstallerAge      int        // Days before disk images and packages are listed as Installers, defaults to DefaultInstallerAge
+	ScreenshotAge     int        // Days before Desktop screenshots are listed, defaults to DefaultScreenshotAge
+	ScreenshotArchive string     // Folder old screenshots are moved into, defaults to the Trash
+	Sizes             *SizeCache // Reuses recently measured directory sizes, see OpenSizeCache
+	// Deletes the items of categories cleaned by deletion, such as a rate
+	// limited remover for network mounts. Results of categories without a
+	// strategy of their own get it as their Remover. Defaults to RemoveAll.
+	Remove func(path string) error
+}
+
+// OpenSizeCache loads the directory sizes cached at path, creating an empty
+// cache if there is none. Call Save on it after scanning to keep the sizes.
+func OpenSizeCache(path string) *SizeCache {
+	return sizecache.Open(path)
+}
+
+// Report is the outcome of a scan
+type Report struct {
+	Results   map[string]*ScanResult // Non-empty categories by name
+	TotalSize int64                  // Reclaimable bytes, excluding advisory categories
+	Stats     *ScanStats             // Time and files visited per scanner, including empty ones
+}
+
+// Scan runs the scanners for opts.Mode in parallel. Scanners can't be
+// interrupted, so when ctx is done Scan returns ctx.Err() right away and
+// lets them finish in the background.
+func Scan(ctx context.Context, opts Options) (*Report, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s := scanner.NewScanner()
+	if opts.HomeDir != "" {
+		s.HomeDir = opts.HomeDir
+	}
+	if opts.RootDir != "" {
+		s.RootDir = opts.RootDir
+	}
+	s.Sizes = opts.Sizes
+	s.Roots = opts.Roots
+	s.InstallerAge = opts.InstallerAge
+	s.ScreenshotAge = opts.ScreenshotAge
+	s.ScreenshotArchive = opts.ScreenshotArchive
+	s.Remove = opts.Remove
+	s.Disabled = make(map[string]bool, len(opts.Disabled))
+	for _, name := range opts.Disabled {
+		s.Disabled[name] = true
+	}
+
+	type namedScan struct {
+		name string
+		scan ScanFunc
+	}
+	var scans []namedScan
+	for _, sc := range s.Scanners(string(opts.Mode)) {
+		scans = append(scans, namedScan{sc.Name(), func() *ScanResult { return sc.Scan(ctx) }})
+	}
+	for _, scan := range opts.Extra {
+		scans = append(scans, namedScan{scan: scan})
+	}
+
+	started := time.Now()
+	stopSampling := sampleMemory()
+	report := &Report{Results: make(map[string]*ScanResult), Stats: &ScanStats{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, scan := range scans {
+		wg.Add(1)
+		go func(scan namedScan) {
+			defer wg.Done()
+
+			scanStarted := time.Now()
+			result := scan.scan()
+			stats := ScannerStats{
+				Name:     scan.name,
+				Category: result.Category,
+				Duration: time.Since(scanStarted),
+				Dirs:     result.DirsVisited,
+				Files:    result.FilesVisited,
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Stats.Scanners = append(report.Stats.Scanners, stats)
+			if result.Total <= 0 {
+				return
+			}
+			report.Results[result.Category] = result
+			if result.Remover == nil && !result.Advisory && opts.Remove != nil {
+				result.Remover = opts.Remove
+			}
+			if !result.Advisory {
+				report.TotalSize += result.Total
+			}
+		}(scan)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		report.Stats.Duration = time.Since(started)
+		report.Stats.PeakMemory = stopSampling()
+		sort.Slice(report.Stats.Scanners, func(i, j int) bool {
+			return report.Stats.Scanners[i].Duration > report.Stats.Scanners[j].Duration
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return report, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// memorySampleInterval is how often sampleMemory reads the heap size
+const memorySampleInterval = 50 * time.Millisecond
+
+// sampleMemory watches the heap in use until the returned function is
+// called, which returns the highest size seen
+func sampleMemory() func() uint64 {
+	var peak uint64
+	sample := func() {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		if ms.HeapInuse > peak {
+			peak = ms.HeapInuse
+		}
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		for {
+			sample()
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() uint64 {
+		close(stop)
+		<-stopped
+		sample()
+		return peak
+	}
+}
+
+// Strategy removes a single path
+type Strategy interface {
+	Remove(path string) error
+}
+
+// StrategyFunc adapts a function to a Strategy
+type StrategyFunc func(path string) error
+
+// Remove calls f(path)
+func (f StrategyFunc) Remove(path string) error {
+	return f(path)
+}
+
+var (
+	// RemoveAll deletes paths from disk, refusing protected locations
+	RemoveAll Strategy = StrategyFunc(utils.RemovePath)
+	// DryRun removes nothing and reports every path as removed
+	DryRun Strategy = dryRun{}
+)
+
+type dryRun struct{}
+
+func (dryRun) Remove(string) error { return nil }
+
+// MoveToTrash returns a strategy moving paths into home's Trash instead of
+// deleting them
+func MoveToTrash(home string) Strategy {
+	return strategy.MoveToTrash(home)
+}
+
+// ResultStrategy returns how a scanned category's items should be cleaned,
+// falling back to RemoveAll for categories without a strategy of their own
+func ResultStrategy(result *ScanResult) Strategy {
+	if result.Remover != nil {
+		return StrategyFunc(result.Remover)
+	}
+	return RemoveAll
+}
+
+// CleanReport is the outcome of a clean
+type CleanReport struct {
+	Freed   int64    // Bytes freed, including items that were already gone
+	Removed []string // Paths that are gone after the clean
+	Errors  []error  // One error per path that failed or was already gone
+}
+
+// Clean removes items with strategy in order. Items below an already
+// removed directory are skipped, and items that no longer exist count as
+// removed.
+func Clean(items []FileItem, strategy Strategy) CleanReport {
+	var report CleanReport
+	for _, item := range items {
+		// Children of an already removed directory went with it
+		if utils.HasPathPrefix(item.Path, report.Removed) {
+			continue
+		}
+		if err := strategy.Remove(item.Path); err != nil {
+			report.Errors = append(report.Errors, err)
+			// Items that vanished on their own are dropped from the list too
+			if !errors.Is(err, types.ErrNotFound) {
+				continue
+			}
+		}
+		if strategy == DryRun {
+			report.Freed += item.Size
+		} else {
+			report.Freed += Freed(item)
+		}
+		report.Removed = append(report.Removed, item.Path)
+	}
+	return report
+}
+
+// Freed returns the space cleaning item freed. An item still there after
+// its strategy succeeded was cleaned in place, e.g. by `pnpm store prune`,
+// so only what it shrank by counts.
+func Freed(item FileItem) int64 {
+	if _, err := os.Lstat(item.Path); err != nil {
+		return item.Size
+	}
+	remaining, _ := utils.DirSize(item.Path, nil)
+	return max(0, item.Size-remaining)
+}
+
+// ScannerInfo describes a built-in scanner
+type ScannerInfo struct {
+	Name     string // Identifier used in Options.Disabled
+	Category string
+	Risk     RiskLevel
+	Modes    []Mode
+}
+
+// Scanners lists the built-in scanners in their scan order
+func Scanners() []ScannerInfo {
+	var infos []ScannerInfo
+	for _, r := range scanner.Registrations() {
+		info := ScannerInfo{Name: r.Name, Category: r.Category, Risk: r.Risk}
+		for _, set := range r.Sets {
+			info.Modes = append(info.Modes, Mode(set))
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// CategoryRisk returns the risk of deleting everything in a category
+func CategoryRisk(category string) RiskLevel {
+	return scanner.CategoryRisk(category)
+}
@@ -0,0 +1,171 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeHome creates files relative to a temp home directory and returns it
+func fakeHome(t *testing.T, files map[string]int) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("GOPATH", filepath.Join(home, "go"))
+	t.Setenv("CARGO_HOME", filepath.Join(home, ".cargo"))
+	t.Setenv("GEM_HOME", filepath.Join(home, ".gem"))
+	t.Setenv("PYENV_ROOT", "")
+	t.Setenv("RBENV_ROOT", "")
+	t.Setenv("NVM_DIR", "")
+
+	for rel, size := range files {
+		path := filepath.Join(home, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return home
+}
+
+func TestScanAndClean(t *testing.T) {
+	home := fakeHome(t, map[string]int{
+		"code/web/package.json":                   10,
+		"code/web/node_modules/left-pad/index.js": 1000,
+		"code/api/node_modules/express/index.js":  500,
+	})
+
+	extra := func() *ScanResult {
+		return &ScanResult{
+			Category: "Extra",
+			Items:    []FileItem{{Path: "/nowhere", Size: 7}},
+			Total:    7,
+		}
+	}
+
+	report, err := Scan(context.Background(), Options{
+		Mode:    ModeDev,
+		HomeDir: home,
+		RootDir: filepath.Join(home, "root"),
+		Extra:   []ScanFunc{extra},
+		// Keep a local Docker daemon out of the totals
+		Disabled: []string{"docker", "time-machine"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalSize != 1507 {
+		t.Errorf("TotalSize = %d, want 1507", report.TotalSize)
+	}
+
+	// Every scanner is summarized, including those that found nothing
+	stats := make(map[string]ScannerStats)
+	for _, sc := range report.Stats.Scanners {
+		stats[sc.Category] = sc
+	}
+	if len(report.Stats.Scanners) <= len(report.Results) {
+		t.Errorf("Stats.Scanners = %+v, want empty categories too", report.Stats.Scanners)
+	}
+	if nm := stats["Node Modules"]; nm.Name != "node-modules" || nm.Dirs == 0 || nm.Files == 0 {
+		t.Errorf("Node Modules stats = %+v, want visited dirs and files", nm)
+	}
+	if ex, ok := stats["Extra"]; !ok || ex.Name != "" {
+		t.Errorf("Extra stats = %+v, %v", ex, ok)
+	}
+	if report.Stats.Duration <= 0 || report.Stats.PeakMemory == 0 {
+		t.Errorf("Stats = %+v, want a duration and peak memory", report.Stats)
+	}
+	nodeModules := report.Results["Node Modules"]
+	if nodeModules == nil || len(nodeModules.Items) != 2 {
+		t.Fatalf("Node Modules = %+v, want 2 items", nodeModules)
+	}
+
+	if dry := Clean(nodeModules.Items, DryRun); dry.Freed != 1500 || len(dry.Removed) != 2 {
+		t.Errorf("DryRun clean = %+v", dry)
+	}
+	if _, err := os.Stat(nodeModules.Items[0].Path); err != nil {
+		t.Fatalf("DryRun removed %s: %v", nodeModules.Items[0].Path, err)
+	}
+
+	// A missing item counts as removed, and children of removed items are skipped
+	items := append(nodeModules.Items,
+		FileItem{Path: filepath.Join(home, "code/web/node_modules/left-pad"), Size: 1000},
+		FileItem{Path: filepath.Join(home, "missing"), Size: 3},
+	)
+	done := Clean(items, RemoveAll)
+	if done.Freed != 1503 || len(done.Removed) != 3 {
+		t.Errorf("clean = %+v, want 1503 bytes freed from 3 paths", done)
+	}
+	if len(done.Errors) != 1 || !errors.Is(done.Errors[0], ErrNotFound) {
+		t.Errorf("errors = %v, want one not-found error", done.Errors)
+	}
+	for _, item := range nodeModules.Items {
+		if _, err := os.Stat(item.Path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", item.Path)
+		}
+	}
+}
+
+func TestScanRemoveOption(t *testing.T) {
+	home := fakeHome(t, map[string]int{
+		"code/web/package.json":                   10,
+		"code/web/node_modules/left-pad/index.js": 1000,
+	})
+
+	var removed []string
+	report, err := Scan(context.Background(), Options{
+		Mode:     ModeDev,
+		HomeDir:  home,
+		RootDir:  filepath.Join(home, "root"),
+		Disabled: []string{"docker", "time-machine"},
+		Remove: func(path string) error {
+			removed = append(removed, path)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeModules := report.Results["Node Modules"]
+	if nodeModules == nil || len(nodeModules.Items) != 1 {
+		t.Fatalf("Node Modules = %+v, want 1 item", nodeModules)
+	}
+
+	// Categories without a strategy of their own delete through Remove
+	path := nodeModules.Items[0].Path
+	Clean(nodeModules.Items, ResultStrategy(nodeModules))
+	if len(removed) != 1 || removed[0] != path {
+		t.Errorf("Remove got %v, want %s", removed, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("%s was deleted without Remove: %v", path, err)
+	}
+}
+
+func TestScanCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Scan(ctx, Options{HomeDir: t.TempDir()}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Scan(cancelled) = %v, want context.Canceled", err)
+	}
+}
+
+func TestCleanInPlace(t *testing.T) {
+	home := fakeHome(t, map[string]int{
+		".pnpm-store/v3/files/00/used":   300,
+		".pnpm-store/v3/files/01/unused": 700,
+	})
+	store := filepath.Join(home, ".pnpm-store")
+	// Stands in for `pnpm store prune`, which only removes unused packages
+	prune := StrategyFunc(func(path string) error {
+		return os.Remove(filepath.Join(path, "v3/files/01/unused"))
+	})
+
+	report := Clean([]FileItem{{Path: store, Size: 1000}}, prune)
+	if report.Freed != 700 || len(report.Removed) != 1 || len(report.Errors) != 0 {
+		t.Errorf("clean = %+v, want only the pruned 700 bytes freed", report)
+	}
+}